@@ -5,10 +5,12 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"code.cloudfoundry.org/lager"
 	. "github.com/alphagov/paas-aiven-broker/broker"
 	broker_tester "github.com/alphagov/paas-aiven-broker/broker/testing"
+	"github.com/alphagov/paas-aiven-broker/provider"
 	"github.com/alphagov/paas-aiven-broker/provider/fakes"
 	"github.com/pivotal-cf/brokerapi"
 
@@ -92,6 +94,112 @@ var _ = Describe("Broker API", func() {
 			Expect(len(catalogResponse.Services[0].Plans)).To(Equal(1))
 			Expect(catalogResponse.Services[0].Plans[0].ID).To(Equal(plan1))
 		})
+
+		It("serves every service offering in the config", func() {
+			config, err := NewConfig(strings.NewReader(`
+				{
+					"basic_auth_username": "username",
+					"basic_auth_password": "password",
+					"catalog": {"services": [
+						{
+							"id": "uuid-elasticsearch",
+							"name": "aiven-elasticsearch",
+							"description": "Elasticsearch instances provisioned via Aiven",
+							"metadata": {"displayName": "Aiven Elasticsearch"},
+							"plans": [{"id": "uuid-elasticsearch-plan", "name": "small", "service_type": "elasticsearch"}]
+						},
+						{
+							"id": "uuid-influxdb",
+							"name": "aiven-influxdb",
+							"description": "InfluxDB instances provisioned via Aiven",
+							"metadata": {"displayName": "Aiven InfluxDB"},
+							"plans": [{"id": "uuid-influxdb-plan", "name": "small", "service_type": "influxdb"}]
+						},
+						{
+							"id": "uuid-kafka",
+							"name": "aiven-kafka",
+							"description": "Kafka instances provisioned via Aiven",
+							"metadata": {"displayName": "Aiven Kafka"},
+							"plans": [{"id": "uuid-kafka-plan", "name": "small", "service_type": "kafka"}]
+						}
+					]}
+				}
+			`))
+			Expect(err).NotTo(HaveOccurred())
+			brokerTester = broker_tester.New(brokerapi.BrokerCredentials{
+				Username: "username",
+				Password: "password",
+			}, NewAPI(New(config, fakeProvider, logger), logger, config))
+
+			res := brokerTester.Services()
+			Expect(res.Code).To(Equal(http.StatusOK))
+
+			catalogResponse := brokerapi.CatalogResponse{}
+			err = json.Unmarshal(res.Body.Bytes(), &catalogResponse)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(catalogResponse.Services).To(HaveLen(3))
+			for i, name := range []string{"elasticsearch", "influxdb", "kafka"} {
+				service := catalogResponse.Services[i]
+				Expect(service.ID).To(Equal("uuid-" + name))
+				Expect(service.Name).To(Equal("aiven-" + name))
+				Expect(service.Description).To(ContainSubstring("instances provisioned via Aiven"))
+				Expect(service.Metadata.DisplayName).To(HavePrefix("Aiven "))
+				Expect(service.Plans).To(HaveLen(1))
+				Expect(service.Plans[0].ID).To(Equal("uuid-" + name + "-plan"))
+			}
+		})
+	})
+
+	Describe("Plan metadata", func() {
+		It("serves the plan metadata from the config, including unknown keys", func() {
+			config, err := NewConfig(strings.NewReader(`
+				{
+					"basic_auth_username": "username",
+					"basic_auth_password": "password",
+					"catalog": {"services": [{
+						"id": "uuid-elasticsearch",
+						"name": "elasticsearch",
+						"plans": [{
+							"id": "uuid-elasticsearch-plan",
+							"name": "small",
+							"aiven_plan": "startup-1",
+							"metadata": {
+								"displayName": "Small",
+								"bullets": ["1 CPU", "1 GB RAM"],
+								"costs": [{"amount": {"gbp": 12.5}, "unit": "MONTHLY"}],
+								"free": false
+							}
+						}]
+					}]}
+				}
+			`))
+			Expect(err).NotTo(HaveOccurred())
+			brokerTester = broker_tester.New(brokerapi.BrokerCredentials{
+				Username: "username",
+				Password: "password",
+			}, NewAPI(New(config, fakeProvider, logger), logger, config))
+
+			res := brokerTester.Services()
+			Expect(res.Code).To(Equal(http.StatusOK))
+
+			var catalogResponse struct {
+				Services []struct {
+					Plans []struct {
+						Metadata json.RawMessage `json:"metadata"`
+					} `json:"plans"`
+				} `json:"services"`
+			}
+			err = json.Unmarshal(res.Body.Bytes(), &catalogResponse)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(catalogResponse.Services[0].Plans[0].Metadata)).To(MatchJSON(`{
+				"displayName": "Small",
+				"bullets": ["1 CPU", "1 GB RAM"],
+				"costs": [{"amount": {"gbp": 12.5}, "unit": "MONTHLY"}],
+				"free": false
+			}`))
+		})
 	})
 
 	Describe("Provision", func() {
@@ -154,13 +262,13 @@ var _ = Describe("Broker API", func() {
 		It("accepts a deprovision request", func() {
 			fakeProvider.DeprovisionReturns("operationData", nil)
 			res := brokerTester.Deprovision(instanceID, service1, plan1, true)
-			Expect(res.Code).To(Equal(http.StatusOK))
+			Expect(res.Code).To(Equal(http.StatusAccepted))
 
 			deprovisionResponse := brokerapi.DeprovisionResponse{}
 			err := json.Unmarshal(res.Body.Bytes(), &deprovisionResponse)
 			Expect(err).NotTo(HaveOccurred())
 
-			expectedResponse := brokerapi.DeprovisionResponse{}
+			expectedResponse := brokerapi.DeprovisionResponse{OperationData: "operationData"}
 			Expect(deprovisionResponse).To(Equal(expectedResponse))
 		})
 
@@ -192,6 +300,7 @@ var _ = Describe("Broker API", func() {
 					PlanID:    plan1,
 					AppGUID:   appGUID,
 				},
+				false,
 			)
 			Expect(res.Code).To(Equal(http.StatusCreated))
 
@@ -215,11 +324,74 @@ var _ = Describe("Broker API", func() {
 					PlanID:    plan1,
 					AppGUID:   appGUID,
 				},
+				false,
 			)
 			Expect(res.Code).To(Equal(http.StatusInternalServerError))
 		})
 	})
 
+	Describe("Asynchronous Bind", func() {
+		var bindingID string
+
+		BeforeEach(func() {
+			bindingID = "bindingID"
+		})
+
+		It("accepts a binding request", func() {
+			fakeProvider.BindReturns(brokerapi.Binding{IsAsync: true, OperationData: "bind"}, nil)
+			res := brokerTester.Bind(
+				instanceID,
+				bindingID,
+				broker_tester.RequestBody{
+					ServiceID: service1,
+					PlanID:    plan1,
+					AppGUID:   "appGUID",
+				},
+				true,
+			)
+			Expect(res.Code).To(Equal(http.StatusAccepted))
+			Expect(res.Body.String()).To(MatchJSON(`{"operation": "bind"}`))
+
+			_, bindData := fakeProvider.BindArgsForCall(0)
+			Expect(bindData.AsyncAllowed).To(BeTrue())
+		})
+
+		It("provides the state of the binding operation", func() {
+			fakeProvider.LastBindingOperationReturns(brokerapi.InProgress, "description", nil)
+			res := brokerTester.LastBindingOperation(instanceID, bindingID, service1, plan1, "bind")
+			Expect(res.Code).To(Equal(http.StatusOK))
+			Expect(res.Body.String()).To(MatchJSON(`{"state": "in progress", "description": "description"}`))
+
+			_, lastBindingOperationData := fakeProvider.LastBindingOperationArgsForCall(0)
+			Expect(lastBindingOperationData).To(Equal(provider.LastBindingOperationData{
+				InstanceID:    instanceID,
+				BindingID:     bindingID,
+				ServiceID:     service1,
+				PlanID:        plan1,
+				OperationData: "bind",
+			}))
+		})
+
+		It("provides the credentials of the binding", func() {
+			fakeProvider.GetBindingReturns(brokerapi.GetBindingSpec{Credentials: "secrets"}, nil)
+			res := brokerTester.GetBinding(instanceID, bindingID)
+			Expect(res.Code).To(Equal(http.StatusOK))
+			Expect(res.Body.String()).To(MatchJSON(`{"credentials": "secrets"}`))
+
+			_, getBindingData := fakeProvider.GetBindingArgsForCall(0)
+			Expect(getBindingData).To(Equal(provider.GetBindingData{
+				InstanceID: instanceID,
+				BindingID:  bindingID,
+			}))
+		})
+
+		It("responds with not found if the binding does not exist", func() {
+			fakeProvider.GetBindingReturns(brokerapi.GetBindingSpec{}, brokerapi.ErrBindingNotFound)
+			res := brokerTester.GetBinding(instanceID, bindingID)
+			Expect(res.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
 	Describe("Unbind", func() {
 		var bindingID string
 
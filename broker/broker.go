@@ -2,7 +2,10 @@ package broker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -11,9 +14,10 @@ import (
 )
 
 type Broker struct {
-	config   Config
-	Provider provider.ServiceProvider
-	logger   lager.Logger
+	config     Config
+	configLock sync.RWMutex
+	Provider   provider.ServiceProvider
+	logger     lager.Logger
 }
 
 func New(config Config, serviceProvider provider.ServiceProvider, logger lager.Logger) *Broker {
@@ -24,22 +28,72 @@ func New(config Config, serviceProvider provider.ServiceProvider, logger lager.L
 	}
 }
 
-func (b *Broker) GetBinding(ctx context.Context, first, second string) (brokerapi.GetBindingSpec, error) {
-	return brokerapi.GetBindingSpec{}, fmt.Errorf("GetBinding method not implemented")
+// SetConfig replaces the config, for example when it is reloaded. Requests
+// which have already started carry on with the config they started with.
+func (b *Broker) SetConfig(config Config) {
+	b.configLock.Lock()
+	defer b.configLock.Unlock()
+	b.config = config
+}
+
+func (b *Broker) currentConfig() Config {
+	b.configLock.RLock()
+	defer b.configLock.RUnlock()
+	return b.config
 }
 
 func (b *Broker) GetInstance(ctx context.Context, first string) (brokerapi.GetInstanceDetailsSpec, error) {
 	return brokerapi.GetInstanceDetailsSpec{}, fmt.Errorf("GetInstance method not implemented")
 }
 
-func (b *Broker) LastBindingOperation(ctx context.Context, first, second string, pollDetails brokerapi.PollDetails) (brokerapi.LastOperation, error) {
-	return brokerapi.LastOperation{}, fmt.Errorf("LastBindingOperation method not implemented")
+func (b *Broker) Services(ctx context.Context) ([]brokerapi.Service, error) {
+	config := b.currentConfig()
+	services := []brokerapi.Service{}
+	for _, service := range config.Catalog.Catalog.Services {
+		plans := []brokerapi.ServicePlan{}
+		for _, plan := range service.Plans {
+			options := config.Plans[plan.ID]
+			if options.Deprecated {
+				if options.HideWhenDeprecated {
+					continue
+				}
+				plan.Metadata = deprecatedPlanMetadata(plan.Metadata)
+			}
+			plans = append(plans, plan)
+		}
+		if len(plans) == 0 {
+			continue
+		}
+		service.Plans = plans
+		services = append(services, service)
+	}
+	return services, nil
 }
 
-func (b *Broker) Services(ctx context.Context) ([]brokerapi.Service, error) {
-	return b.config.Catalog.Catalog.Services, nil
+// deprecatedPlanMetadata flags a deprecated plan in its metadata. The plan
+// stays bindable, as existing instances on it can still be bound. The
+// metadata is copied, so that the catalog in the config is left as it is.
+func deprecatedPlanMetadata(metadata *brokerapi.ServicePlanMetadata) *brokerapi.ServicePlanMetadata {
+	flagged := brokerapi.ServicePlanMetadata{}
+	if metadata != nil {
+		flagged = *metadata
+	}
+	flagged.AdditionalMetadata = map[string]interface{}{}
+	if metadata != nil {
+		for key, value := range metadata.AdditionalMetadata {
+			flagged.AdditionalMetadata[key] = value
+		}
+	}
+	flagged.AdditionalMetadata["deprecated"] = true
+	return &flagged
 }
 
+var errPlanDeprecated = brokerapi.NewFailureResponse(
+	errors.New("plan is no longer available for new instances"),
+	http.StatusBadRequest,
+	"plan-deprecated",
+)
+
 func (b *Broker) Provision(
 	ctx context.Context,
 	instanceID string,
@@ -56,7 +110,8 @@ func (b *Broker) Provision(
 		return brokerapi.ProvisionedServiceSpec{}, brokerapi.ErrAsyncRequired
 	}
 
-	service, err := findServiceByID(b.config.Catalog, details.ServiceID)
+	config := b.currentConfig()
+	service, err := findServiceByID(config.Catalog, details.ServiceID)
 	if err != nil {
 		return brokerapi.ProvisionedServiceSpec{}, err
 	}
@@ -66,6 +121,14 @@ func (b *Broker) Provision(
 		return brokerapi.ProvisionedServiceSpec{}, err
 	}
 
+	if config.Plans[plan.ID].Deprecated {
+		return brokerapi.ProvisionedServiceSpec{}, errPlanDeprecated
+	}
+
+	if err := checkMaintenanceInfo(plan, details.MaintenanceInfo); err != nil {
+		return brokerapi.ProvisionedServiceSpec{}, err
+	}
+
 	providerCtx, cancelFunc := context.WithTimeout(ctx, 30*time.Second)
 	defer cancelFunc()
 
@@ -113,7 +176,8 @@ func (b *Broker) Deprovision(
 	providerCtx, cancelFunc := context.WithTimeout(ctx, 30*time.Second)
 	defer cancelFunc()
 
-	service, err := findServiceByID(b.config.Catalog, details.ServiceID)
+	config := b.currentConfig()
+	service, err := findServiceByID(config.Catalog, details.ServiceID)
 	if err != nil {
 		return brokerapi.DeprovisionServiceSpec{}, err
 	}
@@ -142,7 +206,7 @@ func (b *Broker) Deprovision(
 	})
 
 	return brokerapi.DeprovisionServiceSpec{
-		IsAsync:       false,
+		IsAsync:       true,
 		OperationData: operationData,
 	}, nil
 }
@@ -154,18 +218,20 @@ func (b *Broker) Bind(
 	asyncAllowed bool,
 ) (brokerapi.Binding, error) {
 	b.logger.Debug("binding-start", lager.Data{
-		"instance-id": instanceID,
-		"binding-id":  bindingID,
-		"details":     details,
+		"instance-id":   instanceID,
+		"binding-id":    bindingID,
+		"details":       details,
+		"async-allowed": asyncAllowed,
 	})
 
 	providerCtx, cancelFunc := context.WithTimeout(ctx, 30*time.Second)
 	defer cancelFunc()
 
 	bindData := provider.BindData{
-		InstanceID: instanceID,
-		BindingID:  bindingID,
-		Details:    details,
+		InstanceID:   instanceID,
+		BindingID:    bindingID,
+		Details:      details,
+		AsyncAllowed: asyncAllowed,
 	}
 
 	binding, err := b.Provider.Bind(providerCtx, bindData)
@@ -217,6 +283,75 @@ func (b *Broker) Unbind(
 	return brokerapi.UnbindSpec{}, nil
 }
 
+func (b *Broker) GetBinding(
+	ctx context.Context,
+	instanceID, bindingID string,
+) (brokerapi.GetBindingSpec, error) {
+	b.logger.Debug("get-binding-start", lager.Data{
+		"instance-id": instanceID,
+		"binding-id":  bindingID,
+	})
+
+	providerCtx, cancelFunc := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelFunc()
+
+	getBindingData := provider.GetBindingData{
+		InstanceID: instanceID,
+		BindingID:  bindingID,
+	}
+
+	spec, err := b.Provider.GetBinding(providerCtx, getBindingData)
+	if err != nil {
+		return brokerapi.GetBindingSpec{}, err
+	}
+
+	b.logger.Debug("get-binding-success", lager.Data{
+		"instance-id": instanceID,
+		"binding-id":  bindingID,
+	})
+
+	return spec, nil
+}
+
+func (b *Broker) LastBindingOperation(
+	ctx context.Context,
+	instanceID, bindingID string,
+	pollDetails brokerapi.PollDetails,
+) (brokerapi.LastOperation, error) {
+	b.logger.Debug("last-binding-operation-start", lager.Data{
+		"instance-id":    instanceID,
+		"binding-id":     bindingID,
+		"operation-data": pollDetails.OperationData,
+	})
+
+	providerCtx, cancelFunc := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelFunc()
+
+	lastBindingOperationData := provider.LastBindingOperationData{
+		InstanceID:    instanceID,
+		BindingID:     bindingID,
+		ServiceID:     pollDetails.ServiceID,
+		PlanID:        pollDetails.PlanID,
+		OperationData: pollDetails.OperationData,
+	}
+
+	state, description, err := b.Provider.LastBindingOperation(providerCtx, lastBindingOperationData)
+	if err != nil {
+		return brokerapi.LastOperation{}, err
+	}
+
+	b.logger.Debug("last-binding-operation-success", lager.Data{
+		"instance-id":    instanceID,
+		"binding-id":     bindingID,
+		"operation-data": pollDetails.OperationData,
+	})
+
+	return brokerapi.LastOperation{
+		State:       state,
+		Description: description,
+	}, nil
+}
+
 func (b *Broker) Update(
 	ctx context.Context,
 	instanceID string,
@@ -233,7 +368,8 @@ func (b *Broker) Update(
 		return brokerapi.UpdateServiceSpec{}, brokerapi.ErrAsyncRequired
 	}
 
-	service, err := findServiceByID(b.config.Catalog, details.ServiceID)
+	config := b.currentConfig()
+	service, err := findServiceByID(config.Catalog, details.ServiceID)
 	if err != nil {
 		return brokerapi.UpdateServiceSpec{}, err
 	}
@@ -247,6 +383,18 @@ func (b *Broker) Update(
 		return brokerapi.UpdateServiceSpec{}, err
 	}
 
+	// Instances already on a deprecated plan can still be updated, but no
+	// others may move onto it.
+	if config.Plans[plan.ID].Deprecated && details.PlanID != details.PreviousValues.PlanID {
+		return brokerapi.UpdateServiceSpec{}, errPlanDeprecated
+	}
+
+	// A request which only carries maintenance_info is an upgrade, which the
+	// provider applies like any other update to the plan.
+	if err := checkMaintenanceInfo(plan, details.MaintenanceInfo); err != nil {
+		return brokerapi.UpdateServiceSpec{}, err
+	}
+
 	providerCtx, cancelFunc := context.WithTimeout(ctx, 30*time.Second)
 	defer cancelFunc()
 
@@ -274,6 +422,19 @@ func (b *Broker) Update(
 	}, nil
 }
 
+func checkMaintenanceInfo(plan brokerapi.ServicePlan, maintenanceInfo *brokerapi.MaintenanceInfo) error {
+	if maintenanceInfo == nil {
+		return nil
+	}
+	if plan.MaintenanceInfo == nil {
+		return brokerapi.ErrMaintenanceInfoNilConflict
+	}
+	if !plan.MaintenanceInfo.Equals(*maintenanceInfo) {
+		return brokerapi.ErrMaintenanceInfoConflict
+	}
+	return nil
+}
+
 func (b *Broker) LastOperation(
 	ctx context.Context,
 	instanceID string,
@@ -289,6 +450,8 @@ func (b *Broker) LastOperation(
 
 	lastOperationData := provider.LastOperationData{
 		InstanceID:    instanceID,
+		ServiceID:     pollDetails.ServiceID,
+		PlanID:        pollDetails.PlanID,
 		OperationData: pollDetails.OperationData,
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
 
 	"code.cloudfoundry.org/lager"
 	. "github.com/alphagov/paas-aiven-broker/broker"
@@ -54,6 +55,76 @@ var _ = Describe("Broker", func() {
 		}
 	})
 
+	Describe("Services", func() {
+		It("flags deprecated plans in their metadata, keeping them bindable", func() {
+			validConfig.Plans = map[string]PlanOptions{plan2.ID: {Deprecated: true}}
+			validConfig.Catalog.Catalog.Services[0].Plans[1].Metadata = &brokerapi.ServicePlanMetadata{
+				DisplayName:        "Plan 2",
+				AdditionalMetadata: map[string]interface{}{"tier": "small"},
+			}
+			b := New(validConfig, &fakes.FakeServiceProvider{}, lager.NewLogger("broker"))
+
+			services, err := b.Services(context.Background())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(services).To(HaveLen(1))
+			Expect(services[0].Plans).To(HaveLen(2))
+			Expect(services[0].Plans[0]).To(Equal(plan1))
+			Expect(services[0].Plans[1].Bindable).To(BeNil())
+			Expect(services[0].Plans[1].Metadata.DisplayName).To(Equal("Plan 2"))
+			Expect(services[0].Plans[1].Metadata.AdditionalMetadata).To(Equal(map[string]interface{}{
+				"tier":       "small",
+				"deprecated": true,
+			}))
+
+			planJSON, err := json.Marshal(services[0].Plans[1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(planJSON).NotTo(ContainSubstring(`"bindable"`))
+
+			Expect(validConfig.Catalog.Catalog.Services[0].Plans[1].Metadata.AdditionalMetadata).To(Equal(
+				map[string]interface{}{"tier": "small"},
+			))
+		})
+
+		It("serves the catalog from the config it was last given", func() {
+			b := New(validConfig, &fakes.FakeServiceProvider{}, lager.NewLogger("broker"))
+
+			newConfig := validConfig
+			newConfig.Plans = map[string]PlanOptions{plan2.ID: {Deprecated: true, HideWhenDeprecated: true}}
+			b.SetConfig(newConfig)
+
+			services, err := b.Services(context.Background())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(services).To(HaveLen(1))
+			Expect(services[0].Plans).To(Equal([]brokerapi.ServicePlan{plan1}))
+		})
+
+		It("hides deprecated plans when configured to", func() {
+			validConfig.Plans = map[string]PlanOptions{plan2.ID: {Deprecated: true, HideWhenDeprecated: true}}
+			b := New(validConfig, &fakes.FakeServiceProvider{}, lager.NewLogger("broker"))
+
+			services, err := b.Services(context.Background())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(services).To(HaveLen(1))
+			Expect(services[0].Plans).To(Equal([]brokerapi.ServicePlan{plan1}))
+		})
+
+		It("leaves out services whose plans are all hidden", func() {
+			validConfig.Plans = map[string]PlanOptions{
+				plan1.ID: {Deprecated: true, HideWhenDeprecated: true},
+				plan2.ID: {Deprecated: true, HideWhenDeprecated: true},
+			}
+			b := New(validConfig, &fakes.FakeServiceProvider{}, lager.NewLogger("broker"))
+
+			services, err := b.Services(context.Background())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(services).To(BeEmpty())
+		})
+	})
+
 	Describe("Provision", func() {
 		var validProvisionDetails brokerapi.ProvisionDetails
 
@@ -106,6 +177,34 @@ var _ = Describe("Broker", func() {
 			Expect(err).To(MatchError("Error: plan " + plan1.ID + " not found in service " + service1.ID))
 		})
 
+		It("rejects new instances of a deprecated plan", func() {
+			config := validConfig
+			config.Plans = map[string]PlanOptions{plan1.ID: {Deprecated: true}}
+			fakeProvider := &fakes.FakeServiceProvider{}
+			b := New(config, fakeProvider, lager.NewLogger("broker"))
+
+			_, err := b.Provision(context.Background(), instanceID, validProvisionDetails, true)
+
+			Expect(err).To(MatchError("plan is no longer available for new instances"))
+			failureResponse, ok := err.(*brokerapi.FailureResponse)
+			Expect(ok).To(BeTrue())
+			Expect(failureResponse.ValidatedStatusCode(nil)).To(Equal(http.StatusBadRequest))
+			Expect(fakeProvider.ProvisionCallCount()).To(Equal(0))
+		})
+
+		It("rejects a provision with stale maintenance_info", func() {
+			config := validConfig
+			config.Catalog.Catalog.Services[0].Plans[0].MaintenanceInfo = &brokerapi.MaintenanceInfo{Version: "7.0.0"}
+			fakeProvider := &fakes.FakeServiceProvider{}
+			b := New(config, fakeProvider, lager.NewLogger("broker"))
+
+			validProvisionDetails.MaintenanceInfo = &brokerapi.MaintenanceInfo{Version: "6.0.0"}
+			_, err := b.Provision(context.Background(), instanceID, validProvisionDetails, true)
+
+			Expect(err).To(Equal(brokerapi.ErrMaintenanceInfoConflict))
+			Expect(fakeProvider.ProvisionCallCount()).To(Equal(0))
+		})
+
 		It("sets a deadline by which the provision request should complete", func() {
 			fakeProvider := &fakes.FakeServiceProvider{}
 			b := New(validConfig, fakeProvider, lager.NewLogger("broker"))
@@ -276,7 +375,7 @@ var _ = Describe("Broker", func() {
 
 			Expect(b.Deprovision(context.Background(), instanceID, validDeprovisionDetails, true)).
 				To(Equal(brokerapi.DeprovisionServiceSpec{
-					IsAsync:       false,
+					IsAsync:       true,
 					OperationData: "operation data",
 				}))
 		})
@@ -517,6 +616,86 @@ var _ = Describe("Broker", func() {
 			})
 		})
 
+		Describe("Deprecated plans", func() {
+			BeforeEach(func() {
+				validConfig.Plans = map[string]PlanOptions{plan2.ID: {Deprecated: true}}
+			})
+
+			It("updates an instance which is already on a deprecated plan", func() {
+				fakeProvider := &fakes.FakeServiceProvider{}
+				b := New(validConfig, fakeProvider, lager.NewLogger("broker"))
+
+				updateParametersDetails := brokerapi.UpdateDetails{
+					ServiceID:      service1.ID,
+					PlanID:         plan2.ID,
+					RawParameters:  json.RawMessage(`{"new":"parameter"}`),
+					PreviousValues: brokerapi.PreviousValues{ServiceID: service1.ID, PlanID: plan2.ID},
+				}
+				_, err := b.Update(context.Background(), instanceID, updateParametersDetails, true)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeProvider.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("does not allow instances to move onto a deprecated plan", func() {
+				fakeProvider := &fakes.FakeServiceProvider{}
+				b := New(validConfig, fakeProvider, lager.NewLogger("broker"))
+
+				_, err := b.Update(context.Background(), instanceID, updatePlanDetails, true)
+
+				Expect(err).To(MatchError("plan is no longer available for new instances"))
+				Expect(fakeProvider.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Describe("Maintenance info", func() {
+			var upgradeDetails brokerapi.UpdateDetails
+
+			BeforeEach(func() {
+				validConfig.Catalog.Catalog.Services[0].Plans[0].MaintenanceInfo = &brokerapi.MaintenanceInfo{Version: "7.0.0"}
+				upgradeDetails = brokerapi.UpdateDetails{
+					ServiceID:       service1.ID,
+					PlanID:          plan1.ID,
+					MaintenanceInfo: &brokerapi.MaintenanceInfo{Version: "7.0.0"},
+					PreviousValues:  brokerapi.PreviousValues{ServiceID: service1.ID, PlanID: plan1.ID},
+				}
+			})
+
+			It("passes an upgrade to the current maintenance_info to the provider", func() {
+				fakeProvider := &fakes.FakeServiceProvider{}
+				b := New(validConfig, fakeProvider, lager.NewLogger("broker"))
+
+				_, err := b.Update(context.Background(), instanceID, upgradeDetails, true)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeProvider.UpdateCallCount()).To(Equal(1))
+				_, updateData := fakeProvider.UpdateArgsForCall(0)
+				Expect(updateData.Details.MaintenanceInfo).To(Equal(upgradeDetails.MaintenanceInfo))
+			})
+
+			It("rejects stale maintenance_info", func() {
+				fakeProvider := &fakes.FakeServiceProvider{}
+				b := New(validConfig, fakeProvider, lager.NewLogger("broker"))
+
+				upgradeDetails.MaintenanceInfo = &brokerapi.MaintenanceInfo{Version: "6.0.0"}
+				_, err := b.Update(context.Background(), instanceID, upgradeDetails, true)
+
+				Expect(err).To(Equal(brokerapi.ErrMaintenanceInfoConflict))
+				Expect(fakeProvider.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("rejects maintenance_info for plans which do not have any", func() {
+				fakeProvider := &fakes.FakeServiceProvider{}
+				b := New(validConfig, fakeProvider, lager.NewLogger("broker"))
+
+				upgradeDetails.PlanID = plan2.ID
+				_, err := b.Update(context.Background(), instanceID, upgradeDetails, true)
+
+				Expect(err).To(Equal(brokerapi.ErrMaintenanceInfoNilConflict))
+				Expect(fakeProvider.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
 		It("logs a debug message when update begins", func() {
 			logger := lager.NewLogger("broker")
 			log := gbytes.NewBuffer()
@@ -660,13 +839,19 @@ var _ = Describe("Broker", func() {
 			fakeProvider := &fakes.FakeServiceProvider{}
 			b := New(validConfig, fakeProvider, lager.NewLogger("broker"))
 
-			b.LastOperation(context.Background(), instanceID, brokerapi.PollDetails{OperationData: operationData})
+			b.LastOperation(context.Background(), instanceID, brokerapi.PollDetails{
+				ServiceID:     "service-id",
+				PlanID:        "plan-id",
+				OperationData: operationData,
+			})
 
 			Expect(fakeProvider.LastOperationCallCount()).To(Equal(1))
 			_, lastOperationData := fakeProvider.LastOperationArgsForCall(0)
 
 			expectedLastOperationData := provider.LastOperationData{
 				InstanceID:    instanceID,
+				ServiceID:     "service-id",
+				PlanID:        "plan-id",
 				OperationData: operationData,
 			}
 
@@ -21,6 +21,7 @@ const (
 type Config struct {
 	API      API
 	Catalog  Catalog
+	Plans    map[string]PlanOptions
 	Provider []byte
 }
 
@@ -59,9 +60,15 @@ func NewConfig(source io.Reader) (Config, error) {
 		return config, err
 	}
 
+	plans, err := decodePlanOptions(bytes)
+	if err != nil {
+		return config, err
+	}
+
 	config = Config{
 		API:      api,
 		Catalog:  catalog,
+		Plans:    plans,
 		Provider: bytes,
 	}
 
@@ -83,10 +90,59 @@ func (c Config) Validate() error {
 	if len(c.Catalog.Catalog.Services) == 0 {
 		return fmt.Errorf("Config error: at least one service is required")
 	}
+	serviceIDs := map[string]bool{}
+	serviceNames := map[string]bool{}
+	planIDs := map[string]bool{}
 	for _, service := range c.Catalog.Catalog.Services {
 		if len(service.Plans) == 0 {
 			return fmt.Errorf("Config error: no plans found for service %s", service.Name)
 		}
+		if serviceIDs[service.ID] {
+			return fmt.Errorf("Config error: service id %s is used by more than one service", service.ID)
+		}
+		serviceIDs[service.ID] = true
+		if serviceNames[service.Name] {
+			return fmt.Errorf("Config error: service name %s is used by more than one service", service.Name)
+		}
+		serviceNames[service.Name] = true
+		// Platforms identify plans by ID alone, so they must be unique
+		// across every service offered by the broker.
+		for _, plan := range service.Plans {
+			if planIDs[plan.ID] {
+				return fmt.Errorf("Config error: plan id %s is used by more than one plan", plan.ID)
+			}
+			planIDs[plan.ID] = true
+		}
+	}
+	return nil
+}
+
+// AddPlanSchemas sets the parameter schemas advertised for every plan in the
+// catalog.
+func (c *Config) AddPlanSchemas(planSchemas func(serviceID, planID string) (*brokerapi.ServiceSchemas, error)) error {
+	for _, service := range c.Catalog.Catalog.Services {
+		for i := range service.Plans {
+			schemas, err := planSchemas(service.ID, service.Plans[i].ID)
+			if err != nil {
+				return err
+			}
+			service.Plans[i].Schemas = schemas
+		}
+	}
+	return nil
+}
+
+// AddPlanMaintenanceInfo sets the maintenance_info advertised for every plan
+// in the catalog.
+func (c *Config) AddPlanMaintenanceInfo(planMaintenanceInfo func(serviceID, planID string) (*brokerapi.MaintenanceInfo, error)) error {
+	for _, service := range c.Catalog.Catalog.Services {
+		for i := range service.Plans {
+			maintenanceInfo, err := planMaintenanceInfo(service.ID, service.Plans[i].ID)
+			if err != nil {
+				return err
+			}
+			service.Plans[i].MaintenanceInfo = maintenanceInfo
+		}
 	}
 	return nil
 }
@@ -117,6 +173,41 @@ type Catalog struct {
 	Catalog brokerapi.CatalogResponse `json:"catalog"`
 }
 
+// PlanOptions are broker settings which sit alongside a plan in the catalog
+// config but are not part of the catalog served to the platform.
+type PlanOptions struct {
+	// Deprecated plans cannot be used for new instances, but existing
+	// instances on them can still be updated, bound and deprovisioned.
+	Deprecated bool `json:"deprecated"`
+	// HideWhenDeprecated removes a deprecated plan from the catalog rather
+	// than flagging it as deprecated in its metadata.
+	HideWhenDeprecated bool `json:"hide_when_deprecated"`
+}
+
+func decodePlanOptions(bytes []byte) (map[string]PlanOptions, error) {
+	var config struct {
+		Catalog struct {
+			Services []struct {
+				Plans []struct {
+					ID string `json:"id"`
+					PlanOptions
+				} `json:"plans"`
+			} `json:"services"`
+		} `json:"catalog"`
+	}
+	if err := json.Unmarshal(bytes, &config); err != nil {
+		return nil, err
+	}
+
+	plans := map[string]PlanOptions{}
+	for _, service := range config.Catalog.Services {
+		for _, plan := range service.Plans {
+			plans[plan.ID] = plan.PlanOptions
+		}
+	}
+	return plans, nil
+}
+
 func findServiceByID(catalog Catalog, serviceID string) (brokerapi.Service, error) {
 	for _, service := range catalog.Catalog.Services {
 		if service.ID == serviceID {
@@ -1,10 +1,12 @@
 package broker_test
 
 import (
+	"errors"
 	"strings"
 
 	"code.cloudfoundry.org/lager"
 	. "github.com/alphagov/paas-aiven-broker/broker"
+	"github.com/pivotal-cf/brokerapi"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -147,5 +149,142 @@ var _ = Describe("Config", func() {
 			_, err := NewConfig(strings.NewReader(configSource))
 			Expect(err).To(MatchError("Config error: no plans found for service service2"))
 		})
+
+		It("allows several services each with their own plans", func() {
+			configSource = `
+				{
+					"basic_auth_username":"username",
+					"basic_auth_password":"1234",
+					"catalog": {"services": [
+						{"id": "service1", "name": "aiven-elasticsearch", "plans": [{"id": "plan1", "name": "small"}]},
+						{"id": "service2", "name": "aiven-influxdb", "plans": [{"id": "plan2", "name": "small"}]},
+						{"id": "service3", "name": "aiven-kafka", "plans": [{"id": "plan3", "name": "small"}]}
+					]}
+				}
+			`
+			config, err := NewConfig(strings.NewReader(configSource))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Catalog.Catalog.Services).To(HaveLen(3))
+		})
+
+		It("reads the plan deprecation settings", func() {
+			configSource = `
+				{
+					"basic_auth_username":"username",
+					"basic_auth_password":"1234",
+					"catalog": {"services": [
+						{"id": "service1", "name": "service1", "plans": [
+							{"id": "plan1", "name": "plan1"},
+							{"id": "plan2", "name": "plan2", "deprecated": true, "hide_when_deprecated": true}
+						]}
+					]}
+				}
+			`
+			config, err := NewConfig(strings.NewReader(configSource))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Plans).To(Equal(map[string]PlanOptions{
+				"plan1": {},
+				"plan2": {Deprecated: true, HideWhenDeprecated: true},
+			}))
+		})
+
+		It("requires service ids to be unique", func() {
+			configSource = `
+				{
+					"basic_auth_username":"username",
+					"basic_auth_password":"1234",
+					"catalog": {"services": [
+						{"id": "service1", "name": "service1", "plans": [{"id": "plan1", "name": "plan1"}]},
+						{"id": "service1", "name": "service2", "plans": [{"id": "plan2", "name": "plan2"}]}
+					]}
+				}
+			`
+			_, err := NewConfig(strings.NewReader(configSource))
+			Expect(err).To(MatchError("Config error: service id service1 is used by more than one service"))
+		})
+
+		It("requires service names to be unique", func() {
+			configSource = `
+				{
+					"basic_auth_username":"username",
+					"basic_auth_password":"1234",
+					"catalog": {"services": [
+						{"id": "service1", "name": "service1", "plans": [{"id": "plan1", "name": "plan1"}]},
+						{"id": "service2", "name": "service1", "plans": [{"id": "plan2", "name": "plan2"}]}
+					]}
+				}
+			`
+			_, err := NewConfig(strings.NewReader(configSource))
+			Expect(err).To(MatchError("Config error: service name service1 is used by more than one service"))
+		})
+
+		It("requires plan ids to be unique across services", func() {
+			configSource = `
+				{
+					"basic_auth_username":"username",
+					"basic_auth_password":"1234",
+					"catalog": {"services": [
+						{"id": "service1", "name": "service1", "plans": [{"id": "plan1", "name": "plan1"}]},
+						{"id": "service2", "name": "service2", "plans": [{"id": "plan1", "name": "plan1"}]}
+					]}
+				}
+			`
+			_, err := NewConfig(strings.NewReader(configSource))
+			Expect(err).To(MatchError("Config error: plan id plan1 is used by more than one plan"))
+		})
+	})
+	Describe("Adding generated plan details", func() {
+		var config Config
+
+		BeforeEach(func() {
+			configSource = `
+				{
+					"basic_auth_username":"username",
+					"basic_auth_password":"1234",
+					"catalog": {"services": [
+						{"id": "service1", "name": "service1", "plans": [{"id": "plan1", "name": "plan1"}, {"id": "plan2", "name": "plan2"}]}
+					]}
+				}
+			`
+			var err error
+			config, err = NewConfig(strings.NewReader(configSource))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sets the schemas of every plan", func() {
+			err := config.AddPlanSchemas(func(serviceID, planID string) (*brokerapi.ServiceSchemas, error) {
+				return &brokerapi.ServiceSchemas{
+					Instance: brokerapi.ServiceInstanceSchema{
+						Create: brokerapi.Schema{Parameters: map[string]interface{}{"title": serviceID + "/" + planID}},
+					},
+				}, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			plans := config.Catalog.Catalog.Services[0].Plans
+			Expect(plans[0].Schemas.Instance.Create.Parameters).To(HaveKeyWithValue("title", "service1/plan1"))
+			Expect(plans[1].Schemas.Instance.Create.Parameters).To(HaveKeyWithValue("title", "service1/plan2"))
+		})
+
+		It("sets the maintenance info of every plan", func() {
+			err := config.AddPlanMaintenanceInfo(func(serviceID, planID string) (*brokerapi.MaintenanceInfo, error) {
+				if planID == "plan2" {
+					return nil, nil
+				}
+				return &brokerapi.MaintenanceInfo{Version: "6.0.0"}, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			plans := config.Catalog.Catalog.Services[0].Plans
+			Expect(plans[0].MaintenanceInfo).To(Equal(&brokerapi.MaintenanceInfo{Version: "6.0.0"}))
+			Expect(plans[1].MaintenanceInfo).To(BeNil())
+		})
+
+		It("returns any error from generating the schemas", func() {
+			err := config.AddPlanSchemas(func(serviceID, planID string) (*brokerapi.ServiceSchemas, error) {
+				return nil, errors.New("no schema for you")
+			})
+			Expect(err).To(MatchError("no schema for you"))
+		})
 	})
 })
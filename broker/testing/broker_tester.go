@@ -59,15 +59,39 @@ func (bt BrokerTester) Deprovision(instanceID, serviceID, planID string, async b
 	)
 }
 
-func (bt BrokerTester) Bind(instanceID, bindingID string, body RequestBody) *httptest.ResponseRecorder {
+func (bt BrokerTester) Bind(instanceID, bindingID string, body RequestBody, async bool) *httptest.ResponseRecorder {
 	bodyJSON, _ := json.Marshal(body)
 	return bt.Put(
 		fmt.Sprintf("/v2/service_instances/%s/service_bindings/%s", instanceID, bindingID),
 		bytes.NewBuffer(bodyJSON),
+		url.Values{"accepts_incomplete": []string{strconv.FormatBool(async)}},
+	)
+}
+
+func (bt BrokerTester) GetBinding(instanceID, bindingID string) *httptest.ResponseRecorder {
+	return bt.Get(
+		fmt.Sprintf("/v2/service_instances/%s/service_bindings/%s", instanceID, bindingID),
 		url.Values{},
 	)
 }
 
+func (bt BrokerTester) LastBindingOperation(instanceID, bindingID, serviceID, planID, operation string) *httptest.ResponseRecorder {
+	urlValues := url.Values{}
+	if serviceID != "" {
+		urlValues.Add("service_id", serviceID)
+	}
+	if planID != "" {
+		urlValues.Add("plan_id", planID)
+	}
+	if operation != "" {
+		urlValues.Add("operation", operation)
+	}
+	return bt.Get(
+		fmt.Sprintf("/v2/service_instances/%s/service_bindings/%s/last_operation", instanceID, bindingID),
+		urlValues,
+	)
+}
+
 func (bt BrokerTester) Unbind(instanceID, bindingID string, body RequestBody) *httptest.ResponseRecorder {
 	bodyJSON, _ := json.Marshal(body)
 	return bt.Delete(
@@ -76,9 +76,10 @@ var _ = Describe("Broker", func() {
 	)
 
 	var (
-		instanceID   string
-		bindingID    string
-		brokerTester brokertesting.BrokerTester
+		instanceID    string
+		bindingID     string
+		aivenProvider *provider.AivenProvider
+		brokerTester  brokertesting.BrokerTester
 	)
 
 	BeforeEach(func() {
@@ -89,11 +90,11 @@ var _ = Describe("Broker", func() {
 		brokerConfig, err := broker.NewConfig(strings.NewReader(configJSON))
 		Expect(err).ToNot(HaveOccurred())
 
-		aivenProvider, err := provider.New(brokerConfig.Provider)
-		Expect(err).ToNot(HaveOccurred())
-
 		logger := lager.NewLogger("AivenServiceBroker")
 		logger.RegisterSink(lager.NewWriterSink(os.Stdout, brokerConfig.API.LagerLogLevel))
+
+		aivenProvider, err = provider.New(brokerConfig.Provider, logger)
+		Expect(err).ToNot(HaveOccurred())
 		aivenBroker := broker.New(brokerConfig, aivenProvider, logger)
 
 		brokerServer := broker.NewAPI(aivenBroker, logger, brokerConfig)
@@ -123,8 +124,7 @@ var _ = Describe("Broker", func() {
 			egressIP := os.Getenv("EGRESS_IP")
 			Expect(egressIP).ToNot(BeEmpty())
 
-			os.Setenv("IP_WHITELIST", egressIP)
-			defer os.Unsetenv("IP_WHITELIST")
+			aivenProvider.Config.IPWhitelist = []string{egressIP}
 
 			By("Provisioning")
 			res := brokerTester.Provision(instanceID, brokertesting.RequestBody{
@@ -143,7 +143,7 @@ var _ = Describe("Broker", func() {
 			res = brokerTester.Bind(instanceID, bindingID, brokertesting.RequestBody{
 				ServiceID: elasticsearchServiceGUID,
 				PlanID:    elasticsearchInitialPlanGUID,
-			})
+			}, false)
 			Expect(res.Code).To(Equal(http.StatusCreated))
 
 			parsedResponse := BindingResponse{}
@@ -224,8 +224,7 @@ var _ = Describe("Broker", func() {
 		// 99% of this IP whitelisting test is stolen from the lifecycle mgmt test, below.
 		// Refactor opportunity!
 		It("should enforce IP whitelisting if configured to do so", func() {
-			os.Setenv("IP_WHITELIST", "8.8.8.8")
-			defer os.Unsetenv("IP_WHITELIST")
+			aivenProvider.Config.IPWhitelist = []string{"8.8.8.8"}
 
 			By("Provisioning")
 
@@ -245,7 +244,7 @@ var _ = Describe("Broker", func() {
 			res = brokerTester.Bind(instanceID, bindingID, brokertesting.RequestBody{
 				ServiceID: elasticsearchServiceGUID,
 				PlanID:    elasticsearchInitialPlanGUID,
-			})
+			}, false)
 			Expect(res.Code).To(Equal(http.StatusCreated))
 
 			parsedResponse := BindingResponse{}
@@ -280,8 +279,7 @@ var _ = Describe("Broker", func() {
 			egressIP := os.Getenv("EGRESS_IP")
 			Expect(egressIP).ToNot(BeEmpty())
 
-			os.Setenv("IP_WHITELIST", egressIP)
-			defer os.Unsetenv("IP_WHITELIST")
+			aivenProvider.Config.IPWhitelist = []string{egressIP}
 
 			By("Provisioning")
 			res := brokerTester.Provision(instanceID, brokertesting.RequestBody{
@@ -308,6 +306,7 @@ var _ = Describe("Broker", func() {
 					ServiceID: influxDBServiceGUID,
 					PlanID:    influxDBPlanGUID,
 				},
+				false,
 			)
 			Expect(res.Code).To(Equal(http.StatusCreated))
 
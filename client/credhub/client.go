@@ -0,0 +1,188 @@
+package credhub
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:generate counterfeiter -o fakes/fake_client.go . Client
+type Client interface {
+	SetJSON(name string, value interface{}) error
+	AddReadPermission(name, actor string) error
+	Delete(name string) error
+}
+
+type HttpClient struct {
+	http         *http.Client
+	URI          string
+	UAAURI       string
+	ClientName   string
+	ClientSecret string
+
+	tokenLock   sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// New returns a client which authenticates with UAA as the given client. The
+// CA certificate is trusted as well as the system's, if it is set.
+func New(uri, uaaURI, clientName, clientSecret, caCert string) (*HttpClient, error) {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if caCert != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("Error creating CredHub client: invalid CA certificate")
+		}
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+		}
+	}
+
+	return &HttpClient{
+		http:         httpClient,
+		URI:          strings.TrimSuffix(uri, "/"),
+		UAAURI:       strings.TrimSuffix(uaaURI, "/"),
+		ClientName:   clientName,
+		ClientSecret: clientSecret,
+	}, nil
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// accessToken gets a token from UAA, reusing it until shortly before it
+// expires.
+func (c *HttpClient) accessToken() (string, error) {
+	c.tokenLock.Lock()
+	defer c.tokenLock.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	form := url.Values{"grant_type": []string{"client_credentials"}}
+	req, err := http.NewRequest("POST", c.UAAURI+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(c.ClientName, c.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(res.Body)
+		return "", fmt.Errorf("Error getting UAA token: %d status code returned from UAA: '%s'", res.StatusCode, b)
+	}
+
+	token := tokenResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("Error getting UAA token: no access token found in response JSON")
+	}
+
+	c.token = token.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - 30*time.Second)
+	return c.token, nil
+}
+
+func (c *HttpClient) do(method, path string, body interface{}) (*http.Response, error) {
+	token, err := c.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var reqBody []byte
+	if body != nil {
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.URI+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.http.Do(req)
+}
+
+// SetJSON stores the value as a JSON credential, replacing any current value.
+func (c *HttpClient) SetJSON(name string, value interface{}) error {
+	res, err := c.do("PUT", "/api/v1/data", map[string]interface{}{
+		"name":  name,
+		"type":  "json",
+		"value": value,
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("Error setting CredHub credential: %d status code returned from CredHub: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+// AddReadPermission lets the actor, such as mtls-app:<app guid>, read the
+// credential.
+func (c *HttpClient) AddReadPermission(name, actor string) error {
+	res, err := c.do("POST", "/api/v2/permissions", map[string]interface{}{
+		"path":       name,
+		"actor":      actor,
+		"operations": []string{"read"},
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// The permission already existing is fine, as bind requests are retried.
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusConflict {
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("Error adding CredHub permission: %d status code returned from CredHub: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+// Delete removes the credential. Credentials which do not exist are ignored.
+func (c *HttpClient) Delete(name string) error {
+	res, err := c.do("DELETE", "/api/v1/data?"+url.Values{"name": []string{name}}.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusNotFound {
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("Error deleting CredHub credential: %d status code returned from CredHub: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package credhub_test
+
+import (
+	"net/http"
+
+	"github.com/alphagov/paas-aiven-broker/client/credhub"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CredHub Client", func() {
+	var (
+		uaaServer     *ghttp.Server
+		credhubServer *ghttp.Server
+		client        *credhub.HttpClient
+		tokenResponse http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		uaaServer = ghttp.NewServer()
+		credhubServer = ghttp.NewServer()
+
+		var err error
+		client, err = credhub.New(credhubServer.URL(), uaaServer.URL(), "broker", "secret", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		tokenResponse = ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/oauth/token"),
+			ghttp.VerifyBasicAuth("broker", "secret"),
+			ghttp.VerifyFormKV("grant_type", "client_credentials"),
+			ghttp.RespondWith(http.StatusOK, `{"access_token": "token", "expires_in": 3600}`),
+		)
+	})
+
+	AfterEach(func() {
+		uaaServer.Close()
+		credhubServer.Close()
+	})
+
+	It("rejects an invalid CA certificate", func() {
+		_, err := credhub.New(credhubServer.URL(), uaaServer.URL(), "broker", "secret", "not a certificate")
+		Expect(err).To(MatchError("Error creating CredHub client: invalid CA certificate"))
+	})
+
+	Describe("SetJSON", func() {
+		It("stores the value as a JSON credential", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/api/v1/data"),
+				ghttp.VerifyHeaderKV("Authorization", "bearer token"),
+				ghttp.VerifyJSON(`{"name": "/c/broker/instance/binding/credentials", "type": "json", "value": {"password": "secret"}}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := client.SetJSON("/c/broker/instance/binding/credentials", map[string]string{"password": "secret"})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("reuses the UAA token until it expires", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, `{}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			)
+
+			Expect(client.SetJSON("/c/broker/one", map[string]string{})).To(Succeed())
+			Expect(client.SetJSON("/c/broker/two", map[string]string{})).To(Succeed())
+			Expect(uaaServer.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("returns an error if UAA does not give a token", func() {
+			uaaServer.AppendHandlers(ghttp.RespondWith(http.StatusUnauthorized, `{"error": "unauthorized"}`))
+
+			err := client.SetJSON("/c/broker/instance/binding/credentials", map[string]string{})
+			Expect(err).To(MatchError(`Error getting UAA token: 401 status code returned from UAA: '{"error": "unauthorized"}'`))
+		})
+
+		It("returns an error if the credential cannot be stored", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, `{}`))
+
+			err := client.SetJSON("/c/broker/instance/binding/credentials", map[string]string{})
+			Expect(err).To(MatchError("Error setting CredHub credential: 403 status code returned from CredHub: '{}'"))
+		})
+	})
+
+	Describe("AddReadPermission", func() {
+		It("lets the actor read the credential", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/api/v2/permissions"),
+				ghttp.VerifyJSON(`{"path": "/c/broker/instance/binding/credentials", "actor": "mtls-app:app-guid", "operations": ["read"]}`),
+				ghttp.RespondWith(http.StatusCreated, `{}`),
+			))
+
+			err := client.AddReadPermission("/c/broker/instance/binding/credentials", "mtls-app:app-guid")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("succeeds if the permission already exists", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusConflict, `{}`))
+
+			err := client.AddReadPermission("/c/broker/instance/binding/credentials", "mtls-app:app-guid")
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the credential", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v1/data", "name=%2Fc%2Fbroker%2Finstance%2Fbinding%2Fcredentials"),
+				ghttp.RespondWith(http.StatusNoContent, ``),
+			))
+
+			err := client.Delete("/c/broker/instance/binding/credentials")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("succeeds if the credential does not exist", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{}`))
+
+			err := client.Delete("/c/broker/instance/binding/credentials")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the credential cannot be deleted", func() {
+			uaaServer.AppendHandlers(tokenResponse)
+			credhubServer.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, `{}`))
+
+			err := client.Delete("/c/broker/instance/binding/credentials")
+			Expect(err).To(MatchError("Error deleting CredHub credential: 500 status code returned from CredHub: '{}'"))
+		})
+	})
+})
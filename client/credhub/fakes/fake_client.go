@@ -0,0 +1,257 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/alphagov/paas-aiven-broker/client/credhub"
+)
+
+type FakeClient struct {
+	AddReadPermissionStub        func(string, string) error
+	addReadPermissionMutex       sync.RWMutex
+	addReadPermissionArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	addReadPermissionReturns struct {
+		result1 error
+	}
+	addReadPermissionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteStub        func(string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetJSONStub        func(string, interface{}) error
+	setJSONMutex       sync.RWMutex
+	setJSONArgsForCall []struct {
+		arg1 string
+		arg2 interface{}
+	}
+	setJSONReturns struct {
+		result1 error
+	}
+	setJSONReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeClient) AddReadPermission(arg1 string, arg2 string) error {
+	fake.addReadPermissionMutex.Lock()
+	ret, specificReturn := fake.addReadPermissionReturnsOnCall[len(fake.addReadPermissionArgsForCall)]
+	fake.addReadPermissionArgsForCall = append(fake.addReadPermissionArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AddReadPermissionStub
+	fakeReturns := fake.addReadPermissionReturns
+	fake.recordInvocation("AddReadPermission", []interface{}{arg1, arg2})
+	fake.addReadPermissionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) AddReadPermissionCallCount() int {
+	fake.addReadPermissionMutex.RLock()
+	defer fake.addReadPermissionMutex.RUnlock()
+	return len(fake.addReadPermissionArgsForCall)
+}
+
+func (fake *FakeClient) AddReadPermissionCalls(stub func(string, string) error) {
+	fake.addReadPermissionMutex.Lock()
+	defer fake.addReadPermissionMutex.Unlock()
+	fake.AddReadPermissionStub = stub
+}
+
+func (fake *FakeClient) AddReadPermissionArgsForCall(i int) (string, string) {
+	fake.addReadPermissionMutex.RLock()
+	defer fake.addReadPermissionMutex.RUnlock()
+	argsForCall := fake.addReadPermissionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) AddReadPermissionReturns(result1 error) {
+	fake.addReadPermissionMutex.Lock()
+	defer fake.addReadPermissionMutex.Unlock()
+	fake.AddReadPermissionStub = nil
+	fake.addReadPermissionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) AddReadPermissionReturnsOnCall(i int, result1 error) {
+	fake.addReadPermissionMutex.Lock()
+	defer fake.addReadPermissionMutex.Unlock()
+	fake.AddReadPermissionStub = nil
+	if fake.addReadPermissionReturnsOnCall == nil {
+		fake.addReadPermissionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addReadPermissionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) Delete(arg1 string) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeClient) DeleteCalls(stub func(string) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeClient) DeleteArgsForCall(i int) string {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetJSON(arg1 string, arg2 interface{}) error {
+	fake.setJSONMutex.Lock()
+	ret, specificReturn := fake.setJSONReturnsOnCall[len(fake.setJSONArgsForCall)]
+	fake.setJSONArgsForCall = append(fake.setJSONArgsForCall, struct {
+		arg1 string
+		arg2 interface{}
+	}{arg1, arg2})
+	stub := fake.SetJSONStub
+	fakeReturns := fake.setJSONReturns
+	fake.recordInvocation("SetJSON", []interface{}{arg1, arg2})
+	fake.setJSONMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) SetJSONCallCount() int {
+	fake.setJSONMutex.RLock()
+	defer fake.setJSONMutex.RUnlock()
+	return len(fake.setJSONArgsForCall)
+}
+
+func (fake *FakeClient) SetJSONCalls(stub func(string, interface{}) error) {
+	fake.setJSONMutex.Lock()
+	defer fake.setJSONMutex.Unlock()
+	fake.SetJSONStub = stub
+}
+
+func (fake *FakeClient) SetJSONArgsForCall(i int) (string, interface{}) {
+	fake.setJSONMutex.RLock()
+	defer fake.setJSONMutex.RUnlock()
+	argsForCall := fake.setJSONArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) SetJSONReturns(result1 error) {
+	fake.setJSONMutex.Lock()
+	defer fake.setJSONMutex.Unlock()
+	fake.SetJSONStub = nil
+	fake.setJSONReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetJSONReturnsOnCall(i int, result1 error) {
+	fake.setJSONMutex.Lock()
+	defer fake.setJSONMutex.Unlock()
+	fake.SetJSONStub = nil
+	if fake.setJSONReturnsOnCall == nil {
+		fake.setJSONReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setJSONReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ credhub.Client = new(FakeClient)
@@ -0,0 +1,118 @@
+// Package postgres runs statements on PostgreSQL services, for the few
+// things, such as grants, which Aiven's API cannot do.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+//go:generate counterfeiter -o fakes/fake_client.go . Client
+type Client interface {
+	// Exec runs each statement in turn, stopping at the first which fails.
+	Exec(ctx context.Context, connection Connection, statements ...string) error
+}
+
+// Connection is where and as whom statements are run.
+type Connection struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	Database string
+	// CACertificate is the PEM encoded CA certificate of the service. The
+	// system's CAs are used without it.
+	CACertificate string
+}
+
+type TCPClient struct {
+	// DialTimeout is used when the context has no deadline.
+	DialTimeout time.Duration
+}
+
+func New() *TCPClient {
+	return &TCPClient{DialTimeout: 10 * time.Second}
+}
+
+// QuoteIdentifier quotes a name, such as a database or user, for use in a
+// statement.
+func QuoteIdentifier(name string) string {
+	return pq.QuoteIdentifier(name)
+}
+
+func (c *TCPClient) Exec(ctx context.Context, connection Connection, statements ...string) error {
+	// lib/pq only reads CA certificates from files.
+	caFile := ""
+	if connection.CACertificate != "" {
+		file, err := ioutil.TempFile("", "postgres-ca-*.pem")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file.Name())
+		_, err = file.WriteString(connection.CACertificate)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		caFile = file.Name()
+	}
+
+	connector, err := pq.NewConnector(c.connectionString(ctx, connection, caFile))
+	if err != nil {
+		return err
+	}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectionString is the connection's lib/pq DSN. Aiven requires TLS, and
+// the server's certificate is always verified.
+func (c *TCPClient) connectionString(ctx context.Context, connection Connection, caFile string) string {
+	parameters := [][2]string{
+		{"host", connection.Host},
+		{"port", connection.Port},
+		{"user", connection.Username},
+		{"password", connection.Password},
+		{"dbname", connection.Database},
+		{"sslmode", "verify-full"},
+	}
+	if caFile != "" {
+		parameters = append(parameters, [2]string{"sslrootcert", caFile})
+	}
+	if _, ok := ctx.Deadline(); !ok && c.DialTimeout > 0 {
+		// connect_timeout is in whole seconds, and zero means none.
+		seconds := int(c.DialTimeout.Seconds())
+		if seconds < 1 {
+			seconds = 1
+		}
+		parameters = append(parameters, [2]string{"connect_timeout", fmt.Sprint(seconds)})
+	}
+
+	pairs := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		value := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(parameter[1])
+		pairs[i] = fmt.Sprintf("%s='%s'", parameter[0], value)
+	}
+	return strings.Join(pairs, " ")
+}
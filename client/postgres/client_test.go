@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL Client", func() {
+	var (
+		connection Connection
+		client     *TCPClient
+	)
+
+	BeforeEach(func() {
+		connection = Connection{
+			Host:     "db.example.com",
+			Port:     "5432",
+			Username: "user",
+			Password: `it's a \ secret`,
+			Database: "my-database",
+		}
+		client = New()
+	})
+
+	It("connects over verified TLS, escaping the connection's values", func() {
+		Expect(client.connectionString(context.Background(), connection, "")).To(Equal(
+			`host='db.example.com' port='5432' user='user' password='it\'s a \\ secret' dbname='my-database' ` +
+				`sslmode='verify-full' connect_timeout='10'`,
+		))
+	})
+
+	It("trusts the CA certificate file, and leaves timing out to the context's deadline", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		Expect(client.connectionString(ctx, connection, "/tmp/ca.pem")).To(Equal(
+			`host='db.example.com' port='5432' user='user' password='it\'s a \\ secret' dbname='my-database' ` +
+				`sslmode='verify-full' sslrootcert='/tmp/ca.pem'`,
+		))
+	})
+
+	It("refuses to connect to a server without TLS", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		defer listener.Close()
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			// Refuse the SSLRequest.
+			io.ReadFull(conn, make([]byte, 8))
+			conn.Write([]byte("N"))
+		}()
+		connection.Host, connection.Port, _ = net.SplitHostPort(listener.Addr().String())
+
+		err = client.Exec(context.Background(), connection, "SELECT 1")
+		Expect(err).To(MatchError(ContainSubstring("SSL is not enabled on the server")))
+	})
+
+	It("returns an error if the server cannot be reached", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		connection.Host, connection.Port, _ = net.SplitHostPort(listener.Addr().String())
+		listener.Close()
+
+		err = client.Exec(context.Background(), connection, "SELECT 1")
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+	})
+
+	It("quotes identifiers", func() {
+		Expect(QuoteIdentifier(`my"db`)).To(Equal(`"my""db"`))
+	})
+})
@@ -1,23 +1,28 @@
 module github.com/alphagov/paas-aiven-broker
 
-go 1.15
+go 1.18
 
 require (
 	code.cloudfoundry.org/lager v1.1.0
+	github.com/lib/pq v1.10.9
+	github.com/onsi/ginkgo v1.5.0
+	github.com/onsi/gomega v1.4.0
+	github.com/pivotal-cf/brokerapi v6.4.2+incompatible
+	github.com/satori/go.uuid v1.2.0
+	gopkg.in/jarcoal/httpmock.v1 v1.0.0-20180615191036-16f9a43967d6
+)
+
+require (
 	github.com/drewolson/testflight v1.0.0 // indirect
 	github.com/golang/protobuf v1.1.0 // indirect
+	github.com/google/uuid v1.0.0 // indirect
 	github.com/gorilla/context v1.1.1 // indirect
 	github.com/gorilla/mux v1.6.2 // indirect
-	github.com/onsi/ginkgo v1.5.0
-	github.com/onsi/gomega v1.4.0
 	github.com/pborman/uuid v1.2.0 // indirect
-	github.com/pivotal-cf/brokerapi v6.4.2+incompatible
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/satori/go.uuid v1.2.0
 	golang.org/x/net v0.0.0-20180611182652-db08ff08e862 // indirect
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e // indirect
 	golang.org/x/sys v0.0.0-20180613171135-56ede360ec1c // indirect
 	golang.org/x/text v0.3.0 // indirect
-	gopkg.in/jarcoal/httpmock.v1 v1.0.0-20180615191036-16f9a43967d6
 	gopkg.in/yaml.v2 v2.2.1 // indirect
 )
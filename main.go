@@ -1,47 +1,121 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"code.cloudfoundry.org/lager"
 
 	"github.com/alphagov/paas-aiven-broker/broker"
 	"github.com/alphagov/paas-aiven-broker/provider"
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
 )
 
-var configFilePath string
+var (
+	configFilePath     string
+	skipPlanValidation bool
+	listBindingUsers   string
+	listInstances      bool
+	syncIPWhitelist    bool
+	syncConcurrency    int
+	dryRun             bool
+)
 
 func main() {
 	flag.StringVar(&configFilePath, "config", "./config.json", "Location of the config file")
+	flag.BoolVar(&skipPlanValidation, "skip-plan-validation", false, "Do not check the configured plans exist in Aiven")
+	flag.StringVar(&listBindingUsers, "list-binding-users", "", "Print the users of the bindings of this instance ID, and the apps they were created for, then exit")
+	flag.BoolVar(&listInstances, "list-instances", false, "Print the broker's services in Aiven, with the tags tracing them back to their instances, then exit")
+	flag.BoolVar(&syncIPWhitelist, "sync-ip-whitelist", false, "Update the IP filters of the broker's services to match the platform whitelist, print what changed, then exit")
+	flag.IntVar(&syncConcurrency, "sync-concurrency", provider.DefaultIPWhitelistSyncConcurrency, "How many services -sync-ip-whitelist updates at once")
+	flag.BoolVar(&dryRun, "dry-run", false, "With -sync-ip-whitelist, only report the services whose IP filters differ")
 	flag.Parse()
 
-	file, err := os.Open(configFilePath)
+	config, err := readConfig()
 	if err != nil {
-		log.Fatalf("Error opening config file %s: %s\n", configFilePath, err)
+		log.Fatalln(err)
 	}
-	defer file.Close()
 
-	config, err := broker.NewConfig(file)
-	if err != nil {
-		log.Fatalf("Error validating config file: %v\n", err)
-	}
+	logger := lager.NewLogger("aiven-service-broker")
+	logger.RegisterSink(lager.NewWriterSink(os.Stdout, config.API.LagerLogLevel))
 
-	aivenProvider, err := provider.New(config.Provider)
+	aivenProvider, err := provider.New(config.Provider, logger)
 	if err != nil {
 		log.Fatalf("Error creating Aiven provider: %v\n", err)
 	}
 
-	logger := lager.NewLogger("aiven-service-broker")
-	logger.RegisterSink(lager.NewWriterSink(os.Stdout, config.API.LagerLogLevel))
+	if listBindingUsers != "" {
+		users, err := aivenProvider.ListBindingUsers(context.Background(), listBindingUsers)
+		if err != nil {
+			log.Fatalf("Error listing binding users: %v\n", err)
+		}
+		json.NewEncoder(os.Stdout).Encode(users)
+		return
+	}
+
+	if listInstances {
+		instances, err := aivenProvider.ListInstances(context.Background())
+		if err != nil {
+			log.Fatalf("Error listing instances: %v\n", err)
+		}
+		json.NewEncoder(os.Stdout).Encode(instances)
+		return
+	}
+
+	if syncIPWhitelist {
+		reports, err := aivenProvider.SyncIPWhitelist(context.Background(), dryRun, syncConcurrency)
+		if err != nil {
+			log.Fatalf("Error syncing IP whitelist: %v\n", err)
+		}
+		json.NewEncoder(os.Stdout).Encode(reports)
+		return
+	}
+
+	if err := prepareConfig(context.Background(), &config, aivenProvider.Config, aivenProvider.Client); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Bindings with a ttl have their users deleted once they expire.
+	go aivenProvider.RunBindingReaper(context.Background(), provider.BindingReaperInterval)
 
 	aivenBroker := broker.New(config, aivenProvider, logger)
 	brokerServer := broker.NewAPI(aivenBroker, logger, config)
 
+	// The catalog and plans are reloaded on SIGHUP. API settings, such as the
+	// port and credentials, only change on restart.
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			newConfig, err := readConfig()
+			if err != nil {
+				logger.Error("reload-config", err)
+				continue
+			}
+			newProviderConfig, err := provider.DecodeConfig(newConfig.Provider)
+			if err != nil {
+				logger.Error("reload-config", err)
+				continue
+			}
+			if err := prepareConfig(context.Background(), &newConfig, newProviderConfig, aivenProvider.Client); err != nil {
+				logger.Error("reload-config", err)
+				continue
+			}
+
+			aivenProvider.SetConfig(newProviderConfig)
+			aivenBroker.SetConfig(newConfig)
+			logger.Info("reload-config-success", lager.Data{"config-file": configFilePath})
+		}
+	}()
+
 	listener, err := net.Listen("tcp", ":"+config.API.Port)
 	if err != nil {
 		log.Fatalf("Error listening to port %s: %s", config.API.Port, err)
@@ -49,3 +123,37 @@ func main() {
 	fmt.Println("Aiven service broker started on port " + config.API.Port + "...")
 	http.Serve(listener, brokerServer)
 }
+
+func readConfig() (broker.Config, error) {
+	file, err := os.Open(configFilePath)
+	if err != nil {
+		return broker.Config{}, fmt.Errorf("Error opening config file %s: %s", configFilePath, err)
+	}
+	defer file.Close()
+
+	config, err := broker.NewConfig(file)
+	if err != nil {
+		return broker.Config{}, fmt.Errorf("Error validating config file: %v", err)
+	}
+	return config, nil
+}
+
+func prepareConfig(ctx context.Context, config *broker.Config, providerConfig *provider.Config, client aiven.Client) error {
+	if !skipPlanValidation {
+		if err := providerConfig.ValidatePlans(ctx, client); err != nil {
+			return fmt.Errorf("Error validating plans: %v", err)
+		}
+		if err := providerConfig.ValidateProjectVPCs(ctx, client); err != nil {
+			return fmt.Errorf("Error validating project VPCs: %v", err)
+		}
+	}
+
+	if err := config.AddPlanSchemas(providerConfig.PlanSchemas); err != nil {
+		return fmt.Errorf("Error generating plan schemas: %v", err)
+	}
+
+	if err := config.AddPlanMaintenanceInfo(providerConfig.PlanMaintenanceInfo); err != nil {
+		return fmt.Errorf("Error generating plan maintenance info: %v", err)
+	}
+	return nil
+}
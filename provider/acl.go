@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// Permissions bindings to Elasticsearch and OpenSearch services can be given
+// with the permission parameter. Bindings have admin, or full, access unless
+// they ask for less.
+const (
+	PermissionRead      = "read"
+	PermissionReadWrite = "readwrite"
+	PermissionAdmin     = "admin"
+)
+
+var permissions = []string{PermissionRead, PermissionReadWrite, PermissionAdmin}
+
+// Access bindings to Elasticsearch and OpenSearch services can be given with
+// the access parameter. Bindings with dashboards access can read the indexes
+// through Kibana or OpenSearch Dashboards, which stores its own objects in
+// the dashboards index.
+const (
+	AccessAPI        = "api"
+	AccessDashboards = "dashboards"
+)
+
+var accesses = []string{AccessAPI, AccessDashboards}
+
+func dashboardsIndex(serviceType string) string {
+	if serviceType == "opensearch" {
+		return ".opensearch_dashboards*"
+	}
+	return ".kibana*"
+}
+
+// dashboardsComponent is the service component which serves the dashboards,
+// and its name.
+func dashboardsComponent(serviceType string) (string, string) {
+	if serviceType == "opensearch" {
+		return "opensearch_dashboards", "OpenSearch Dashboards"
+	}
+	return "kibana", "Kibana"
+}
+
+var fullAccessRules = []aiven.ACLRule{{Index: "*", Permission: PermissionAdmin}}
+
+func supportsACLs(serviceType string) bool {
+	return serviceType == "elasticsearch" || serviceType == "opensearch"
+}
+
+// bindingACLRules works out the access a binding asked for. Bindings without
+// parameters get full access.
+func (c *Config) bindingACLRules(serviceID, planID string, parameters BindParameters) ([]aiven.ACLRule, error) {
+	if parameters.Access != "" && !contains(accesses, parameters.Access) {
+		return nil, invalidParameters(fmt.Errorf("Invalid access: %s, must be one of %s", parameters.Access, strings.Join(accesses, ", ")))
+	}
+	dashboards := parameters.Access == AccessDashboards
+	if parameters.Permission == "" && parameters.IndexPrefix == "" && !dashboards {
+		return fullAccessRules, nil
+	}
+
+	permission := parameters.Permission
+	if permission == "" {
+		permission = PermissionAdmin
+		if dashboards {
+			permission = PermissionRead
+		}
+	}
+	if !contains(permissions, permission) {
+		return nil, invalidParameters(fmt.Errorf("Invalid permission: %s, must be one of %s", permission, strings.Join(permissions, ", ")))
+	}
+	if dashboards && permission != PermissionRead {
+		return nil, invalidParameters(fmt.Errorf("access dashboards cannot be combined with permission %s", permission))
+	}
+
+	index := "*"
+	if parameters.IndexPrefix != "" {
+		if !indexPrefixPattern.MatchString(parameters.IndexPrefix) {
+			return nil, invalidParameters(fmt.Errorf("Invalid index_prefix: %s", parameters.IndexPrefix))
+		}
+		index = parameters.IndexPrefix
+		if !strings.HasSuffix(index, "*") {
+			index += "*"
+		}
+	}
+
+	plan, err := c.FindPlan(serviceID, planID)
+	if err != nil {
+		return nil, planNotFound(err)
+	}
+	if !supportsACLs(plan.ServiceType) {
+		if parameters.Permission == "" && parameters.IndexPrefix == "" {
+			return nil, invalidParameters(fmt.Errorf("access is not supported by %s services", plan.ServiceType))
+		}
+		return nil, invalidParameters(fmt.Errorf("permission and index_prefix are not supported by %s services", plan.ServiceType))
+	}
+	rules := []aiven.ACLRule{{Index: index, Permission: permission}}
+	if dashboards {
+		rules = append(rules, aiven.ACLRule{Index: dashboardsIndex(plan.ServiceType), Permission: PermissionAdmin})
+	}
+	return rules, nil
+}
+
+// hasDashboardsAccess reports whether the user's rules are those of a
+// binding with dashboards access.
+func hasDashboardsAccess(aclConfig *aiven.ACLConfig, serviceType, username string) bool {
+	if aclConfig == nil || !aclConfig.Enabled {
+		return false
+	}
+	i := findACL(aclConfig, username)
+	if i < 0 {
+		return false
+	}
+	for _, rule := range aclConfig.ACLs[i].Rules {
+		if rule.Index == dashboardsIndex(serviceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexPrefixPattern matches index names, which may include wildcards.
+var indexPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._*-]*$`)
+
+func isFullAccess(rules []aiven.ACLRule) bool {
+	return len(rules) == 1 && rules[0] == fullAccessRules[0]
+}
+
+// grantACL gives the user access to the service's indexes. ACLs are only
+// enabled once a binding asks for less than full access, so services whose
+// bindings all have full access are left as they were.
+func (ap *AivenProvider) grantACL(ctx context.Context, project, serviceName string, service *aiven.Service, username string, rules []aiven.ACLRule) error {
+	aclConfig, err := ap.Client.GetACLConfig(ctx, &aiven.GetACLConfigInput{
+		Project:     project,
+		ServiceName: serviceName,
+		ServiceType: service.ServiceType,
+	})
+	if err != nil {
+		return err
+	}
+	if !aclConfig.Enabled && isFullAccess(rules) {
+		return nil
+	}
+
+	if !aclConfig.Enabled {
+		// Enabling ACLs takes access away from users without rules, so the
+		// users of earlier bindings keep the full access they had.
+		for _, user := range service.Users {
+			if user.Type != "primary" && user.Username != username && findACL(aclConfig, user.Username) < 0 {
+				aclConfig.ACLs = append(aclConfig.ACLs, aiven.ACL{Username: user.Username, Rules: fullAccessRules})
+			}
+		}
+		aclConfig.Enabled = true
+	}
+
+	acl := aiven.ACL{Username: username, Rules: rules}
+	if i := findACL(aclConfig, username); i >= 0 {
+		aclConfig.ACLs[i] = acl
+	} else {
+		aclConfig.ACLs = append(aclConfig.ACLs, acl)
+	}
+
+	return ap.Client.UpdateACLConfig(ctx, &aiven.UpdateACLConfigInput{
+		Project:     project,
+		ServiceName: serviceName,
+		ServiceType: service.ServiceType,
+		ACLConfig:   *aclConfig,
+	})
+}
+
+// revokeACL removes the user's rules, if it has any.
+func (ap *AivenProvider) revokeACL(ctx context.Context, project, serviceName, serviceType, username string) error {
+	aclConfig, err := ap.Client.GetACLConfig(ctx, &aiven.GetACLConfigInput{
+		Project:     project,
+		ServiceName: serviceName,
+		ServiceType: serviceType,
+	})
+	if err != nil {
+		return err
+	}
+
+	i := findACL(aclConfig, username)
+	if i < 0 {
+		return nil
+	}
+	aclConfig.ACLs = append(aclConfig.ACLs[:i], aclConfig.ACLs[i+1:]...)
+
+	return ap.Client.UpdateACLConfig(ctx, &aiven.UpdateACLConfigInput{
+		Project:     project,
+		ServiceName: serviceName,
+		ServiceType: serviceType,
+		ACLConfig:   *aclConfig,
+	})
+}
+
+func findACL(aclConfig *aiven.ACLConfig, username string) int {
+	for i, acl := range aclConfig.ACLs {
+		if acl.Username == username {
+			return i
+		}
+	}
+	return -1
+}
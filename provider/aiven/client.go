@@ -2,29 +2,71 @@ package aiven
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strings"
 	"time"
+
+	"code.cloudfoundry.org/lager"
 )
 
 //go:generate counterfeiter -o fakes/fake_client.go . Client
 type Client interface {
-	CreateService(params *CreateServiceInput) (string, error)
-	GetService(params *GetServiceInput) (*Service, error)
-	DeleteService(params *DeleteServiceInput) error
-	CreateServiceUser(params *CreateServiceUserInput) (string, error)
-	DeleteServiceUser(params *DeleteServiceUserInput) (string, error)
-	UpdateService(params *UpdateServiceInput) (string, error)
+	CreateService(ctx context.Context, params *CreateServiceInput) (string, error)
+	GetService(ctx context.Context, params *GetServiceInput) (*Service, error)
+	DeleteService(ctx context.Context, params *DeleteServiceInput) error
+	CreateServiceUser(ctx context.Context, params *CreateServiceUserInput) (string, error)
+	GetServiceUser(ctx context.Context, params *GetServiceUserInput) (*User, error)
+	DeleteServiceUser(ctx context.Context, params *DeleteServiceUserInput) (string, error)
+	ListServiceUsers(ctx context.Context, params *ListServiceUsersInput) ([]User, error)
+	CreateServiceDatabase(ctx context.Context, params *CreateServiceDatabaseInput) error
+	DeleteServiceDatabase(ctx context.Context, params *DeleteServiceDatabaseInput) error
+	CreateConnectionPool(ctx context.Context, params *CreateConnectionPoolInput) error
+	DeleteConnectionPool(ctx context.Context, params *DeleteConnectionPoolInput) error
+	ResetServiceUserCredentials(ctx context.Context, params *ResetServiceUserCredentialsInput) (string, error)
+	UpdateService(ctx context.Context, params *UpdateServiceInput) (string, error)
+	ListServices(ctx context.Context, params *ListServicesInput) ([]Service, error)
+	UpdateServiceTags(ctx context.Context, params *UpdateServiceTagsInput) error
+	PowerService(ctx context.Context, params *PowerServiceInput) error
+	ListMaintenanceUpdates(ctx context.Context, params *ListMaintenanceUpdatesInput) ([]MaintenanceUpdate, error)
+	StartMaintenance(ctx context.Context, params *StartMaintenanceInput) error
+	GetServiceDiskUsage(ctx context.Context, params *GetServiceDiskUsageInput) (float64, error)
+	GetServicePlans(ctx context.Context, params *GetServicePlansInput) ([]ServicePlan, error)
+	GetACLConfig(ctx context.Context, params *GetACLConfigInput) (*ACLConfig, error)
+	UpdateACLConfig(ctx context.Context, params *UpdateACLConfigInput) error
+	GetProjectCA(ctx context.Context, params *GetProjectCAInput) (string, error)
+	CreateIntegrationEndpoint(ctx context.Context, params *CreateIntegrationEndpointInput) (string, error)
+	ListIntegrationEndpoints(ctx context.Context, params *ListIntegrationEndpointsInput) ([]IntegrationEndpoint, error)
+	DeleteIntegrationEndpoint(ctx context.Context, params *DeleteIntegrationEndpointInput) error
+	CreateServiceIntegration(ctx context.Context, params *CreateServiceIntegrationInput) (string, error)
+	ListServiceIntegrations(ctx context.Context, params *ListServiceIntegrationsInput) ([]ServiceIntegration, error)
+	DeleteServiceIntegration(ctx context.Context, params *DeleteServiceIntegrationInput) error
+	ListServiceBackups(ctx context.Context, params *ListServiceBackupsInput) ([]ServiceBackup, error)
+	ListProjectVPCs(ctx context.Context, params *ListProjectVPCsInput) ([]ProjectVPC, error)
+	CreateStaticIP(ctx context.Context, params *CreateStaticIPInput) (*StaticIP, error)
+	ListStaticIPs(ctx context.Context, params *ListStaticIPsInput) ([]StaticIP, error)
+	DeleteStaticIP(ctx context.Context, params *DeleteStaticIPInput) error
+	AssignProjectToBillingGroup(ctx context.Context, params *AssignProjectToBillingGroupInput) error
 }
 
 type HttpClient struct {
-	BaseURL    string
-	Token      string
+	BaseURL string
+	Token   string
+	// Project is used for requests which do not name a project of their own.
 	Project    string
 	HTTPClient *http.Client
+	// Retry is how requests which fail with transient errors are retried.
+	Retry RetryPolicy
+	// RateLimiter, if set, paces every request, including retries.
+	RateLimiter *RateLimiter
+	// Logger, if set, gets a debug log of every response, with how many
+	// attempts the request took.
+	Logger lager.Logger
 }
 
 func NewHttpClient(baseURL, token, project string) *HttpClient {
@@ -32,7 +74,8 @@ func NewHttpClient(baseURL, token, project string) *HttpClient {
 		BaseURL:    baseURL,
 		Token:      token,
 		Project:    project,
-		HTTPClient: &http.Client{},
+		HTTPClient: DefaultTransportConfig.HTTPClient(),
+		Retry:      DefaultRetryPolicy,
 	}
 }
 
@@ -45,19 +88,98 @@ func (p ErrInvalidUpdate) Error() string {
 }
 
 type CreateServiceInput struct {
-	Cloud       string     `json:"cloud,omitempty"`
-	GroupName   string     `json:"group_name,omitempty"`
-	Plan        string     `json:"plan,omitempty"`
-	ServiceName string     `json:"service_name"`
-	ServiceType string     `json:"service_type"`
-	UserConfig  UserConfig `json:"user_config"`
+	Project string `json:"-"`
+	Cloud   string `json:"cloud,omitempty"`
+	// ProjectVPCID creates the service in a VPC of the project, which must
+	// be in the service's cloud.
+	ProjectVPCID string            `json:"project_vpc_id,omitempty"`
+	GroupName    string            `json:"group_name,omitempty"`
+	Plan         string            `json:"plan,omitempty"`
+	ServiceName  string            `json:"service_name"`
+	ServiceType  string            `json:"service_type"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	// DiskSpaceMB is the service's total disk space, which can be more
+	// than the plan's. Aiven gives it the plan's when it is omitted.
+	DiskSpaceMB int `json:"disk_space_mb,omitempty"`
+	// StaticIPs are the IDs of static IP addresses to associate with the
+	// service, which it uses if its user config turns static_ips on.
+	StaticIPs   []string     `json:"static_ips,omitempty"`
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+	// TechEmails are who Aiven sends notifications about the service to,
+	// as well as the project's members.
+	TechEmails []TechEmail `json:"tech_emails,omitempty"`
+	// TerminationProtection stops the service being deleted until it is
+	// turned off. Aiven leaves it off when it is omitted.
+	TerminationProtection *bool      `json:"termination_protection,omitempty"`
+	UserConfig            UserConfig `json:"user_config"`
+}
+
+// TechEmail is a contact for notifications about a service.
+type TechEmail struct {
+	Email string `json:"email"`
+}
+
+// Maintenance is the weekly window in which Aiven applies maintenance
+// updates to a service. Aiven picks the window when it is omitted.
+type Maintenance struct {
+	DOW  string `json:"dow,omitempty"`
+	Time string `json:"time,omitempty"`
+}
+
+// ServiceMaintenance is a service's maintenance window, and the maintenance
+// updates waiting for it.
+type ServiceMaintenance struct {
+	DOW     string              `json:"dow"`
+	Time    string              `json:"time"`
+	Updates []MaintenanceUpdate `json:"updates"`
+}
+
+// MaintenanceUpdate is a pending maintenance update, such as a minor version
+// upgrade or security patch, which Aiven applies in the maintenance window,
+// or by the deadline, unless it is started sooner.
+type MaintenanceUpdate struct {
+	Description string `json:"description"`
+	StartAfter  string `json:"start_after"`
+	Deadline    string `json:"deadline"`
+}
+
+type ListMaintenanceUpdatesInput struct {
+	Project     string
+	ServiceName string
+}
+
+type StartMaintenanceInput struct {
+	Project     string
+	ServiceName string
+}
+
+type GetServiceDiskUsageInput struct {
+	Project     string
+	ServiceName string
+}
+
+type serviceMetricsRequest struct {
+	Period string `json:"period"`
+}
+
+// serviceMetricsResponse holds Aiven's service metrics, each a table whose
+// first column is the time and whose other columns are the values of the
+// service's nodes.
+type serviceMetricsResponse struct {
+	Metrics map[string]struct {
+		Data struct {
+			Rows [][]interface{} `json:"rows"`
+		} `json:"data"`
+	} `json:"metrics"`
 }
 
 type DeleteServiceInput struct {
+	Project     string
 	ServiceName string
 }
 
 type CreateServiceUserInput struct {
+	Project     string `json:"-"`
 	ServiceName string `json:"-"`
 	Username    string `json:"username"`
 }
@@ -71,14 +193,75 @@ type User struct {
 	Password string `json:"password"`
 	Type     string `json:"type"`
 	Username string `json:"username"`
+	// AccessCert and AccessKey are the PEM encoded client certificate and
+	// key of Kafka users.
+	AccessCert string `json:"access_cert"`
+	AccessKey  string `json:"access_key"`
+}
+
+type GetServiceUserInput struct {
+	Project     string
+	ServiceName string
+	Username    string
+}
+
+type GetServiceUserResponse struct {
+	User User `json:"user"`
+}
+
+type ListServiceUsersInput struct {
+	Project     string
+	ServiceName string
+}
+
+type ListServiceUsersResponse struct {
+	Service struct {
+		Users []User `json:"users"`
+	} `json:"service"`
+}
+
+type CreateServiceDatabaseInput struct {
+	Project     string `json:"-"`
+	ServiceName string `json:"-"`
+	Database    string `json:"database"`
+}
+
+type DeleteServiceDatabaseInput struct {
+	Project     string
+	ServiceName string
+	Database    string
+}
+
+type CreateConnectionPoolInput struct {
+	Project     string `json:"-"`
+	ServiceName string `json:"-"`
+	PoolName    string `json:"pool_name"`
+	Database    string `json:"database"`
+	Username    string `json:"username"`
+	PoolSize    int    `json:"pool_size"`
+	PoolMode    string `json:"pool_mode,omitempty"`
+}
+
+type DeleteConnectionPoolInput struct {
+	Project     string
+	ServiceName string
+	PoolName    string
 }
 
 type DeleteServiceUserInput struct {
+	Project     string
+	ServiceName string
+	Username    string
+}
+
+type ResetServiceUserCredentialsInput struct {
+	Project     string
 	ServiceName string
 	Username    string
 }
 
 type GetServiceInput struct {
+	Project     string
 	ServiceName string
 }
 
@@ -86,22 +269,163 @@ type GetServiceResponse struct {
 	Service Service `json:"service"`
 }
 
+type ListServicesInput struct {
+	Project string
+}
+
+type ListServicesResponse struct {
+	Services []Service `json:"services"`
+}
+
 type Service struct {
-	State            ServiceStatus    `json:"state"`
-	UpdateTime       time.Time        `json:"update_time"`
-	ServiceUriParams ServiceUriParams `json:"service_uri_params"`
-	ServiceType      string           `json:"service_type"`
+	ServiceName      string             `json:"service_name"`
+	Plan             string             `json:"plan"`
+	CloudName        string             `json:"cloud_name"`
+	ProjectVPCID     string             `json:"project_vpc_id"`
+	DiskSpaceMB      int                `json:"disk_space_mb"`
+	State            ServiceStatus      `json:"state"`
+	UpdateTime       time.Time          `json:"update_time"`
+	ServiceUriParams ServiceUriParams   `json:"service_uri_params"`
+	ServiceType      string             `json:"service_type"`
+	Components       []ServiceComponent `json:"components"`
+	Tags             map[string]string  `json:"tags"`
+	UserConfig       ServiceUserConfig  `json:"user_config"`
+	Users            []User             `json:"users"`
+	Databases        []string           `json:"databases"`
+	ConnectionPools  []ConnectionPool   `json:"connection_pools"`
+	Maintenance      ServiceMaintenance `json:"maintenance"`
+	NodeStates       []NodeState        `json:"node_states"`
+}
+
+// NodeState is the state of one of a service's nodes, such as "running" or
+// "setting_up_vm", and how far along any work on it is.
+type NodeState struct {
+	Name            string         `json:"name"`
+	State           string         `json:"state"`
+	ProgressUpdates []NodeProgress `json:"progress_updates"`
+}
+
+// NodeRunning is the state of nodes which are ready.
+const NodeRunning = "running"
+
+// NodeProgress is how far a node has got through a phase of its work, such
+// as streaming a base backup. Current, Min and Max are only given for
+// phases whose progress Aiven can measure.
+type NodeProgress struct {
+	Phase     string `json:"phase"`
+	Completed bool   `json:"completed"`
+	Current   *int64 `json:"current"`
+	Min       *int64 `json:"min"`
+	Max       *int64 `json:"max"`
+	Unit      string `json:"unit"`
+}
+
+// ConnectionPool is a PgBouncer pool of connections to a PostgreSQL
+// database.
+type ConnectionPool struct {
+	PoolName string `json:"pool_name"`
+	Database string `json:"database"`
+	Username string `json:"username"`
+	PoolSize int    `json:"pool_size"`
+	PoolMode string `json:"pool_mode"`
+}
+
+// ServiceUserConfig is the part of a service's user config which is read back
+// from Aiven.
+type ServiceUserConfig struct {
+	IPFilter     IPFilter        `json:"ip_filter"`
+	StaticIPs    bool            `json:"static_ips"`
+	PublicAccess map[string]bool `json:"public_access"`
+
+	ElasticsearchVersion string `json:"elasticsearch_version"`
+	KafkaVersion         string `json:"kafka_version"`
+	OpenSearchVersion    string `json:"opensearch_version"`
+	PGVersion            string `json:"pg_version"`
+	RedisVersion         string `json:"redis_version"`
+}
+
+// EngineVersion is the version of the service's engine, or empty if the
+// service type does not have one.
+func (c ServiceUserConfig) EngineVersion(serviceType string) string {
+	switch serviceType {
+	case "elasticsearch":
+		return c.ElasticsearchVersion
+	case "kafka":
+		return c.KafkaVersion
+	case "opensearch":
+		return c.OpenSearchVersion
+	case "pg":
+		return c.PGVersion
+	case "redis":
+		return c.RedisVersion
+	default:
+		return ""
+	}
+}
+
+// IPFilter is a list of networks. Aiven may return each entry either as a
+// string or as an object with a network and a description.
+type IPFilter []string
+
+func (f *IPFilter) UnmarshalJSON(data []byte) error {
+	entries := []json.RawMessage{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	networks := IPFilter{}
+	for _, entry := range entries {
+		var network string
+		if err := json.Unmarshal(entry, &network); err != nil {
+			object := struct {
+				Network string `json:"network"`
+			}{}
+			if err := json.Unmarshal(entry, &object); err != nil {
+				return err
+			}
+			network = object.Network
+		}
+		networks = append(networks, network)
+	}
+	*f = networks
+	return nil
+}
+
+type ServiceComponent struct {
+	Component                 string `json:"component"`
+	Host                      string `json:"host"`
+	Port                      int    `json:"port"`
+	Route                     string `json:"route"`
+	Usage                     string `json:"usage"`
+	KafkaAuthenticationMethod string `json:"kafka_authentication_method,omitempty"`
 }
 
 type ServiceStatus string
 
+// The states Aiven documents for services, and TERMINATING, which it does
+// not document but reports.
 const (
 	Running     ServiceStatus = "RUNNING"
 	Rebuilding  ServiceStatus = "REBUILDING"
 	Rebalancing ServiceStatus = "REBALANCING"
 	PowerOff    ServiceStatus = "POWEROFF"
+	// Terminating is reported by services which are being deleted.
+	Terminating ServiceStatus = "TERMINATING"
 )
 
+// ServiceStatuses are the states Aiven is known to report.
+var ServiceStatuses = []ServiceStatus{Running, Rebuilding, Rebalancing, PowerOff, Terminating}
+
+// Known reports whether the status is one Aiven is known to report.
+func (s ServiceStatus) Known() bool {
+	for _, status := range ServiceStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
 type ServiceUriParams struct {
 	Host     string `json:"host"`
 	Password string `json:"password"`
@@ -110,9 +434,233 @@ type ServiceUriParams struct {
 }
 
 type UpdateServiceInput struct {
-	ServiceName string     `json:"-"`
-	Plan        string     `json:"plan,omitempty"`
-	UserConfig  UserConfig `json:"user_config"`
+	Project     string `json:"-"`
+	ServiceName string `json:"-"`
+	Cloud       string `json:"cloud,omitempty"`
+	// ProjectVPCID moves the service into a VPC of the project.
+	ProjectVPCID string       `json:"project_vpc_id,omitempty"`
+	Plan         string       `json:"plan,omitempty"`
+	ServiceType  string       `json:"service_type,omitempty"`
+	Maintenance  *Maintenance `json:"maintenance,omitempty"`
+	// DiskSpaceMB is left as it is when it is omitted. Aiven can grow a
+	// service's disk, but not shrink it.
+	DiskSpaceMB int `json:"disk_space_mb,omitempty"`
+	// TechEmails are left as they are when they are omitted, and removed
+	// when they are empty.
+	TechEmails *[]TechEmail `json:"tech_emails,omitempty"`
+	// TerminationProtection is left as it is when it is omitted.
+	TerminationProtection *bool      `json:"termination_protection,omitempty"`
+	UserConfig            UserConfig `json:"user_config"`
+}
+
+type UpdateServiceTagsInput struct {
+	Project     string            `json:"-"`
+	ServiceName string            `json:"-"`
+	Tags        map[string]string `json:"tags"`
+}
+
+// PowerServiceInput powers a service off, or back on. Powering a service off
+// stops its nodes, but keeps its data and configuration.
+type PowerServiceInput struct {
+	Project     string `json:"-"`
+	ServiceName string `json:"-"`
+	Powered     bool   `json:"powered"`
+}
+
+type GetServicePlansInput struct {
+	Project     string
+	ServiceType string
+}
+
+type ListServiceTypesResponse struct {
+	ServiceTypes map[string]struct {
+		ServicePlans []ServicePlan `json:"service_plans"`
+	} `json:"service_types"`
+}
+
+type ServicePlan struct {
+	ServicePlan string `json:"service_plan"`
+	// DiskSpaceMB is the disk space services on the plan get by default,
+	// and DiskSpaceCapMB the most they can be given.
+	DiskSpaceMB    int `json:"disk_space_mb"`
+	DiskSpaceCapMB int `json:"disk_space_cap_mb"`
+	// Regions is keyed by the names of the clouds the plan is available in.
+	Regions map[string]interface{} `json:"regions"`
+}
+
+// ACLConfig controls which indexes the users of an Elasticsearch or
+// OpenSearch service can access. Users without rules have no access while it
+// is enabled, apart from the primary user.
+type ACLConfig struct {
+	ACLs        []ACL `json:"acls"`
+	Enabled     bool  `json:"enabled"`
+	ExtendedACL bool  `json:"extendedAcl"`
+}
+
+type ACL struct {
+	Username string    `json:"username"`
+	Rules    []ACLRule `json:"rules"`
+}
+
+type ACLRule struct {
+	Index      string `json:"index"`
+	Permission string `json:"permission"`
+}
+
+// GetACLConfigInput names the service whose ACL config is wanted.
+// ServiceType is elasticsearch or opensearch.
+type GetACLConfigInput struct {
+	Project     string
+	ServiceName string
+	ServiceType string
+}
+
+type UpdateACLConfigInput struct {
+	Project     string
+	ServiceName string
+	ServiceType string
+	ACLConfig   ACLConfig
+}
+
+type GetProjectCAInput struct {
+	Project string
+}
+
+type GetProjectCAResponse struct {
+	Certificate string `json:"certificate"`
+}
+
+// Integration endpoints are destinations outside of Aiven, such as a
+// Prometheus server, which services can be integrated with.
+type IntegrationEndpoint struct {
+	EndpointID   string `json:"endpoint_id"`
+	EndpointName string `json:"endpoint_name"`
+	EndpointType string `json:"endpoint_type"`
+}
+
+type CreateIntegrationEndpointInput struct {
+	Project      string                 `json:"-"`
+	EndpointName string                 `json:"endpoint_name"`
+	EndpointType string                 `json:"endpoint_type"`
+	UserConfig   map[string]interface{} `json:"user_config"`
+}
+
+type IntegrationEndpointResponse struct {
+	IntegrationEndpoint IntegrationEndpoint `json:"service_integration_endpoint"`
+}
+
+type ListIntegrationEndpointsInput struct {
+	Project string
+}
+
+type ListIntegrationEndpointsResponse struct {
+	IntegrationEndpoints []IntegrationEndpoint `json:"service_integration_endpoints"`
+}
+
+type DeleteIntegrationEndpointInput struct {
+	Project    string
+	EndpointID string
+}
+
+type ServiceIntegration struct {
+	ServiceIntegrationID string `json:"service_integration_id"`
+	IntegrationType      string `json:"integration_type"`
+	SourceService        string `json:"source_service"`
+	DestEndpointID       string `json:"dest_endpoint_id"`
+}
+
+type CreateServiceIntegrationInput struct {
+	Project         string `json:"-"`
+	IntegrationType string `json:"integration_type"`
+	SourceService   string `json:"source_service"`
+	DestEndpointID  string `json:"dest_endpoint_id"`
+}
+
+type ServiceIntegrationResponse struct {
+	ServiceIntegration ServiceIntegration `json:"service_integration"`
+}
+
+type ListServiceIntegrationsInput struct {
+	Project     string
+	ServiceName string
+}
+
+type ListServiceIntegrationsResponse struct {
+	ServiceIntegrations []ServiceIntegration `json:"service_integrations"`
+}
+
+type DeleteServiceIntegrationInput struct {
+	Project              string
+	ServiceIntegrationID string
+}
+
+type ListServiceBackupsInput struct {
+	Project     string
+	ServiceName string
+}
+
+type ListServiceBackupsResponse struct {
+	Backups []ServiceBackup `json:"backups"`
+}
+
+type ServiceBackup struct {
+	BackupName string    `json:"backup_name"`
+	BackupTime time.Time `json:"backup_time"`
+	DataSize   int64     `json:"data_size"`
+}
+
+type ListProjectVPCsInput struct {
+	Project string
+}
+
+type ListProjectVPCsResponse struct {
+	VPCs []ProjectVPC `json:"vpcs"`
+}
+
+type ProjectVPC struct {
+	ProjectVPCID string `json:"project_vpc_id"`
+	CloudName    string `json:"cloud_name"`
+	NetworkCIDR  string `json:"network_cidr"`
+	State        string `json:"state"`
+}
+
+type CreateStaticIPInput struct {
+	Project   string `json:"-"`
+	CloudName string `json:"cloud_name"`
+}
+
+type ListStaticIPsInput struct {
+	Project string
+}
+
+type ListStaticIPsResponse struct {
+	StaticIPs []StaticIP `json:"static_ips"`
+}
+
+type DeleteStaticIPInput struct {
+	Project           string
+	StaticIPAddressID string
+}
+
+// StaticIP is a static IP address of a project. It is associated with a
+// service by passing its ID when the service is created.
+type StaticIP struct {
+	StaticIPAddressID string `json:"static_ip_address_id"`
+	IPAddress         string `json:"ip_address"`
+	CloudName         string `json:"cloud_name"`
+	ServiceName       string `json:"service_name"`
+	State             string `json:"state"`
+}
+
+// AssignProjectToBillingGroupInput moves a project's billing to a billing
+// group. Aiven bills services by the project they are in.
+type AssignProjectToBillingGroupInput struct {
+	Project        string `json:"-"`
+	BillingGroupID string `json:"-"`
+}
+
+type assignProjectsToBillingGroupRequest struct {
+	ProjectsNames []string `json:"projects_names"`
 }
 
 type AivenErrorResponse struct {
@@ -123,13 +671,15 @@ type AivenErrorResponse struct {
 	Message string `json:"message"`
 }
 
-func (a *HttpClient) CreateService(params *CreateServiceInput) (string, error) {
+var ErrServiceAlreadyExists = errors.New("Error creating service: a service with the same name already exists")
+
+func (a *HttpClient) CreateService(ctx context.Context, params *CreateServiceInput) (string, error) {
 	reqBody, err := json.Marshal(params)
 	if err != nil {
 		return "", err
 	}
 
-	res, err := a.do("POST", fmt.Sprintf("/project/%s/service", a.Project), reqBody)
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/project/%s/service", a.project(params.Project)), reqBody)
 	if err != nil {
 		return "", err
 	}
@@ -139,6 +689,9 @@ func (a *HttpClient) CreateService(params *CreateServiceInput) (string, error) {
 		return "", err
 	}
 
+	if res.StatusCode == http.StatusConflict {
+		return "", ErrServiceAlreadyExists
+	}
 	if res.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("Error creating service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
 	}
@@ -148,8 +701,14 @@ func (a *HttpClient) CreateService(params *CreateServiceInput) (string, error) {
 
 var ErrInstanceDoesNotExist = errors.New("Error deleting service: service instance does not exist")
 
-func (a *HttpClient) DeleteService(params *DeleteServiceInput) error {
-	res, err := a.do("DELETE", fmt.Sprintf("/project/%s/service/%s", a.Project, params.ServiceName), nil)
+// ErrServiceDoesNotExist is returned by lookups of a service, or of things
+// it has, when the service does not exist.
+var ErrServiceDoesNotExist = errors.New("Error getting service: service does not exist")
+
+var ErrTerminationProtectionEnabled = errors.New("Error deleting service: service has termination protection enabled")
+
+func (a *HttpClient) DeleteService(ctx context.Context, params *DeleteServiceInput) error {
+	res, err := a.do(ctx, "DELETE", fmt.Sprintf("/project/%s/service/%s", a.project(params.Project), params.ServiceName), nil)
 	if err != nil {
 		return err
 	}
@@ -167,16 +726,28 @@ func (a *HttpClient) DeleteService(params *DeleteServiceInput) error {
 	if err != nil {
 		return err
 	}
+
+	var errorResponse AivenErrorResponse
+	if jsonErr := json.Unmarshal(b, &errorResponse); jsonErr == nil &&
+		res.StatusCode >= 400 && res.StatusCode < 500 &&
+		strings.Contains(strings.ToLower(errorResponse.Message), "termination") {
+		return ErrTerminationProtectionEnabled
+	}
+
 	return fmt.Errorf("Error deleting service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
 }
 
-func (a *HttpClient) CreateServiceUser(params *CreateServiceUserInput) (string, error) {
+var ErrServiceUserNotSupported = errors.New("Error creating service user: service users are not supported by this service")
+
+var ErrServiceUserAlreadyExists = errors.New("Error creating service user: service user already exists")
+
+func (a *HttpClient) CreateServiceUser(ctx context.Context, params *CreateServiceUserInput) (string, error) {
 	reqBody, err := json.Marshal(params)
 	if err != nil {
 		return "", err
 	}
 
-	res, err := a.do("POST", fmt.Sprintf("/project/%s/service/%s/user", a.Project, params.ServiceName), reqBody)
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/project/%s/service/%s/user", a.project(params.Project), params.ServiceName), reqBody)
 	if err != nil {
 		return "", err
 	}
@@ -187,6 +758,18 @@ func (a *HttpClient) CreateServiceUser(params *CreateServiceUserInput) (string,
 		if err != nil {
 			return "", err
 		}
+
+		var errorResponse AivenErrorResponse
+		if jsonErr := json.Unmarshal(b, &errorResponse); jsonErr == nil &&
+			res.StatusCode >= 400 && res.StatusCode < 500 &&
+			strings.Contains(strings.ToLower(errorResponse.Message), "not supported") {
+			return "", ErrServiceUserNotSupported
+		}
+
+		if res.StatusCode == http.StatusConflict {
+			return "", ErrServiceUserAlreadyExists
+		}
+
 		return "", fmt.Errorf("Error creating service user: %d status code returned from Aiven: '%s'", res.StatusCode, b)
 	}
 
@@ -201,116 +784,920 @@ func (a *HttpClient) CreateServiceUser(params *CreateServiceUserInput) (string,
 	return createServiceUserResponse.User.Password, nil
 }
 
-func (a *HttpClient) DeleteServiceUser(params *DeleteServiceUserInput) (string, error) {
-	res, err := a.do("DELETE", fmt.Sprintf("/project/%s/service/%s/user/%s", a.Project, params.ServiceName, params.Username), nil)
+// ErrServiceUserDoesNotExist is returned when getting or deleting a user
+// which does not exist.
+var ErrServiceUserDoesNotExist = errors.New("Error finding service user: service user does not exist")
+
+// GetServiceUser returns the user, including the password Aiven keeps for
+// it.
+func (a *HttpClient) GetServiceUser(ctx context.Context, params *GetServiceUserInput) (*User, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/service/%s/user/%s", a.project(params.Project), params.ServiceName, params.Username), nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer res.Body.Close()
 
-	b, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return "", err
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrServiceUserDoesNotExist
 	}
-
 	if res.StatusCode != http.StatusOK {
-		var errorResponse AivenErrorResponse
-		jsonErr := json.Unmarshal(b, &errorResponse)
-
-		expectedMessageIfUserWasAlreadyDeleted := fmt.Sprintf("Service user '%s' does not exist", params.Username)
-		if jsonErr != nil || errorResponse.Message != expectedMessageIfUserWasAlreadyDeleted {
-			return "", fmt.Errorf("Error deleting service user: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
 		}
+		return nil, fmt.Errorf("Error getting service user: %d status code returned from Aiven: '%s'", res.StatusCode, b)
 	}
 
-	return string(b), nil
+	getServiceUserResponse := &GetServiceUserResponse{}
+	if err := json.NewDecoder(res.Body).Decode(getServiceUserResponse); err != nil {
+		return nil, err
+	}
+	if getServiceUserResponse.User.Password == "" {
+		return nil, errors.New("Error getting service user: password was empty")
+	}
+	return &getServiceUserResponse.User, nil
 }
 
-func (a *HttpClient) GetService(params *GetServiceInput) (*Service, error) {
-	res, err := a.do("GET", fmt.Sprintf("/project/%s/service/%s", a.Project, params.ServiceName), nil)
+// ListServiceUsers returns the users of a service, which Aiven lists with
+// the rest of the service.
+func (a *HttpClient) ListServiceUsers(ctx context.Context, params *ListServiceUsersInput) ([]User, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/service/%s", a.project(params.Project), params.ServiceName), nil)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrServiceDoesNotExist
+	}
 	if res.StatusCode != http.StatusOK {
 		b, err := ioutil.ReadAll(res.Body)
 		if err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("Error getting service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+		return nil, fmt.Errorf("Error listing service users: %d status code returned from Aiven: '%s'", res.StatusCode, b)
 	}
 
-	getServiceResponse := &GetServiceResponse{}
-	if err := json.NewDecoder(res.Body).Decode(getServiceResponse); err != nil {
+	listServiceUsersResponse := &ListServiceUsersResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listServiceUsersResponse); err != nil {
 		return nil, err
 	}
+	return listServiceUsersResponse.Service.Users, nil
+}
 
-	service := getServiceResponse.Service
+// ErrServiceDatabaseAlreadyExists is returned when creating a database
+// which already exists.
+var ErrServiceDatabaseAlreadyExists = errors.New("Error creating service database: database already exists")
 
-	if service.ServiceType == "" {
-		return nil, errors.New("Error getting service: no service type found in response JSON")
+func (a *HttpClient) CreateServiceDatabase(ctx context.Context, params *CreateServiceDatabaseInput) error {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return err
 	}
 
-	if service.State == "" {
-		return nil, errors.New("Error getting service: no state found in response JSON")
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/project/%s/service/%s/db", a.project(params.Project), params.ServiceName), reqBody)
+	if err != nil {
+		return err
 	}
+	defer res.Body.Close()
 
-	defaultTime := time.Time{}
-	if service.UpdateTime == defaultTime {
-		return nil, errors.New("Error getting service: no update_time found in response JSON")
+	if res.StatusCode == http.StatusConflict {
+		return ErrServiceDatabaseAlreadyExists
 	}
-
-	return &service, nil
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error creating service database: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
 }
 
-func (a *HttpClient) UpdateService(params *UpdateServiceInput) (string, error) {
-	reqBody, err := json.Marshal(params)
-	if err != nil {
-		return "", err
-	}
+// ErrServiceDatabaseDoesNotExist is returned when deleting a database which
+// does not exist.
+var ErrServiceDatabaseDoesNotExist = errors.New("Error deleting service database: database does not exist")
 
-	res, err := a.do("PUT", fmt.Sprintf("/project/%s/service/%s", a.Project, params.ServiceName), reqBody)
+func (a *HttpClient) DeleteServiceDatabase(ctx context.Context, params *DeleteServiceDatabaseInput) error {
+	res, err := a.do(ctx, "DELETE", fmt.Sprintf("/project/%s/service/%s/db/%s", a.project(params.Project), params.ServiceName, params.Database), nil)
 	if err != nil {
-		return "", err
+		return err
 	}
 	defer res.Body.Close()
-	b, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return "", err
-	}
 
-	if res.StatusCode == http.StatusBadRequest {
-		var errorResponse AivenErrorResponse
-		jsonErr := json.Unmarshal(b, &errorResponse)
-		if jsonErr != nil {
-			return "", fmt.Errorf("Error updating service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
-		}
-		return "", ErrInvalidUpdate{fmt.Sprintf("Invalid Update: %s", errorResponse.Message)}
+	if res.StatusCode == http.StatusNotFound {
+		return ErrServiceDatabaseDoesNotExist
 	}
-
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Error updating service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error deleting service database: %d status code returned from Aiven: '%s'", res.StatusCode, b)
 	}
-
-	return string(b), nil
+	return nil
 }
 
-func (a *HttpClient) do(method, path string, body []byte) (*http.Response, error) {
-	req, err := a.requestBuilder(method, path, body)
+// ErrConnectionPoolAlreadyExists is returned when creating a connection pool
+// which already exists.
+var ErrConnectionPoolAlreadyExists = errors.New("Error creating connection pool: connection pool already exists")
+
+func (a *HttpClient) CreateConnectionPool(ctx context.Context, params *CreateConnectionPoolInput) error {
+	reqBody, err := json.Marshal(params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	res, err := a.HTTPClient.Do(req)
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/project/%s/service/%s/connection_pool", a.project(params.Project), params.ServiceName), reqBody)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusConflict {
+		return ErrConnectionPoolAlreadyExists
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error creating connection pool: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+// ErrConnectionPoolDoesNotExist is returned when deleting a connection pool
+// which does not exist.
+var ErrConnectionPoolDoesNotExist = errors.New("Error deleting connection pool: connection pool does not exist")
+
+func (a *HttpClient) DeleteConnectionPool(ctx context.Context, params *DeleteConnectionPoolInput) error {
+	res, err := a.do(ctx, "DELETE", fmt.Sprintf("/project/%s/service/%s/connection_pool/%s", a.project(params.Project), params.ServiceName, params.PoolName), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrConnectionPoolDoesNotExist
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error deleting connection pool: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+func (a *HttpClient) DeleteServiceUser(ctx context.Context, params *DeleteServiceUserInput) (string, error) {
+	res, err := a.do(ctx, "DELETE", fmt.Sprintf("/project/%s/service/%s/user/%s", a.project(params.Project), params.ServiceName, params.Username), nil)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var errorResponse AivenErrorResponse
+		jsonErr := json.Unmarshal(b, &errorResponse)
+
+		expectedMessageIfUserWasAlreadyDeleted := fmt.Sprintf("Service user '%s' does not exist", params.Username)
+		if jsonErr == nil && errorResponse.Message == expectedMessageIfUserWasAlreadyDeleted {
+			return "", ErrServiceUserDoesNotExist
+		}
+		// Otherwise a 404 means the service itself is gone.
+		if res.StatusCode == http.StatusNotFound {
+			return "", ErrInstanceDoesNotExist
+		}
+		return "", fmt.Errorf("Error deleting service user: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	return string(b), nil
+}
+
+// ResetServiceUserCredentials gives the user a new password, which is
+// returned. The old password stops working.
+func (a *HttpClient) ResetServiceUserCredentials(ctx context.Context, params *ResetServiceUserCredentialsInput) (string, error) {
+	reqBody, err := json.Marshal(map[string]string{"operation": "reset-credentials"})
+	if err != nil {
+		return "", err
+	}
+
+	res, err := a.do(ctx, "PUT", fmt.Sprintf("/project/%s/service/%s/user/%s", a.project(params.Project), params.ServiceName, params.Username), reqBody)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("Error resetting service user credentials: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	getServiceResponse := &GetServiceResponse{}
+	if err := json.NewDecoder(res.Body).Decode(getServiceResponse); err != nil {
+		return "", err
+	}
+	for _, user := range getServiceResponse.Service.Users {
+		if user.Username == params.Username && user.Password != "" {
+			return user.Password, nil
+		}
+	}
+	return "", errors.New("Error resetting service user credentials: password was empty")
+}
+
+func (a *HttpClient) GetService(ctx context.Context, params *GetServiceInput) (*Service, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/service/%s", a.project(params.Project), params.ServiceName), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrServiceDoesNotExist
+	}
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error getting service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	getServiceResponse := &GetServiceResponse{}
+	if err := json.NewDecoder(res.Body).Decode(getServiceResponse); err != nil {
+		return nil, err
+	}
+
+	service := getServiceResponse.Service
+
+	if service.ServiceType == "" {
+		return nil, errors.New("Error getting service: no service type found in response JSON")
+	}
+
+	if service.State == "" {
+		return nil, errors.New("Error getting service: no state found in response JSON")
+	}
+
+	defaultTime := time.Time{}
+	if service.UpdateTime == defaultTime {
+		return nil, errors.New("Error getting service: no update_time found in response JSON")
+	}
+
+	return &service, nil
+}
+
+func (a *HttpClient) UpdateService(ctx context.Context, params *UpdateServiceInput) (string, error) {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := a.do(ctx, "PUT", fmt.Sprintf("/project/%s/service/%s", a.project(params.Project), params.ServiceName), reqBody)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if res.StatusCode == http.StatusBadRequest {
+		var errorResponse AivenErrorResponse
+		jsonErr := json.Unmarshal(b, &errorResponse)
+		if jsonErr != nil {
+			return "", fmt.Errorf("Error updating service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+		}
+		return "", ErrInvalidUpdate{fmt.Sprintf("Invalid Update: %s", errorResponse.Message)}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Error updating service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	return string(b), nil
+}
+
+func (a *HttpClient) ListServices(ctx context.Context, params *ListServicesInput) ([]Service, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/service", a.project(params.Project)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error listing services: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	listServicesResponse := &ListServicesResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listServicesResponse); err != nil {
+		return nil, err
+	}
+	return listServicesResponse.Services, nil
+}
+
+func (a *HttpClient) UpdateServiceTags(ctx context.Context, params *UpdateServiceTagsInput) error {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	res, err := a.do(ctx, "PUT", fmt.Sprintf("/project/%s/service/%s/tag", a.project(params.Project), params.ServiceName), reqBody)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error updating service tags: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+func (a *HttpClient) PowerService(ctx context.Context, params *PowerServiceInput) error {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	res, err := a.do(ctx, "PUT", fmt.Sprintf("/project/%s/service/%s", a.project(params.Project), params.ServiceName), reqBody)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error powering service: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+// ListMaintenanceUpdates returns the maintenance updates pending for the
+// service, which Aiven includes in the service.
+func (a *HttpClient) ListMaintenanceUpdates(ctx context.Context, params *ListMaintenanceUpdatesInput) ([]MaintenanceUpdate, error) {
+	service, err := a.GetService(ctx, &GetServiceInput{
+		Project:     params.Project,
+		ServiceName: params.ServiceName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service.Maintenance.Updates, nil
+}
+
+// StartMaintenance applies the service's pending maintenance updates now,
+// rather than in its maintenance window.
+func (a *HttpClient) StartMaintenance(ctx context.Context, params *StartMaintenanceInput) error {
+	res, err := a.do(ctx, "PUT", fmt.Sprintf("/project/%s/service/%s/maintenance/start", a.project(params.Project), params.ServiceName), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error starting maintenance: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+// GetServiceDiskUsage returns the percentage of the service's disk in use,
+// from the latest of the last hour's metrics. Services with several nodes
+// return the fullest node's.
+func (a *HttpClient) GetServiceDiskUsage(ctx context.Context, params *GetServiceDiskUsageInput) (float64, error) {
+	reqBody, err := json.Marshal(serviceMetricsRequest{Period: "hour"})
+	if err != nil {
+		return 0, err
+	}
+
+	// Metrics are queried with a POST, but it changes nothing.
+	res, err := a.doRequest(
+		ctx, "POST", fmt.Sprintf("/project/%s/service/%s/metrics", a.project(params.Project), params.ServiceName), reqBody, true,
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("Error getting service metrics: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	metrics := &serviceMetricsResponse{}
+	if err := json.NewDecoder(res.Body).Decode(metrics); err != nil {
+		return 0, err
+	}
+	rows := metrics.Metrics["disk_usage"].Data.Rows
+	if len(rows) == 0 {
+		return 0, errors.New("Error getting service metrics: no disk_usage metrics returned from Aiven")
+	}
+	usage, found := 0.0, false
+	for i, value := range rows[len(rows)-1] {
+		// The first column is the time.
+		if percent, ok := value.(float64); ok && i > 0 && (!found || percent > usage) {
+			usage, found = percent, true
+		}
+	}
+	if !found {
+		return 0, errors.New("Error getting service metrics: no disk_usage metrics returned from Aiven")
+	}
+	return usage, nil
+}
+
+func (a *HttpClient) GetServicePlans(ctx context.Context, params *GetServicePlansInput) ([]ServicePlan, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/service_types", a.project(params.Project)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error getting service plans: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	listServiceTypesResponse := &ListServiceTypesResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listServiceTypesResponse); err != nil {
+		return nil, err
+	}
+
+	serviceType, ok := listServiceTypesResponse.ServiceTypes[params.ServiceType]
+	if !ok {
+		return nil, fmt.Errorf("Error getting service plans: unknown service type %s", params.ServiceType)
+	}
+	return serviceType.ServicePlans, nil
+}
+
+func (a *HttpClient) GetACLConfig(ctx context.Context, params *GetACLConfigInput) (*ACLConfig, error) {
+	res, err := a.do(ctx, "GET", aclPath(a.project(params.Project), params.ServiceName, params.ServiceType), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrServiceDoesNotExist
+	}
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error getting ACL config: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	aclConfigResponse := map[string]ACLConfig{}
+	if err := json.NewDecoder(res.Body).Decode(&aclConfigResponse); err != nil {
+		return nil, err
+	}
+	aclConfig, ok := aclConfigResponse[aclConfigKey(params.ServiceType)]
+	if !ok {
+		return nil, errors.New("Error getting ACL config: no ACL config found in response JSON")
+	}
+	return &aclConfig, nil
+}
+
+func (a *HttpClient) UpdateACLConfig(ctx context.Context, params *UpdateACLConfigInput) error {
+	reqBody, err := json.Marshal(map[string]ACLConfig{
+		aclConfigKey(params.ServiceType): params.ACLConfig,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := a.do(ctx, "PUT", aclPath(a.project(params.Project), params.ServiceName, params.ServiceType), reqBody)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error updating ACL config: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+// GetProjectCA returns the PEM encoded certificate of the CA which signs the
+// certificates of the project's services.
+func (a *HttpClient) GetProjectCA(ctx context.Context, params *GetProjectCAInput) (string, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/kms/ca", a.project(params.Project)), nil)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("Error getting project CA: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	getProjectCAResponse := &GetProjectCAResponse{}
+	if err := json.NewDecoder(res.Body).Decode(getProjectCAResponse); err != nil {
+		return "", err
+	}
+	if getProjectCAResponse.Certificate == "" {
+		return "", errors.New("Error getting project CA: no certificate found in response JSON")
+	}
+	return getProjectCAResponse.Certificate, nil
+}
+
+func (a *HttpClient) CreateIntegrationEndpoint(ctx context.Context, params *CreateIntegrationEndpointInput) (string, error) {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/project/%s/integration_endpoint", a.project(params.Project)), reqBody)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("Error creating integration endpoint: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	integrationEndpointResponse := &IntegrationEndpointResponse{}
+	if err := json.NewDecoder(res.Body).Decode(integrationEndpointResponse); err != nil {
+		return "", err
+	}
+	if integrationEndpointResponse.IntegrationEndpoint.EndpointID == "" {
+		return "", errors.New("Error creating integration endpoint: no endpoint_id found in response JSON")
+	}
+	return integrationEndpointResponse.IntegrationEndpoint.EndpointID, nil
+}
+
+func (a *HttpClient) ListIntegrationEndpoints(ctx context.Context, params *ListIntegrationEndpointsInput) ([]IntegrationEndpoint, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/integration_endpoint", a.project(params.Project)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error listing integration endpoints: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	listIntegrationEndpointsResponse := &ListIntegrationEndpointsResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listIntegrationEndpointsResponse); err != nil {
+		return nil, err
+	}
+	return listIntegrationEndpointsResponse.IntegrationEndpoints, nil
+}
+
+func (a *HttpClient) DeleteIntegrationEndpoint(ctx context.Context, params *DeleteIntegrationEndpointInput) error {
+	res, err := a.do(ctx, "DELETE", fmt.Sprintf("/project/%s/integration_endpoint/%s", a.project(params.Project), params.EndpointID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error deleting integration endpoint: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+func (a *HttpClient) CreateServiceIntegration(ctx context.Context, params *CreateServiceIntegrationInput) (string, error) {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/project/%s/integration", a.project(params.Project)), reqBody)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("Error creating service integration: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	serviceIntegrationResponse := &ServiceIntegrationResponse{}
+	if err := json.NewDecoder(res.Body).Decode(serviceIntegrationResponse); err != nil {
+		return "", err
+	}
+	if serviceIntegrationResponse.ServiceIntegration.ServiceIntegrationID == "" {
+		return "", errors.New("Error creating service integration: no service_integration_id found in response JSON")
+	}
+	return serviceIntegrationResponse.ServiceIntegration.ServiceIntegrationID, nil
+}
+
+func (a *HttpClient) ListServiceIntegrations(ctx context.Context, params *ListServiceIntegrationsInput) ([]ServiceIntegration, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/service/%s/integration", a.project(params.Project), params.ServiceName), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error listing service integrations: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	listServiceIntegrationsResponse := &ListServiceIntegrationsResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listServiceIntegrationsResponse); err != nil {
+		return nil, err
+	}
+	return listServiceIntegrationsResponse.ServiceIntegrations, nil
+}
+
+// ListServiceBackups returns the backups a service can be forked from.
+func (a *HttpClient) ListServiceBackups(ctx context.Context, params *ListServiceBackupsInput) ([]ServiceBackup, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/service/%s/backups", a.project(params.Project), params.ServiceName), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrServiceDoesNotExist
+	}
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error listing service backups: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	listServiceBackupsResponse := &ListServiceBackupsResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listServiceBackupsResponse); err != nil {
+		return nil, err
+	}
+	return listServiceBackupsResponse.Backups, nil
+}
+
+// ListProjectVPCs returns the VPCs of a project, which services can be
+// created in.
+func (a *HttpClient) ListProjectVPCs(ctx context.Context, params *ListProjectVPCsInput) ([]ProjectVPC, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/vpcs", a.project(params.Project)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error listing project VPCs: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	listProjectVPCsResponse := &ListProjectVPCsResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listProjectVPCsResponse); err != nil {
+		return nil, err
+	}
+	return listProjectVPCsResponse.VPCs, nil
+}
+
+func (a *HttpClient) CreateStaticIP(ctx context.Context, params *CreateStaticIPInput) (*StaticIP, error) {
+	reqBody, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/project/%s/static-ips", a.project(params.Project)), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error creating static IP: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	staticIP := &StaticIP{}
+	if err := json.NewDecoder(res.Body).Decode(staticIP); err != nil {
+		return nil, err
+	}
+	return staticIP, nil
+}
+
+func (a *HttpClient) ListStaticIPs(ctx context.Context, params *ListStaticIPsInput) ([]StaticIP, error) {
+	res, err := a.do(ctx, "GET", fmt.Sprintf("/project/%s/static-ips", a.project(params.Project)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Error listing static IPs: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+
+	listStaticIPsResponse := &ListStaticIPsResponse{}
+	if err := json.NewDecoder(res.Body).Decode(listStaticIPsResponse); err != nil {
+		return nil, err
+	}
+	return listStaticIPsResponse.StaticIPs, nil
+}
+
+// DeleteStaticIP releases a static IP address. It succeeds if the address
+// has already been released.
+func (a *HttpClient) DeleteStaticIP(ctx context.Context, params *DeleteStaticIPInput) error {
+	res, err := a.do(ctx, "DELETE", fmt.Sprintf("/project/%s/static-ips/%s", a.project(params.Project), params.StaticIPAddressID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error deleting static IP: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+var ErrBillingGroupDoesNotExist = errors.New("Error assigning project to billing group: billing group does not exist")
+
+func (a *HttpClient) AssignProjectToBillingGroup(ctx context.Context, params *AssignProjectToBillingGroupInput) error {
+	reqBody, err := json.Marshal(assignProjectsToBillingGroupRequest{
+		ProjectsNames: []string{a.project(params.Project)},
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := a.do(ctx, "POST", fmt.Sprintf("/billing-group/%s/projects-assign", params.BillingGroupID), reqBody)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrBillingGroupDoesNotExist
+	}
+	if res.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error assigning project to billing group: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+func (a *HttpClient) DeleteServiceIntegration(ctx context.Context, params *DeleteServiceIntegrationInput) error {
+	res, err := a.do(ctx, "DELETE", fmt.Sprintf("/project/%s/integration/%s", a.project(params.Project), params.ServiceIntegrationID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("Error deleting service integration: %d status code returned from Aiven: '%s'", res.StatusCode, b)
+	}
+	return nil
+}
+
+func aclPath(project, serviceName, serviceType string) string {
+	return fmt.Sprintf("/project/%s/service/%s/%s/acl", project, serviceName, serviceType)
+}
+
+func aclConfigKey(serviceType string) string {
+	return serviceType + "_acl_config"
+}
+
+func (a *HttpClient) project(project string) string {
+	if project == "" {
+		return a.Project
+	}
+	return project
+}
+
+// do makes a request to Aiven, which is abandoned if the context is
+// cancelled or reaches its deadline. The error then wraps the context's.
+// do makes the request, treating GETs as reads and other methods as
+// changes.
+func (a *HttpClient) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	return a.doRequest(ctx, method, path, body, method == http.MethodGet || method == http.MethodHead)
+}
+
+// doRequest makes the request. Reads, including requests which only query
+// Aiven whatever their method, are paced as polls and retried on any 5xx.
+func (a *HttpClient) doRequest(ctx context.Context, method, path string, body []byte, read bool) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if a.RateLimiter != nil {
+			if err := a.RateLimiter.Wait(ctx, !read); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := a.requestBuilder(ctx, method, path, body)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := a.HTTPClient.Do(req)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fmt.Errorf("Error making request to Aiven: %w", ctxErr)
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return nil, fmt.Errorf("Error making request to Aiven: no response within %s: %w", a.HTTPClient.Timeout, err)
+			}
+			return nil, err
+		}
+		retry := a.Retry.shouldRetry(read, res, attempt)
+		if a.Logger != nil {
+			a.Logger.Debug("aiven-request", lager.Data{
+				"method":  method,
+				"path":    path,
+				"status":  res.StatusCode,
+				"attempt": attempt,
+				"retry":   retry,
+			})
+		}
+		if !retry {
+			return res, nil
+		}
+
+		delay := a.Retry.delay(res, attempt)
+		res.Body.Close()
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("Error making request to Aiven: %w", err)
+		}
 	}
-	return res, nil
 }
 
-func (a *HttpClient) requestBuilder(method, path string, body []byte) (*http.Request, error) {
-	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1%s", a.BaseURL, path), bytes.NewBuffer(body))
+func (a *HttpClient) requestBuilder(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1%s", a.BaseURL, path), bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -1,15 +1,20 @@
 package aiven_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	"code.cloudfoundry.org/lager"
+
 	"github.com/alphagov/paas-aiven-broker/provider/aiven"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/ghttp"
 )
 
@@ -22,12 +27,307 @@ var _ = Describe("Client", func() {
 	BeforeEach(func() {
 		aivenAPI = ghttp.NewServer()
 		aivenClient = aiven.NewHttpClient(aivenAPI.URL(), "token", "my-project")
+		// Requests are not retried, unless a test is about retrying.
+		aivenClient.Retry = aiven.RetryPolicy{MaxAttempts: 1}
 	})
 
 	AfterEach(func() {
 		aivenAPI.Close()
 	})
 
+	Describe("cancelling requests", func() {
+		var release chan struct{}
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			aivenAPI.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			})
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("abandons a hung request when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			_, err := aivenClient.GetService(ctx, &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error making request to Aiven: context canceled"))
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		})
+
+		It("abandons a hung request when the context reaches its deadline", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := aivenClient.DeleteService(ctx, &aiven.DeleteServiceInput{ServiceName: "my-service"})
+
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		})
+	})
+
+	Describe("timing out requests", func() {
+		var release chan struct{}
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			aivenAPI.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			})
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("gives up on Aiven if it does not respond within the request timeout", func() {
+			aivenClient.HTTPClient = aiven.TransportConfig{RequestTimeout: 100 * time.Millisecond}.HTTPClient()
+
+			start := time.Now()
+			_, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError(HavePrefix("Error making request to Aiven: no response within 100ms: ")))
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+
+	Describe("TransportConfig", func() {
+		It("makes a client with the timeouts", func() {
+			client := aiven.TransportConfig{
+				RequestTimeout:      time.Minute,
+				TLSHandshakeTimeout: 5 * time.Second,
+			}.HTTPClient()
+
+			Expect(client.Timeout).To(Equal(time.Minute))
+			transport := client.Transport.(*http.Transport)
+			Expect(transport.TLSHandshakeTimeout).To(Equal(5 * time.Second))
+			Expect(transport.DisableKeepAlives).To(BeFalse())
+			Expect(transport.Proxy).To(BeNil())
+		})
+
+		It("only uses a proxy from the environment if asked to", func() {
+			client := aiven.TransportConfig{ProxyFromEnvironment: true}.HTTPClient()
+
+			Expect(client.Transport.(*http.Transport).Proxy).ToNot(BeNil())
+		})
+
+		It("reuses a transport across requests", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusOK, "{}"), ghttp.RespondWith(http.StatusOK, "{}"))
+			aivenClient = aiven.NewHttpClient(aivenAPI.URL(), "token", "my-project")
+			transport := aivenClient.HTTPClient.Transport
+
+			Expect(aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "a"})).To(Succeed())
+			Expect(aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "b"})).To(Succeed())
+
+			Expect(aivenClient.HTTPClient.Transport).To(BeIdenticalTo(transport))
+			Expect(aivenClient.HTTPClient.Timeout).To(Equal(aiven.DefaultRequestTimeout))
+		})
+	})
+
+	Describe("retrying requests", func() {
+		var logBuffer *gbytes.Buffer
+
+		BeforeEach(func() {
+			aivenClient.Retry = aiven.RetryPolicy{MaxAttempts: 3}
+			logBuffer = gbytes.NewBuffer()
+			logger := lager.NewLogger("aiven-client")
+			logger.RegisterSink(lager.NewWriterSink(logBuffer, lager.DEBUG))
+			aivenClient.Logger = logger
+		})
+
+		It("retries reads which fail with a 5xx", func() {
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadGateway, "Bad Gateway"),
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+				ghttp.RespondWith(http.StatusOK, `{"service": {"service_type": "pg", "state": "RUNNING", "update_time": "2018-06-21T10:01:05Z"}}`),
+			)
+
+			service, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.State).To(Equal(aiven.Running))
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(3))
+			Expect(logBuffer).To(gbytes.Say(`"attempt":1,.*"retry":true,"status":502`))
+			Expect(logBuffer).To(gbytes.Say(`"attempt":2,.*"retry":true,"status":500`))
+			Expect(logBuffer).To(gbytes.Say(`"attempt":3,.*"retry":false,"status":200`))
+		})
+
+		It("gives up after the maximum attempts", func() {
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadGateway, "Bad Gateway"),
+				ghttp.RespondWith(http.StatusBadGateway, "Bad Gateway"),
+				ghttp.RespondWith(http.StatusBadGateway, "Bad Gateway"),
+			)
+
+			_, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error getting service: 502 status code returned from Aiven: 'Bad Gateway'"))
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("retries changes which are rate limited, or refused as unavailable with a Retry-After", func() {
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, "{}"),
+				ghttp.RespondWith(http.StatusServiceUnavailable, "{}", http.Header{"Retry-After": []string{"0"}}),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/v1/project/my-project/service/my-service"),
+					ghttp.RespondWith(http.StatusOK, "{}"),
+				),
+			)
+
+			err := aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("retries queries made with a POST as reads", func() {
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadGateway, "Bad Gateway"),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/v1/project/my-project/service/my-service/metrics"),
+					ghttp.RespondWith(http.StatusOK, `{"metrics": {"disk_usage": {"data": {"rows": [["2021-01-01T00:00:00Z", 41.5]]}}}}`),
+				),
+			)
+
+			usage, err := aivenClient.GetServiceDiskUsage(context.Background(), &aiven.GetServiceDiskUsageInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(usage).To(Equal(41.5))
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("does not retry changes which fail with other 5xx, as Aiven may have made them", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusBadGateway, "Bad Gateway"))
+
+			err := aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "my-service"})
+
+			Expect(err).To(HaveOccurred())
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("does not retry changes refused as unavailable without a Retry-After, as Aiven may have made them", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, "{}"))
+
+			err := aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "my-service"})
+
+			Expect(err).To(HaveOccurred())
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("does not retry other errors", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, "{}"))
+
+			_, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).To(HaveOccurred())
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("waits as long as Retry-After asks", func() {
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, "{}", http.Header{"Retry-After": []string{"1"}}),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			)
+
+			start := time.Now()
+			err := aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+		})
+
+		It("waits no longer than the maximum delay, whatever Retry-After asks", func() {
+			aivenClient.Retry.MaxDelay = 100 * time.Millisecond
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, "{}", http.Header{"Retry-After": []string{"60"}}),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			)
+
+			start := time.Now()
+			err := aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+
+		It("stops waiting to retry when the context is cancelled", func() {
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, "{}", http.Header{"Retry-After": []string{"60"}}),
+			)
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := aivenClient.DeleteService(ctx, &aiven.DeleteServiceInput{ServiceName: "my-service"})
+
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("rate limiting requests", func() {
+		It("paces every request through the rate limiter", func() {
+			clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+			aivenClient.RateLimiter = aiven.NewRateLimiter(1, 1, clock)
+			aivenAPI.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, "{}"),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			)
+
+			Expect(aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "a"})).To(Succeed())
+
+			done := make(chan error, 1)
+			go func() {
+				done <- aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "b"})
+			}()
+			Consistently(done).ShouldNot(Receive())
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(1))
+
+			clock.Advance(time.Second)
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(aivenAPI.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("queues queries made with a POST behind changes", func() {
+			clock := &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+			aivenClient.RateLimiter = aiven.NewRateLimiter(1, 1, clock)
+			aivenAPI.RouteToHandler("DELETE", "/v1/project/my-project/service/my-service", ghttp.RespondWith(http.StatusOK, "{}"))
+			aivenAPI.RouteToHandler("POST", "/v1/project/my-project/service/my-service/metrics", ghttp.RespondWith(
+				http.StatusOK, `{"metrics": {"disk_usage": {"data": {"rows": [["2021-01-01T00:00:00Z", 41.5]]}}}}`,
+			))
+			Expect(aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "my-service"})).To(Succeed())
+			queued := clock.NowCalls()
+
+			query := make(chan error, 1)
+			go func() {
+				_, err := aivenClient.GetServiceDiskUsage(context.Background(), &aiven.GetServiceDiskUsageInput{ServiceName: "my-service"})
+				query <- err
+			}()
+			Eventually(clock.NowCalls).Should(Equal(queued + 1))
+			change := make(chan error, 1)
+			go func() {
+				change <- aivenClient.DeleteService(context.Background(), &aiven.DeleteServiceInput{ServiceName: "my-service"})
+			}()
+			Eventually(clock.NowCalls).Should(Equal(queued + 2))
+
+			clock.Advance(time.Second)
+			Eventually(change).Should(Receive(BeNil()))
+			Consistently(query).ShouldNot(Receive())
+
+			clock.Advance(time.Second)
+			Eventually(query).Should(Receive(BeNil()))
+		})
+	})
+
 	Describe("CreateService", func() {
 		It("should make a valid request", func() {
 			userConfig := aiven.UserConfig{}
@@ -50,19 +350,145 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusOK, "{}"),
 			))
 
-			actualService, err := aivenClient.CreateService(createServiceInput)
+			actualService, err := aivenClient.CreateService(context.Background(), createServiceInput)
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(actualService).To(Equal("{}"))
 		})
 
+		It("should create the service in the project from the request when it is set", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/other-project/service"),
+				ghttp.VerifyJSON(`{"service_name": "name", "service_type": "pg", "user_config": {}}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			))
+
+			_, err := aivenClient.CreateService(context.Background(), &aiven.CreateServiceInput{
+				Project:     "other-project",
+				ServiceName: "name",
+				ServiceType: "pg",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should send the maintenance window when it is set", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/service"),
+				ghttp.VerifyJSON(`{
+					"service_name": "name",
+					"service_type": "pg",
+					"maintenance": {"dow": "sunday", "time": "03:00:00"},
+					"user_config": {}
+				}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			))
+
+			_, err := aivenClient.CreateService(context.Background(), &aiven.CreateServiceInput{
+				ServiceName: "name",
+				ServiceType: "pg",
+				Maintenance: &aiven.Maintenance{DOW: "sunday", Time: "03:00:00"},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should send the disk space when it is set", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/service"),
+				ghttp.VerifyJSON(`{
+					"service_name": "name",
+					"service_type": "pg",
+					"disk_space_mb": 92160,
+					"user_config": {}
+				}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			))
+
+			_, err := aivenClient.CreateService(context.Background(), &aiven.CreateServiceInput{
+				ServiceName: "name",
+				ServiceType: "pg",
+				DiskSpaceMB: 92160,
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should send the notification contacts when there are some", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/service"),
+				ghttp.VerifyJSON(`{
+					"service_name": "name",
+					"service_type": "pg",
+					"tech_emails": [{"email": "team@example.com"}],
+					"user_config": {}
+				}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			))
+
+			_, err := aivenClient.CreateService(context.Background(), &aiven.CreateServiceInput{
+				ServiceName: "name",
+				ServiceType: "pg",
+				TechEmails:  []aiven.TechEmail{{Email: "team@example.com"}},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should merge the default user config into the request", func() {
+			userConfig := aiven.UserConfig{}
+			userConfig.PGVersion = "12"
+			userConfig.IPFilter = []string{"1.2.3.4"}
+			userConfig.Defaults = map[string]interface{}{
+				"pg_version": "11",
+				"ip_filter":  []string{},
+				"pg":         map[string]interface{}{"max_connections": 100},
+			}
+
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/service"),
+				ghttp.VerifyJSON(`{
+					"cloud": "cloud",
+					"plan": "plan",
+					"service_name": "name",
+					"service_type": "pg",
+					"user_config": {
+						"ip_filter": ["1.2.3.4"],
+						"pg_version": "12",
+						"pg": {"max_connections": 100}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			))
+
+			_, err := aivenClient.CreateService(context.Background(), &aiven.CreateServiceInput{
+				Cloud:       "cloud",
+				Plan:        "plan",
+				ServiceName: "name",
+				ServiceType: "pg",
+				UserConfig:  userConfig,
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a specific error if the service already exists", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusConflict, `{"message": "Service name is already in use in this project"}`),
+			))
+
+			_, err := aivenClient.CreateService(context.Background(), &aiven.CreateServiceInput{})
+
+			Expect(err).To(Equal(aiven.ErrServiceAlreadyExists))
+		})
+
 		It("returns an error if the http request fails", func() {
 			createServiceInput := &aiven.CreateServiceInput{}
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
 				ghttp.RespondWith(http.StatusNotFound, "{}"),
 			))
 
-			actualService, err := aivenClient.CreateService(createServiceInput)
+			actualService, err := aivenClient.CreateService(context.Background(), createServiceInput)
 
 			Expect(err).To(MatchError("Error creating service: 404 status code returned from Aiven: '{}'"))
 			Expect(actualService).To(Equal(""))
@@ -81,18 +507,144 @@ var _ = Describe("Client", func() {
 				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
 				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
 				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
-				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"service": {"service_type": "pg", "state": "RUNNING", "update_time": "%s"}}`, expectedUpdateTime)),
+				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"service": {"service_type": "pg", "plan": "startup-4", "cloud_name": "aws-eu-west-1", "state": "RUNNING", "update_time": "%s"}}`, expectedUpdateTime)),
 			))
 
-			service, err := aivenClient.GetService(getServiceInput)
+			service, err := aivenClient.GetService(context.Background(), getServiceInput)
 			parsedTime, _ := time.Parse(time.RFC3339Nano, expectedUpdateTime)
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(service.State).To(BeEquivalentTo("RUNNING"))
 			Expect(service.ServiceType).To(Equal("pg"))
+			Expect(service.Plan).To(Equal("startup-4"))
+			Expect(service.CloudName).To(Equal("aws-eu-west-1"))
 			Expect(service.UpdateTime).To(Equal(parsedTime))
 		})
 
+		It("should return the IP filter given as strings or objects", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.RespondWith(http.StatusOK, `{"service": {"service_type": "pg", "state": "RUNNING", "update_time": "2018-06-21T10:01:05Z", "user_config": {"ip_filter": [
+					"1.2.3.4/32",
+					{"network": "10.0.0.0/8", "description": "office"}
+				]}}}`),
+			))
+
+			service, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.UserConfig.IPFilter).To(Equal(aiven.IPFilter{"1.2.3.4/32", "10.0.0.0/8"}))
+		})
+
+		It("should return the states of the service's nodes", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.RespondWith(http.StatusOK, `{"service": {
+					"service_type": "pg",
+					"state": "REBALANCING",
+					"update_time": "2018-06-21T10:01:05Z",
+					"node_states": [
+						{"name": "my-service-1", "state": "running", "progress_updates": []},
+						{"name": "my-service-2", "state": "syncing_data", "progress_updates": [
+							{"completed": true, "phase": "prepare", "current": null, "min": null, "max": null, "unit": null},
+							{"completed": false, "phase": "basebackup", "current": 450, "min": 0, "max": 1000, "unit": "bytes_uncompressed"}
+						]}
+					]
+				}}`),
+			))
+
+			service, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.NodeStates).To(HaveLen(2))
+			Expect(service.NodeStates[0]).To(Equal(aiven.NodeState{
+				Name: "my-service-1", State: aiven.NodeRunning, ProgressUpdates: []aiven.NodeProgress{},
+			}))
+			Expect(service.NodeStates[1].ProgressUpdates).To(HaveLen(2))
+			basebackup := service.NodeStates[1].ProgressUpdates[1]
+			Expect(basebackup.Phase).To(Equal("basebackup"))
+			Expect(*basebackup.Current).To(Equal(int64(450)))
+			Expect(*basebackup.Max).To(Equal(int64(1000)))
+			Expect(basebackup.Unit).To(Equal("bytes_uncompressed"))
+		})
+
+		It("should return the engine version", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.RespondWith(http.StatusOK, `{"service": {"service_type": "pg", "state": "RUNNING", "update_time": "2018-06-21T10:01:05Z", "user_config": {"pg_version": "13"}}}`),
+			))
+
+			service, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.UserConfig.EngineVersion("pg")).To(Equal("13"))
+			Expect(service.UserConfig.EngineVersion("influxdb")).To(Equal(""))
+		})
+
+		It("should return the service components", func() {
+			getServiceInput := &aiven.GetServiceInput{
+				ServiceName: "my-service",
+			}
+
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.RespondWith(http.StatusOK, `{"service": {
+					"service_type": "kafka",
+					"state": "RUNNING",
+					"update_time": "2018-06-21T10:01:05.000040+00:00",
+					"components": [{
+						"component": "kafka",
+						"host": "kafka.aivencloud.com",
+						"port": 13044,
+						"route": "dynamic",
+						"usage": "primary",
+						"kafka_authentication_method": "sasl"
+					}]
+				}}`),
+			))
+
+			service, err := aivenClient.GetService(context.Background(), getServiceInput)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Components).To(Equal([]aiven.ServiceComponent{
+				{
+					Component:                 "kafka",
+					Host:                      "kafka.aivencloud.com",
+					Port:                      13044,
+					Route:                     "dynamic",
+					Usage:                     "primary",
+					KafkaAuthenticationMethod: "sasl",
+				},
+			}))
+		})
+
+		It("should return the replica components of HA services", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.RespondWith(http.StatusOK, `{"service": {
+					"service_type": "pg",
+					"state": "RUNNING",
+					"update_time": "2018-06-21T10:01:05.000040+00:00",
+					"components": [
+						{"component": "pg", "host": "pg-my-service.aivencloud.com", "port": 21691, "route": "dynamic", "usage": "primary"},
+						{"component": "pg", "host": "replica-pg-my-service.aivencloud.com", "port": 21691, "route": "dynamic", "usage": "replica"},
+						{"component": "pgbouncer", "host": "pg-my-service.aivencloud.com", "port": 21692, "route": "dynamic", "usage": "primary"}
+					]
+				}}`),
+			))
+
+			service, err := aivenClient.GetService(context.Background(), &aiven.GetServiceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Components).To(ContainElement(aiven.ServiceComponent{
+				Component: "pg",
+				Host:      "replica-pg-my-service.aivencloud.com",
+				Port:      21691,
+				Route:     "dynamic",
+				Usage:     "replica",
+			}))
+		})
+
 		It("returns an error if the state is missing", func() {
 			getServiceInput := &aiven.GetServiceInput{
 				ServiceName: "my-service",
@@ -105,7 +657,7 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusOK, `{"service": {"service_type": "pg", "update_time": "2018-06-21T10:01:05.000040+00:00"}}`),
 			))
 
-			_, err := aivenClient.GetService(getServiceInput)
+			_, err := aivenClient.GetService(context.Background(), getServiceInput)
 
 			Expect(err).To(MatchError("Error getting service: no state found in response JSON"))
 		})
@@ -122,7 +674,7 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusOK, `{"service": {"state": "RUNNING", "update_time": "2018-06-21T10:01:05.000040+00:00"}}`),
 			))
 
-			_, err := aivenClient.GetService(getServiceInput)
+			_, err := aivenClient.GetService(context.Background(), getServiceInput)
 
 			Expect(err).To(MatchError("Error getting service: no service type found in response JSON"))
 		})
@@ -139,12 +691,12 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusOK, `{"service": {"service_type": "pg", "state": "RUNNING"}}`),
 			))
 
-			_, err := aivenClient.GetService(getServiceInput)
+			_, err := aivenClient.GetService(context.Background(), getServiceInput)
 
 			Expect(err).To(MatchError("Error getting service: no update_time found in response JSON"))
 		})
 
-		It("returns an error if aiven 404s", func() {
+		It("returns ErrServiceDoesNotExist if aiven 404s", func() {
 			getServiceInput := &aiven.GetServiceInput{
 				ServiceName: "my-service",
 			}
@@ -156,9 +708,24 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusNotFound, "{}"),
 			))
 
-			_, err := aivenClient.GetService(getServiceInput)
+			_, err := aivenClient.GetService(context.Background(), getServiceInput)
+
+			Expect(err).To(Equal(aiven.ErrServiceDoesNotExist))
+		})
+
+		It("returns an error if aiven returns another status code", func() {
+			getServiceInput := &aiven.GetServiceInput{
+				ServiceName: "my-service",
+			}
+
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+			))
+
+			_, err := aivenClient.GetService(context.Background(), getServiceInput)
 
-			Expect(err).To(MatchError("Error getting service: 404 status code returned from Aiven: '{}'"))
+			Expect(err).To(MatchError("Error getting service: 500 status code returned from Aiven: '{}'"))
 		})
 	})
 
@@ -174,7 +741,7 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusOK, "{}"),
 			))
 
-			err := aivenClient.DeleteService(deleteServiceInput)
+			err := aivenClient.DeleteService(context.Background(), deleteServiceInput)
 
 			Expect(err).ToNot(HaveOccurred())
 		})
@@ -185,18 +752,32 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusNotFound, "{}"),
 			))
 
-			err := aivenClient.DeleteService(deleteServiceInput)
+			err := aivenClient.DeleteService(context.Background(), deleteServiceInput)
 
 			Expect(err).To(MatchError(aiven.ErrInstanceDoesNotExist))
 		})
 
+		It("returns a specific error if the service has termination protection", func() {
+			deleteServiceInput := &aiven.DeleteServiceInput{}
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, `{
+					"errors": [{"message": "Service is protected against termination and shutdown. Remove termination protection first.", "status": 403}],
+					"message": "Service is protected against termination and shutdown. Remove termination protection first."
+				}`),
+			))
+
+			err := aivenClient.DeleteService(context.Background(), deleteServiceInput)
+
+			Expect(err).To(MatchError(aiven.ErrTerminationProtectionEnabled))
+		})
+
 		It("returns an error if the status code is unexpected", func() {
 			deleteServiceInput := &aiven.DeleteServiceInput{}
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
 				ghttp.RespondWith(http.StatusTeapot, "{}"),
 			))
 
-			err := aivenClient.DeleteService(deleteServiceInput)
+			err := aivenClient.DeleteService(context.Background(), deleteServiceInput)
 
 			Expect(err).To(MatchError("Error deleting service: 418 status code returned from Aiven: '{}'"))
 		})
@@ -217,7 +798,7 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusOK, `{"message":"created","user":{"password":"superdupersecret","type":"normal","username":"user"}}`),
 			))
 
-			actualPassword, err := aivenClient.CreateServiceUser(createServiceUserInput)
+			actualPassword, err := aivenClient.CreateServiceUser(context.Background(), createServiceUserInput)
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(actualPassword).To(Equal("superdupersecret"))
@@ -229,72 +810,384 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusForbidden, "{}"),
 			))
 
-			actualPassword, err := aivenClient.CreateServiceUser(createServiceUserInput)
+			actualPassword, err := aivenClient.CreateServiceUser(context.Background(), createServiceUserInput)
 
 			Expect(err).To(MatchError("Error creating service user: 403 status code returned from Aiven: '{}'"))
 			Expect(actualPassword).To(Equal(""))
 		})
 
-		It("returns an error if the password is empty", func() {
+		It("returns a specific error if the service does not support users", func() {
 			createServiceUserInput := &aiven.CreateServiceUserInput{
 				ServiceName: "my-service",
 				Username:    "user",
 			}
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
-				ghttp.RespondWith(http.StatusOK, `{"this will not":"unmarshal into the password field"}`),
+				ghttp.RespondWith(http.StatusBadRequest, `{"message": "Service users are not supported for this plan"}`),
 			))
 
-			actualPassword, err := aivenClient.CreateServiceUser(createServiceUserInput)
+			actualPassword, err := aivenClient.CreateServiceUser(context.Background(), createServiceUserInput)
 
-			Expect(err).To(MatchError("Error creating service user: password was empty"))
+			Expect(err).To(MatchError(aiven.ErrServiceUserNotSupported))
 			Expect(actualPassword).To(Equal(""))
 		})
-	})
 
-	Describe("DeleteServiceUser", func() {
-		It("should make a valid request", func() {
-			deleteServiceUserInput := &aiven.DeleteServiceUserInput{
+		It("returns an error if the password is empty", func() {
+			createServiceUserInput := &aiven.CreateServiceUserInput{
 				ServiceName: "my-service",
-				Username:    "my-user",
+				Username:    "user",
 			}
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
-				ghttp.VerifyRequest("DELETE", "/v1/project/my-project/service/my-service/user/my-user"),
-				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
-				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
-				ghttp.RespondWith(http.StatusOK, "{}"),
+				ghttp.RespondWith(http.StatusOK, `{"this will not":"unmarshal into the password field"}`),
 			))
 
-			actualResponse, err := aivenClient.DeleteServiceUser(deleteServiceUserInput)
+			actualPassword, err := aivenClient.CreateServiceUser(context.Background(), createServiceUserInput)
 
-			Expect(err).ToNot(HaveOccurred())
-			Expect(actualResponse).To(Equal("{}"))
+			Expect(err).To(MatchError("Error creating service user: password was empty"))
+			Expect(actualPassword).To(Equal(""))
 		})
 
-		It("returns an error if the http request fails", func() {
-			deleteServiceUserInput := &aiven.DeleteServiceUserInput{}
+		It("returns a specific error if the user already exists", func() {
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
-				ghttp.RespondWith(http.StatusForbidden, "{}"),
+				ghttp.RespondWith(http.StatusConflict, `{"message": "Service user already exists"}`),
 			))
 
-			actualResponse, err := aivenClient.DeleteServiceUser(deleteServiceUserInput)
+			_, err := aivenClient.CreateServiceUser(context.Background(), &aiven.CreateServiceUserInput{ServiceName: "my-service", Username: "user"})
 
-			Expect(err).To(MatchError("Error deleting service user: 403 status code returned from Aiven: '{}'"))
-			Expect(actualResponse).To(Equal(""))
+			Expect(err).To(MatchError(aiven.ErrServiceUserAlreadyExists))
 		})
+	})
 
-		It("returns an error if an unexpected error message is returned", func() {
-			deleteServiceUserInput := &aiven.DeleteServiceUserInput{}
+	Describe("GetServiceUser", func() {
+		It("should make a valid request and return the user", func() {
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
-				ghttp.RespondWith(http.StatusForbidden, `{"message": "this error was not expected"}`),
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service/user/user"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"user": {"username": "user", "password": "secret", "type": "normal"}}`),
 			))
 
-			actualResponse, err := aivenClient.DeleteServiceUser(deleteServiceUserInput)
+			user, err := aivenClient.GetServiceUser(context.Background(), &aiven.GetServiceUserInput{
+				ServiceName: "my-service",
+				Username:    "user",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user).To(Equal(&aiven.User{Username: "user", Password: "secret", Type: "normal"}))
+		})
+
+		It("should return the client certificate of Kafka users", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service/user/user"),
+				ghttp.RespondWith(http.StatusOK, `{"user": {"username": "user", "password": "secret", "type": "normal", "access_cert": "cert", "access_key": "key"}}`),
+			))
+
+			user, err := aivenClient.GetServiceUser(context.Background(), &aiven.GetServiceUserInput{
+				ServiceName: "my-service",
+				Username:    "user",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.AccessCert).To(Equal("cert"))
+			Expect(user.AccessKey).To(Equal("key"))
+		})
+
+		It("returns a specific error if the user does not exist", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{"message": "Service user 'user' does not exist"}`),
+			))
+
+			_, err := aivenClient.GetServiceUser(context.Background(), &aiven.GetServiceUserInput{ServiceName: "my-service", Username: "user"})
+
+			Expect(err).To(MatchError(aiven.ErrServiceUserDoesNotExist))
+		})
+
+		It("returns an error if the response has no password", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"user": {"username": "user"}}`),
+			))
+
+			_, err := aivenClient.GetServiceUser(context.Background(), &aiven.GetServiceUserInput{ServiceName: "my-service", Username: "user"})
+
+			Expect(err).To(MatchError("Error getting service user: password was empty"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+			))
+
+			_, err := aivenClient.GetServiceUser(context.Background(), &aiven.GetServiceUserInput{ServiceName: "my-service", Username: "user"})
+
+			Expect(err).To(MatchError("Error getting service user: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ListServiceUsers", func() {
+		It("should make a valid request and return the users", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"service": {"users": [
+					{"username": "avnadmin", "password": "admin-secret", "type": "primary"},
+					{"username": "user", "password": "secret", "type": "normal"}
+				]}}`),
+			))
+
+			users, err := aivenClient.ListServiceUsers(context.Background(), &aiven.ListServiceUsersInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(users).To(Equal([]aiven.User{
+				{Username: "avnadmin", Password: "admin-secret", Type: "primary"},
+				{Username: "user", Password: "secret", Type: "normal"},
+			}))
+		})
+
+		It("returns a specific error if the service does not exist", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{"message": "Service not found"}`))
+
+			_, err := aivenClient.ListServiceUsers(context.Background(), &aiven.ListServiceUsersInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError(aiven.ErrServiceDoesNotExist))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, "{}"))
+
+			_, err := aivenClient.ListServiceUsers(context.Background(), &aiven.ListServiceUsersInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error listing service users: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("CreateServiceDatabase", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/service/my-service/db"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.VerifyJSON(`{"database": "my-database"}`),
+				ghttp.RespondWith(http.StatusOK, `{"message": "created"}`),
+			))
+
+			err := aivenClient.CreateServiceDatabase(context.Background(), &aiven.CreateServiceDatabaseInput{
+				ServiceName: "my-service",
+				Database:    "my-database",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a specific error if the database already exists", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusConflict, `{"message": "Database already exists"}`))
+
+			err := aivenClient.CreateServiceDatabase(context.Background(), &aiven.CreateServiceDatabaseInput{ServiceName: "my-service", Database: "my-database"})
+
+			Expect(err).To(MatchError(aiven.ErrServiceDatabaseAlreadyExists))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, "{}"))
+
+			err := aivenClient.CreateServiceDatabase(context.Background(), &aiven.CreateServiceDatabaseInput{ServiceName: "my-service", Database: "my-database"})
+
+			Expect(err).To(MatchError("Error creating service database: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("DeleteServiceDatabase", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/project/my-project/service/my-service/db/my-database"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"message": "deleted"}`),
+			))
+
+			err := aivenClient.DeleteServiceDatabase(context.Background(), &aiven.DeleteServiceDatabaseInput{
+				ServiceName: "my-service",
+				Database:    "my-database",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a specific error if the database does not exist", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{"message": "Database does not exist"}`))
+
+			err := aivenClient.DeleteServiceDatabase(context.Background(), &aiven.DeleteServiceDatabaseInput{ServiceName: "my-service", Database: "my-database"})
+
+			Expect(err).To(MatchError(aiven.ErrServiceDatabaseDoesNotExist))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, "{}"))
+
+			err := aivenClient.DeleteServiceDatabase(context.Background(), &aiven.DeleteServiceDatabaseInput{ServiceName: "my-service", Database: "my-database"})
+
+			Expect(err).To(MatchError("Error deleting service database: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("CreateConnectionPool", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/service/my-service/connection_pool"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.VerifyJSON(`{"pool_name": "my-pool", "database": "defaultdb", "username": "user", "pool_size": 20, "pool_mode": "transaction"}`),
+				ghttp.RespondWith(http.StatusOK, `{"message": "created"}`),
+			))
+
+			err := aivenClient.CreateConnectionPool(context.Background(), &aiven.CreateConnectionPoolInput{
+				ServiceName: "my-service",
+				PoolName:    "my-pool",
+				Database:    "defaultdb",
+				Username:    "user",
+				PoolSize:    20,
+				PoolMode:    "transaction",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a specific error if the pool already exists", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusConflict, `{"message": "Connection pool already exists"}`))
+
+			err := aivenClient.CreateConnectionPool(context.Background(), &aiven.CreateConnectionPoolInput{ServiceName: "my-service", PoolName: "my-pool"})
+
+			Expect(err).To(MatchError(aiven.ErrConnectionPoolAlreadyExists))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, "{}"))
+
+			err := aivenClient.CreateConnectionPool(context.Background(), &aiven.CreateConnectionPoolInput{ServiceName: "my-service", PoolName: "my-pool"})
+
+			Expect(err).To(MatchError("Error creating connection pool: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("DeleteConnectionPool", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/project/my-project/service/my-service/connection_pool/my-pool"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"message": "deleted"}`),
+			))
+
+			err := aivenClient.DeleteConnectionPool(context.Background(), &aiven.DeleteConnectionPoolInput{ServiceName: "my-service", PoolName: "my-pool"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns a specific error if the pool does not exist", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{"message": "Connection pool does not exist"}`))
+
+			err := aivenClient.DeleteConnectionPool(context.Background(), &aiven.DeleteConnectionPoolInput{ServiceName: "my-service", PoolName: "my-pool"})
+
+			Expect(err).To(MatchError(aiven.ErrConnectionPoolDoesNotExist))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, "{}"))
+
+			err := aivenClient.DeleteConnectionPool(context.Background(), &aiven.DeleteConnectionPoolInput{ServiceName: "my-service", PoolName: "my-pool"})
+
+			Expect(err).To(MatchError("Error deleting connection pool: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ResetServiceUserCredentials", func() {
+		It("should make a valid request and return the new password", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service/user/user"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.VerifyJSON(`{"operation": "reset-credentials"}`),
+				ghttp.RespondWith(http.StatusOK, `{"message": "updated", "service": {"users": [
+					{"username": "avnadmin", "password": "admin-secret", "type": "primary"},
+					{"username": "user", "password": "new-secret", "type": "normal"}
+				]}}`),
+			))
+
+			password, err := aivenClient.ResetServiceUserCredentials(context.Background(), &aiven.ResetServiceUserCredentialsInput{
+				ServiceName: "my-service",
+				Username:    "user",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(password).To(Equal("new-secret"))
+		})
+
+		It("returns an error if the response has no password for the user", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"service": {"users": []}}`),
+			))
+
+			_, err := aivenClient.ResetServiceUserCredentials(context.Background(), &aiven.ResetServiceUserCredentialsInput{
+				ServiceName: "my-service",
+				Username:    "user",
+			})
+
+			Expect(err).To(MatchError("Error resetting service user credentials: password was empty"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, "{}"),
+			))
+
+			_, err := aivenClient.ResetServiceUserCredentials(context.Background(), &aiven.ResetServiceUserCredentialsInput{
+				ServiceName: "my-service",
+				Username:    "user",
+			})
+
+			Expect(err).To(MatchError("Error resetting service user credentials: 404 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("DeleteServiceUser", func() {
+		It("should make a valid request", func() {
+			deleteServiceUserInput := &aiven.DeleteServiceUserInput{
+				ServiceName: "my-service",
+				Username:    "my-user",
+			}
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/project/my-project/service/my-service/user/my-user"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			))
+
+			actualResponse, err := aivenClient.DeleteServiceUser(context.Background(), deleteServiceUserInput)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actualResponse).To(Equal("{}"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			deleteServiceUserInput := &aiven.DeleteServiceUserInput{}
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			actualResponse, err := aivenClient.DeleteServiceUser(context.Background(), deleteServiceUserInput)
+
+			Expect(err).To(MatchError("Error deleting service user: 403 status code returned from Aiven: '{}'"))
+			Expect(actualResponse).To(Equal(""))
+		})
+
+		It("returns an error if an unexpected error message is returned", func() {
+			deleteServiceUserInput := &aiven.DeleteServiceUserInput{}
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, `{"message": "this error was not expected"}`),
+			))
+
+			actualResponse, err := aivenClient.DeleteServiceUser(context.Background(), deleteServiceUserInput)
 
 			Expect(err).To(MatchError(`Error deleting service user: 403 status code returned from Aiven: '{"message": "this error was not expected"}'`))
 			Expect(actualResponse).To(Equal(""))
 		})
 
-		It("succeeds if an error saying the user does not exist is returned", func() {
+		It("returns a specific error if an error saying the user does not exist is returned", func() {
 			deleteServiceUserInput := &aiven.DeleteServiceUserInput{
 				ServiceName: "my-service",
 				Username:    "my-deleted-user",
@@ -304,10 +1197,23 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusForbidden, response),
 			))
 
-			actualResponse, err := aivenClient.DeleteServiceUser(deleteServiceUserInput)
+			_, err := aivenClient.DeleteServiceUser(context.Background(), deleteServiceUserInput)
 
-			Expect(err).ToNot(HaveOccurred())
-			Expect(actualResponse).To(Equal(response))
+			Expect(err).To(MatchError(aiven.ErrServiceUserDoesNotExist))
+		})
+
+		It("returns a specific error if the service does not exist", func() {
+			deleteServiceUserInput := &aiven.DeleteServiceUserInput{
+				ServiceName: "my-deleted-service",
+				Username:    "my-user",
+			}
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{"message": "Service not found"}`),
+			))
+
+			_, err := aivenClient.DeleteServiceUser(context.Background(), deleteServiceUserInput)
+
+			Expect(err).To(MatchError(aiven.ErrInstanceDoesNotExist))
 		})
 	})
 
@@ -331,46 +1237,925 @@ var _ = Describe("Client", func() {
 				ghttp.RespondWith(http.StatusOK, `{}`),
 			))
 
-			actualResponse, err := aivenClient.UpdateService(updateServiceInput)
+			actualResponse, err := aivenClient.UpdateService(context.Background(), updateServiceInput)
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(actualResponse).To(Equal(`{}`))
 		})
 
-		It("returns an error if the http request fails", func() {
-			updateServiceInput := &aiven.UpdateServiceInput{}
+		It("should send the cloud to migrate the service to", func() {
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
-				ghttp.RespondWith(http.StatusNotFound, "{}"),
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service"),
+				ghttp.VerifyJSON(`{"cloud": "google-europe-west2", "plan": "new-plan", "user_config": {}}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
 			))
 
-			actualResponse, err := aivenClient.UpdateService(updateServiceInput)
+			_, err := aivenClient.UpdateService(context.Background(), &aiven.UpdateServiceInput{
+				ServiceName: "my-service",
+				Cloud:       "google-europe-west2",
+				Plan:        "new-plan",
+			})
 
-			Expect(err).To(MatchError("Error updating service: 404 status code returned from Aiven: '{}'"))
-			Expect(actualResponse).To(Equal(""))
+			Expect(err).ToNot(HaveOccurred())
 		})
 
-		It("returns the right error type if the operation is invalid", func() {
-			updateServiceInput := &aiven.UpdateServiceInput{}
+		It("should send the disk space when it is set", func() {
 			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
-				ghttp.RespondWith(http.StatusBadRequest, `
-				{
-					"errors" : [
-							{
-								"message" : "Elasticsearch major version downgrade is not possible",
-								"status" : 400
-							}
-					],
-					"message" : "Elasticsearch major version downgrade is not possible"
-				}
-				`),
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service"),
+				ghttp.VerifyJSON(`{"disk_space_mb": 92160, "user_config": {}}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
 			))
 
-			actualResponse, err := aivenClient.UpdateService(updateServiceInput)
+			_, err := aivenClient.UpdateService(context.Background(), &aiven.UpdateServiceInput{
+				ServiceName: "my-service",
+				DiskSpaceMB: 92160,
+			})
 
-			Expect(err).To(MatchError(
-				aiven.ErrInvalidUpdate{"Invalid Update: Elasticsearch major version downgrade is not possible"},
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should send an empty list of notification contacts to remove them", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service"),
+				ghttp.VerifyJSON(`{"tech_emails": [], "user_config": {}}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
 			))
-			Expect(actualResponse).To(Equal(""))
+
+			_, err := aivenClient.UpdateService(context.Background(), &aiven.UpdateServiceInput{
+				ServiceName: "my-service",
+				TechEmails:  &[]aiven.TechEmail{},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should merge the default user config into the request", func() {
+			userConfig := aiven.UserConfig{}
+			userConfig.ElasticsearchVersion = "7"
+			userConfig.IPFilter = []string{"1.2.3.4"}
+			userConfig.Defaults = map[string]interface{}{
+				"elasticsearch": map[string]interface{}{"action_auto_create_index_enabled": false},
+				"ip_filter":     []string{"0.0.0.0/0"},
+			}
+
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service"),
+				ghttp.VerifyJSON(`{
+					"plan": "new-plan",
+					"user_config": {
+						"elasticsearch": {"action_auto_create_index_enabled": false},
+						"elasticsearch_version": "7",
+						"ip_filter": ["1.2.3.4"]
+					}
+				}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			_, err := aivenClient.UpdateService(context.Background(), &aiven.UpdateServiceInput{
+				ServiceName: "my-service",
+				Plan:        "new-plan",
+				UserConfig:  userConfig,
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			updateServiceInput := &aiven.UpdateServiceInput{}
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, "{}"),
+			))
+
+			actualResponse, err := aivenClient.UpdateService(context.Background(), updateServiceInput)
+
+			Expect(err).To(MatchError("Error updating service: 404 status code returned from Aiven: '{}'"))
+			Expect(actualResponse).To(Equal(""))
+		})
+
+		It("returns the right error type if the operation is invalid", func() {
+			updateServiceInput := &aiven.UpdateServiceInput{}
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusBadRequest, `
+				{
+					"errors" : [
+							{
+								"message" : "Elasticsearch major version downgrade is not possible",
+								"status" : 400
+							}
+					],
+					"message" : "Elasticsearch major version downgrade is not possible"
+				}
+				`),
+			))
+
+			actualResponse, err := aivenClient.UpdateService(context.Background(), updateServiceInput)
+
+			Expect(err).To(MatchError(
+				aiven.ErrInvalidUpdate{"Invalid Update: Elasticsearch major version downgrade is not possible"},
+			))
+			Expect(actualResponse).To(Equal(""))
+		})
+	})
+
+	Describe("ListServices", func() {
+		It("should return the services in the project", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"services": [
+					{"service_name": "env-1", "service_type": "pg", "state": "RUNNING", "tags": {"cf_organization_guid": "org-1"}},
+					{"service_name": "env-2", "service_type": "redis", "state": "REBUILDING"}
+				]}`),
+			))
+
+			services, err := aivenClient.ListServices(context.Background(), &aiven.ListServicesInput{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(services).To(HaveLen(2))
+			Expect(services[0].ServiceName).To(Equal("env-1"))
+			Expect(services[0].Tags).To(Equal(map[string]string{"cf_organization_guid": "org-1"}))
+			Expect(services[1].ServiceName).To(Equal("env-2"))
+			Expect(services[1].Tags).To(BeEmpty())
+		})
+
+		It("should use the project from the request when it is set", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/other-project/service"),
+				ghttp.RespondWith(http.StatusOK, `{"services": []}`),
+			))
+
+			services, err := aivenClient.ListServices(context.Background(), &aiven.ListServicesInput{Project: "other-project"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(services).To(BeEmpty())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			services, err := aivenClient.ListServices(context.Background(), &aiven.ListServicesInput{})
+
+			Expect(err).To(MatchError("Error listing services: 403 status code returned from Aiven: '{}'"))
+			Expect(services).To(BeNil())
+		})
+	})
+
+	Describe("UpdateServiceTags", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service/tag"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.VerifyJSON(`{"tags": {"cf_plan_id": "plan-1"}}`),
+				ghttp.RespondWith(http.StatusOK, `{"message": "updated"}`),
+			))
+
+			err := aivenClient.UpdateServiceTags(context.Background(), &aiven.UpdateServiceTagsInput{
+				ServiceName: "my-service",
+				Tags:        map[string]string{"cf_plan_id": "plan-1"},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, "{}"),
+			))
+
+			err := aivenClient.UpdateServiceTags(context.Background(), &aiven.UpdateServiceTagsInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error updating service tags: 404 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("GetServicePlans", func() {
+		It("should return the plans for the service type", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service_types"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"service_types": {
+					"pg": {"service_plans": [{"service_plan": "startup-4", "disk_space_mb": 81920, "disk_space_cap_mb": 409600, "regions": {"aws-eu-west-1": {"price_usd": "0.1"}}}]},
+					"kafka": {"service_plans": [{"service_plan": "business-4", "regions": {}}]}
+				}}`),
+			))
+
+			plans, err := aivenClient.GetServicePlans(context.Background(), &aiven.GetServicePlansInput{ServiceType: "pg"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plans).To(HaveLen(1))
+			Expect(plans[0].ServicePlan).To(Equal("startup-4"))
+			Expect(plans[0].Regions).To(HaveKey("aws-eu-west-1"))
+			Expect(plans[0].DiskSpaceMB).To(Equal(81920))
+			Expect(plans[0].DiskSpaceCapMB).To(Equal(409600))
+		})
+
+		It("returns an error if the service type is unknown", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"service_types": {}}`),
+			))
+
+			_, err := aivenClient.GetServicePlans(context.Background(), &aiven.GetServicePlansInput{ServiceType: "mongodb"})
+
+			Expect(err).To(MatchError("Error getting service plans: unknown service type mongodb"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			_, err := aivenClient.GetServicePlans(context.Background(), &aiven.GetServicePlansInput{ServiceType: "pg"})
+
+			Expect(err).To(MatchError("Error getting service plans: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("GetACLConfig", func() {
+		It("should return the ACL config for the service type", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service/opensearch/acl"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"opensearch_acl_config": {
+					"acls": [{"username": "user-1", "rules": [{"index": "*", "permission": "read"}]}],
+					"enabled": true,
+					"extendedAcl": false
+				}}`),
+			))
+
+			aclConfig, err := aivenClient.GetACLConfig(context.Background(), &aiven.GetACLConfigInput{
+				ServiceName: "my-service",
+				ServiceType: "opensearch",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(aclConfig).To(Equal(&aiven.ACLConfig{
+				ACLs: []aiven.ACL{{
+					Username: "user-1",
+					Rules:    []aiven.ACLRule{{Index: "*", Permission: "read"}},
+				}},
+				Enabled: true,
+			}))
+		})
+
+		It("returns a specific error if the service does not exist", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{"message": "Service not found"}`),
+			))
+
+			_, err := aivenClient.GetACLConfig(context.Background(), &aiven.GetACLConfigInput{
+				ServiceName: "my-service",
+				ServiceType: "opensearch",
+			})
+
+			Expect(err).To(MatchError(aiven.ErrServiceDoesNotExist))
+		})
+
+		It("returns an error if the response has no ACL config for the service type", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"opensearch_acl_config": {"acls": [], "enabled": false}}`),
+			))
+
+			_, err := aivenClient.GetACLConfig(context.Background(), &aiven.GetACLConfigInput{
+				ServiceName: "my-service",
+				ServiceType: "elasticsearch",
+			})
+
+			Expect(err).To(MatchError("Error getting ACL config: no ACL config found in response JSON"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+			))
+
+			_, err := aivenClient.GetACLConfig(context.Background(), &aiven.GetACLConfigInput{
+				ServiceName: "my-service",
+				ServiceType: "elasticsearch",
+			})
+
+			Expect(err).To(MatchError("Error getting ACL config: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("GetProjectCA", func() {
+		It("should return the project's CA certificate", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/kms/ca"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.RespondWith(http.StatusOK, `{"certificate": "-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"}`),
+			))
+
+			certificate, err := aivenClient.GetProjectCA(context.Background(), &aiven.GetProjectCAInput{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(certificate).To(Equal("-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"))
+		})
+
+		It("returns an error if there is no certificate", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			_, err := aivenClient.GetProjectCA(context.Background(), &aiven.GetProjectCAInput{})
+
+			Expect(err).To(MatchError("Error getting project CA: no certificate found in response JSON"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			_, err := aivenClient.GetProjectCA(context.Background(), &aiven.GetProjectCAInput{Project: "other-project"})
+
+			Expect(err).To(MatchError("Error getting project CA: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("CreateIntegrationEndpoint", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/integration_endpoint"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.VerifyJSON(`{
+					"endpoint_name": "my-endpoint",
+					"endpoint_type": "prometheus",
+					"user_config": {"basic_auth_username": "user", "basic_auth_password": "pass"}
+				}`),
+				ghttp.RespondWith(http.StatusOK, `{"service_integration_endpoint": {"endpoint_id": "endpoint-1"}}`),
+			))
+
+			endpointID, err := aivenClient.CreateIntegrationEndpoint(context.Background(), &aiven.CreateIntegrationEndpointInput{
+				EndpointName: "my-endpoint",
+				EndpointType: "prometheus",
+				UserConfig:   map[string]interface{}{"basic_auth_username": "user", "basic_auth_password": "pass"},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endpointID).To(Equal("endpoint-1"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusBadRequest, "{}"),
+			))
+
+			_, err := aivenClient.CreateIntegrationEndpoint(context.Background(), &aiven.CreateIntegrationEndpointInput{})
+
+			Expect(err).To(MatchError("Error creating integration endpoint: 400 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ListIntegrationEndpoints", func() {
+		It("should return the project's integration endpoints", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/integration_endpoint"),
+				ghttp.RespondWith(http.StatusOK, `{"service_integration_endpoints": [
+					{"endpoint_id": "endpoint-1", "endpoint_name": "my-endpoint", "endpoint_type": "prometheus"}
+				]}`),
+			))
+
+			endpoints, err := aivenClient.ListIntegrationEndpoints(context.Background(), &aiven.ListIntegrationEndpointsInput{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endpoints).To(Equal([]aiven.IntegrationEndpoint{
+				{EndpointID: "endpoint-1", EndpointName: "my-endpoint", EndpointType: "prometheus"},
+			}))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			_, err := aivenClient.ListIntegrationEndpoints(context.Background(), &aiven.ListIntegrationEndpointsInput{})
+
+			Expect(err).To(MatchError("Error listing integration endpoints: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("DeleteIntegrationEndpoint", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/project/my-project/integration_endpoint/endpoint-1"),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := aivenClient.DeleteIntegrationEndpoint(context.Background(), &aiven.DeleteIntegrationEndpointInput{EndpointID: "endpoint-1"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("does not return an error if the endpoint has already been deleted", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, "{}"),
+			))
+
+			err := aivenClient.DeleteIntegrationEndpoint(context.Background(), &aiven.DeleteIntegrationEndpointInput{EndpointID: "endpoint-1"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+			))
+
+			err := aivenClient.DeleteIntegrationEndpoint(context.Background(), &aiven.DeleteIntegrationEndpointInput{EndpointID: "endpoint-1"})
+
+			Expect(err).To(MatchError("Error deleting integration endpoint: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("CreateServiceIntegration", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/integration"),
+				ghttp.VerifyJSON(`{
+					"integration_type": "prometheus",
+					"source_service": "my-service",
+					"dest_endpoint_id": "endpoint-1"
+				}`),
+				ghttp.RespondWith(http.StatusOK, `{"service_integration": {"service_integration_id": "integration-1"}}`),
+			))
+
+			integrationID, err := aivenClient.CreateServiceIntegration(context.Background(), &aiven.CreateServiceIntegrationInput{
+				IntegrationType: "prometheus",
+				SourceService:   "my-service",
+				DestEndpointID:  "endpoint-1",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(integrationID).To(Equal("integration-1"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusBadRequest, "{}"),
+			))
+
+			_, err := aivenClient.CreateServiceIntegration(context.Background(), &aiven.CreateServiceIntegrationInput{})
+
+			Expect(err).To(MatchError("Error creating service integration: 400 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ListServiceIntegrations", func() {
+		It("should return the service's integrations", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service/integration"),
+				ghttp.RespondWith(http.StatusOK, `{"service_integrations": [{
+					"service_integration_id": "integration-1",
+					"integration_type": "prometheus",
+					"source_service": "my-service",
+					"dest_endpoint_id": "endpoint-1"
+				}]}`),
+			))
+
+			integrations, err := aivenClient.ListServiceIntegrations(context.Background(), &aiven.ListServiceIntegrationsInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(integrations).To(Equal([]aiven.ServiceIntegration{{
+				ServiceIntegrationID: "integration-1",
+				IntegrationType:      "prometheus",
+				SourceService:        "my-service",
+				DestEndpointID:       "endpoint-1",
+			}}))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, "{}"),
+			))
+
+			_, err := aivenClient.ListServiceIntegrations(context.Background(), &aiven.ListServiceIntegrationsInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error listing service integrations: 404 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ListServiceBackups", func() {
+		It("should return the service's backups", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service/backups"),
+				ghttp.RespondWith(http.StatusOK, `{"backups": [{
+					"backup_name": "backup-1",
+					"backup_time": "2020-01-02T03:04:05Z",
+					"data_size": 1024
+				}]}`),
+			))
+
+			backups, err := aivenClient.ListServiceBackups(context.Background(), &aiven.ListServiceBackupsInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backups).To(Equal([]aiven.ServiceBackup{{
+				BackupName: "backup-1",
+				BackupTime: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				DataSize:   1024,
+			}}))
+		})
+
+		It("returns ErrServiceDoesNotExist if the service does not exist", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, "{}"),
+			))
+
+			_, err := aivenClient.ListServiceBackups(context.Background(), &aiven.ListServiceBackupsInput{ServiceName: "my-service"})
+
+			Expect(err).To(Equal(aiven.ErrServiceDoesNotExist))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+			))
+
+			_, err := aivenClient.ListServiceBackups(context.Background(), &aiven.ListServiceBackupsInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error listing service backups: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ListProjectVPCs", func() {
+		It("should return the project's VPCs", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/vpcs"),
+				ghttp.RespondWith(http.StatusOK, `{"vpcs": [{
+					"project_vpc_id": "vpc-1",
+					"cloud_name": "aws-eu-west-1",
+					"network_cidr": "10.0.0.0/24",
+					"state": "ACTIVE"
+				}]}`),
+			))
+
+			vpcs, err := aivenClient.ListProjectVPCs(context.Background(), &aiven.ListProjectVPCsInput{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vpcs).To(Equal([]aiven.ProjectVPC{{
+				ProjectVPCID: "vpc-1",
+				CloudName:    "aws-eu-west-1",
+				NetworkCIDR:  "10.0.0.0/24",
+				State:        "ACTIVE",
+			}}))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			_, err := aivenClient.ListProjectVPCs(context.Background(), &aiven.ListProjectVPCsInput{Project: "other-project"})
+
+			Expect(err).To(MatchError("Error listing project VPCs: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("CreateStaticIP", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/static-ips"),
+				ghttp.VerifyJSON(`{"cloud_name": "aws-eu-west-1"}`),
+				ghttp.RespondWith(http.StatusOK, `{
+					"static_ip_address_id": "ip-1",
+					"ip_address": "1.2.3.4",
+					"cloud_name": "aws-eu-west-1",
+					"state": "creating"
+				}`),
+			))
+
+			staticIP, err := aivenClient.CreateStaticIP(context.Background(), &aiven.CreateStaticIPInput{CloudName: "aws-eu-west-1"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(staticIP).To(Equal(&aiven.StaticIP{
+				StaticIPAddressID: "ip-1",
+				IPAddress:         "1.2.3.4",
+				CloudName:         "aws-eu-west-1",
+				State:             "creating",
+			}))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			_, err := aivenClient.CreateStaticIP(context.Background(), &aiven.CreateStaticIPInput{CloudName: "aws-eu-west-1"})
+
+			Expect(err).To(MatchError("Error creating static IP: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ListStaticIPs", func() {
+		It("should return the project's static IPs", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/static-ips"),
+				ghttp.RespondWith(http.StatusOK, `{"static_ips": [{
+					"static_ip_address_id": "ip-1",
+					"ip_address": "1.2.3.4",
+					"cloud_name": "aws-eu-west-1",
+					"service_name": "my-service",
+					"state": "assigned"
+				}]}`),
+			))
+
+			staticIPs, err := aivenClient.ListStaticIPs(context.Background(), &aiven.ListStaticIPsInput{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(staticIPs).To(Equal([]aiven.StaticIP{{
+				StaticIPAddressID: "ip-1",
+				IPAddress:         "1.2.3.4",
+				CloudName:         "aws-eu-west-1",
+				ServiceName:       "my-service",
+				State:             "assigned",
+			}}))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+			))
+
+			_, err := aivenClient.ListStaticIPs(context.Background(), &aiven.ListStaticIPsInput{})
+
+			Expect(err).To(MatchError("Error listing static IPs: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("DeleteStaticIP", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/project/my-project/static-ips/ip-1"),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := aivenClient.DeleteStaticIP(context.Background(), &aiven.DeleteStaticIPInput{StaticIPAddressID: "ip-1"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("succeeds if the static IP has already been released", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{}`),
+			))
+
+			err := aivenClient.DeleteStaticIP(context.Background(), &aiven.DeleteStaticIPInput{StaticIPAddressID: "ip-1"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusConflict, "{}"),
+			))
+
+			err := aivenClient.DeleteStaticIP(context.Background(), &aiven.DeleteStaticIPInput{StaticIPAddressID: "ip-1"})
+
+			Expect(err).To(MatchError("Error deleting static IP: 409 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("GetServiceDiskUsage", func() {
+		It("returns the fullest node's latest disk usage", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/project/my-project/service/my-service/metrics"),
+				ghttp.VerifyJSON(`{"period": "hour"}`),
+				ghttp.RespondWith(http.StatusOK, `{
+					"metrics": {
+						"disk_usage": {
+							"data": {
+								"cols": [{"label": "time", "type": "date"}, {"label": "node-1", "type": "number"}, {"label": "node-2", "type": "number"}],
+								"rows": [
+									["2021-01-01T00:00:00Z", 90.0, 90.0],
+									["2021-01-01T00:01:00Z", 41.5, 63.2]
+								]
+							}
+						}
+					}
+				}`),
+			))
+
+			usage, err := aivenClient.GetServiceDiskUsage(context.Background(), &aiven.GetServiceDiskUsageInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(usage).To(Equal(63.2))
+		})
+
+		It("returns an error if there are no disk usage metrics", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"metrics": {"disk_usage": {"data": {"rows": []}}}}`),
+			))
+
+			_, err := aivenClient.GetServiceDiskUsage(context.Background(), &aiven.GetServiceDiskUsageInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error getting service metrics: no disk_usage metrics returned from Aiven"))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			_, err := aivenClient.GetServiceDiskUsage(context.Background(), &aiven.GetServiceDiskUsageInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error getting service metrics: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("ListMaintenanceUpdates", func() {
+		It("returns the service's pending maintenance updates", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/project/my-project/service/my-service"),
+				ghttp.RespondWith(http.StatusOK, `{
+					"service": {
+						"service_type": "elasticsearch",
+						"state": "RUNNING",
+						"update_time": "2021-01-01T00:00:00Z",
+						"maintenance": {
+							"dow": "sunday",
+							"time": "03:00:00",
+							"updates": [{
+								"description": "Upgrade to Elasticsearch 7.10.2",
+								"start_after": "2021-01-03T03:00:00Z",
+								"deadline": "2021-01-10T03:00:00Z"
+							}]
+						}
+					}
+				}`),
+			))
+
+			updates, err := aivenClient.ListMaintenanceUpdates(context.Background(), &aiven.ListMaintenanceUpdatesInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updates).To(Equal([]aiven.MaintenanceUpdate{{
+				Description: "Upgrade to Elasticsearch 7.10.2",
+				StartAfter:  "2021-01-03T03:00:00Z",
+				Deadline:    "2021-01-10T03:00:00Z",
+			}}))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			_, err := aivenClient.ListMaintenanceUpdates(context.Background(), &aiven.ListMaintenanceUpdatesInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error getting service: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("StartMaintenance", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service/maintenance/start"),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := aivenClient.StartMaintenance(context.Background(), &aiven.StartMaintenanceInput{ServiceName: "my-service"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			err := aivenClient.StartMaintenance(context.Background(), &aiven.StartMaintenanceInput{ServiceName: "my-service"})
+
+			Expect(err).To(MatchError("Error starting maintenance: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("PowerService", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service"),
+				ghttp.VerifyJSON(`{"powered": false}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := aivenClient.PowerService(context.Background(), &aiven.PowerServiceInput{ServiceName: "my-service", Powered: false})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			err := aivenClient.PowerService(context.Background(), &aiven.PowerServiceInput{ServiceName: "my-service", Powered: true})
+
+			Expect(err).To(MatchError("Error powering service: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("AssignProjectToBillingGroup", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/billing-group/group-1/projects-assign"),
+				ghttp.VerifyJSON(`{"projects_names": ["my-project"]}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := aivenClient.AssignProjectToBillingGroup(context.Background(), &aiven.AssignProjectToBillingGroupInput{BillingGroupID: "group-1"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns ErrBillingGroupDoesNotExist if the billing group does not exist", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{}`),
+			))
+
+			err := aivenClient.AssignProjectToBillingGroup(context.Background(), &aiven.AssignProjectToBillingGroupInput{BillingGroupID: "group-1"})
+
+			Expect(err).To(Equal(aiven.ErrBillingGroupDoesNotExist))
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "{}"),
+			))
+
+			err := aivenClient.AssignProjectToBillingGroup(context.Background(), &aiven.AssignProjectToBillingGroupInput{BillingGroupID: "group-1"})
+
+			Expect(err).To(MatchError("Error assigning project to billing group: 403 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("DeleteServiceIntegration", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/project/my-project/integration/integration-1"),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := aivenClient.DeleteServiceIntegration(context.Background(), &aiven.DeleteServiceIntegrationInput{ServiceIntegrationID: "integration-1"})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "{}"),
+			))
+
+			err := aivenClient.DeleteServiceIntegration(context.Background(), &aiven.DeleteServiceIntegrationInput{ServiceIntegrationID: "integration-1"})
+
+			Expect(err).To(MatchError("Error deleting service integration: 500 status code returned from Aiven: '{}'"))
+		})
+	})
+
+	Describe("UpdateACLConfig", func() {
+		It("should make a valid request", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/project/my-project/service/my-service/elasticsearch/acl"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyHeaderKV("Authorization", "aivenv1 token"),
+				ghttp.VerifyJSON(`{"elasticsearch_acl_config": {
+					"acls": [{"username": "user-1", "rules": [{"index": "*", "permission": "read"}]}],
+					"enabled": true,
+					"extendedAcl": false
+				}}`),
+				ghttp.RespondWith(http.StatusOK, `{}`),
+			))
+
+			err := aivenClient.UpdateACLConfig(context.Background(), &aiven.UpdateACLConfigInput{
+				ServiceName: "my-service",
+				ServiceType: "elasticsearch",
+				ACLConfig: aiven.ACLConfig{
+					ACLs: []aiven.ACL{{
+						Username: "user-1",
+						Rules:    []aiven.ACLRule{{Index: "*", Permission: "read"}},
+					}},
+					Enabled: true,
+				},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns an error if the http request fails", func() {
+			aivenAPI.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.RespondWith(http.StatusBadRequest, "{}"),
+			))
+
+			err := aivenClient.UpdateACLConfig(context.Background(), &aiven.UpdateACLConfigInput{
+				ServiceName: "my-service",
+				ServiceType: "elasticsearch",
+			})
+
+			Expect(err).To(MatchError("Error updating ACL config: 400 status code returned from Aiven: '{}'"))
 		})
 	})
 
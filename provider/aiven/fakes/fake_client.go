@@ -2,16 +2,56 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/alphagov/paas-aiven-broker/provider/aiven"
 )
 
 type FakeClient struct {
-	CreateServiceStub        func(*aiven.CreateServiceInput) (string, error)
+	AssignProjectToBillingGroupStub        func(context.Context, *aiven.AssignProjectToBillingGroupInput) error
+	assignProjectToBillingGroupMutex       sync.RWMutex
+	assignProjectToBillingGroupArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.AssignProjectToBillingGroupInput
+	}
+	assignProjectToBillingGroupReturns struct {
+		result1 error
+	}
+	assignProjectToBillingGroupReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateConnectionPoolStub        func(context.Context, *aiven.CreateConnectionPoolInput) error
+	createConnectionPoolMutex       sync.RWMutex
+	createConnectionPoolArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.CreateConnectionPoolInput
+	}
+	createConnectionPoolReturns struct {
+		result1 error
+	}
+	createConnectionPoolReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateIntegrationEndpointStub        func(context.Context, *aiven.CreateIntegrationEndpointInput) (string, error)
+	createIntegrationEndpointMutex       sync.RWMutex
+	createIntegrationEndpointArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.CreateIntegrationEndpointInput
+	}
+	createIntegrationEndpointReturns struct {
+		result1 string
+		result2 error
+	}
+	createIntegrationEndpointReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	CreateServiceStub        func(context.Context, *aiven.CreateServiceInput) (string, error)
 	createServiceMutex       sync.RWMutex
 	createServiceArgsForCall []struct {
-		arg1 *aiven.CreateServiceInput
+		arg1 context.Context
+		arg2 *aiven.CreateServiceInput
 	}
 	createServiceReturns struct {
 		result1 string
@@ -21,10 +61,37 @@ type FakeClient struct {
 		result1 string
 		result2 error
 	}
-	CreateServiceUserStub        func(*aiven.CreateServiceUserInput) (string, error)
+	CreateServiceDatabaseStub        func(context.Context, *aiven.CreateServiceDatabaseInput) error
+	createServiceDatabaseMutex       sync.RWMutex
+	createServiceDatabaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.CreateServiceDatabaseInput
+	}
+	createServiceDatabaseReturns struct {
+		result1 error
+	}
+	createServiceDatabaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateServiceIntegrationStub        func(context.Context, *aiven.CreateServiceIntegrationInput) (string, error)
+	createServiceIntegrationMutex       sync.RWMutex
+	createServiceIntegrationArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.CreateServiceIntegrationInput
+	}
+	createServiceIntegrationReturns struct {
+		result1 string
+		result2 error
+	}
+	createServiceIntegrationReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	CreateServiceUserStub        func(context.Context, *aiven.CreateServiceUserInput) (string, error)
 	createServiceUserMutex       sync.RWMutex
 	createServiceUserArgsForCall []struct {
-		arg1 *aiven.CreateServiceUserInput
+		arg1 context.Context
+		arg2 *aiven.CreateServiceUserInput
 	}
 	createServiceUserReturns struct {
 		result1 string
@@ -34,10 +101,49 @@ type FakeClient struct {
 		result1 string
 		result2 error
 	}
-	DeleteServiceStub        func(*aiven.DeleteServiceInput) error
+	CreateStaticIPStub        func(context.Context, *aiven.CreateStaticIPInput) (*aiven.StaticIP, error)
+	createStaticIPMutex       sync.RWMutex
+	createStaticIPArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.CreateStaticIPInput
+	}
+	createStaticIPReturns struct {
+		result1 *aiven.StaticIP
+		result2 error
+	}
+	createStaticIPReturnsOnCall map[int]struct {
+		result1 *aiven.StaticIP
+		result2 error
+	}
+	DeleteConnectionPoolStub        func(context.Context, *aiven.DeleteConnectionPoolInput) error
+	deleteConnectionPoolMutex       sync.RWMutex
+	deleteConnectionPoolArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteConnectionPoolInput
+	}
+	deleteConnectionPoolReturns struct {
+		result1 error
+	}
+	deleteConnectionPoolReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteIntegrationEndpointStub        func(context.Context, *aiven.DeleteIntegrationEndpointInput) error
+	deleteIntegrationEndpointMutex       sync.RWMutex
+	deleteIntegrationEndpointArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteIntegrationEndpointInput
+	}
+	deleteIntegrationEndpointReturns struct {
+		result1 error
+	}
+	deleteIntegrationEndpointReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteServiceStub        func(context.Context, *aiven.DeleteServiceInput) error
 	deleteServiceMutex       sync.RWMutex
 	deleteServiceArgsForCall []struct {
-		arg1 *aiven.DeleteServiceInput
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceInput
 	}
 	deleteServiceReturns struct {
 		result1 error
@@ -45,10 +151,35 @@ type FakeClient struct {
 	deleteServiceReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteServiceUserStub        func(*aiven.DeleteServiceUserInput) (string, error)
+	DeleteServiceDatabaseStub        func(context.Context, *aiven.DeleteServiceDatabaseInput) error
+	deleteServiceDatabaseMutex       sync.RWMutex
+	deleteServiceDatabaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceDatabaseInput
+	}
+	deleteServiceDatabaseReturns struct {
+		result1 error
+	}
+	deleteServiceDatabaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteServiceIntegrationStub        func(context.Context, *aiven.DeleteServiceIntegrationInput) error
+	deleteServiceIntegrationMutex       sync.RWMutex
+	deleteServiceIntegrationArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceIntegrationInput
+	}
+	deleteServiceIntegrationReturns struct {
+		result1 error
+	}
+	deleteServiceIntegrationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteServiceUserStub        func(context.Context, *aiven.DeleteServiceUserInput) (string, error)
 	deleteServiceUserMutex       sync.RWMutex
 	deleteServiceUserArgsForCall []struct {
-		arg1 *aiven.DeleteServiceUserInput
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceUserInput
 	}
 	deleteServiceUserReturns struct {
 		result1 string
@@ -58,363 +189,2420 @@ type FakeClient struct {
 		result1 string
 		result2 error
 	}
-	GetServiceStub        func(*aiven.GetServiceInput) (*aiven.Service, error)
+	DeleteStaticIPStub        func(context.Context, *aiven.DeleteStaticIPInput) error
+	deleteStaticIPMutex       sync.RWMutex
+	deleteStaticIPArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteStaticIPInput
+	}
+	deleteStaticIPReturns struct {
+		result1 error
+	}
+	deleteStaticIPReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetACLConfigStub        func(context.Context, *aiven.GetACLConfigInput) (*aiven.ACLConfig, error)
+	getACLConfigMutex       sync.RWMutex
+	getACLConfigArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.GetACLConfigInput
+	}
+	getACLConfigReturns struct {
+		result1 *aiven.ACLConfig
+		result2 error
+	}
+	getACLConfigReturnsOnCall map[int]struct {
+		result1 *aiven.ACLConfig
+		result2 error
+	}
+	GetProjectCAStub        func(context.Context, *aiven.GetProjectCAInput) (string, error)
+	getProjectCAMutex       sync.RWMutex
+	getProjectCAArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.GetProjectCAInput
+	}
+	getProjectCAReturns struct {
+		result1 string
+		result2 error
+	}
+	getProjectCAReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetServiceStub        func(context.Context, *aiven.GetServiceInput) (*aiven.Service, error)
 	getServiceMutex       sync.RWMutex
 	getServiceArgsForCall []struct {
-		arg1 *aiven.GetServiceInput
+		arg1 context.Context
+		arg2 *aiven.GetServiceInput
 	}
 	getServiceReturns struct {
 		result1 *aiven.Service
 		result2 error
 	}
-	getServiceReturnsOnCall map[int]struct {
-		result1 *aiven.Service
+	getServiceReturnsOnCall map[int]struct {
+		result1 *aiven.Service
+		result2 error
+	}
+	GetServiceDiskUsageStub        func(context.Context, *aiven.GetServiceDiskUsageInput) (float64, error)
+	getServiceDiskUsageMutex       sync.RWMutex
+	getServiceDiskUsageArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.GetServiceDiskUsageInput
+	}
+	getServiceDiskUsageReturns struct {
+		result1 float64
+		result2 error
+	}
+	getServiceDiskUsageReturnsOnCall map[int]struct {
+		result1 float64
+		result2 error
+	}
+	GetServicePlansStub        func(context.Context, *aiven.GetServicePlansInput) ([]aiven.ServicePlan, error)
+	getServicePlansMutex       sync.RWMutex
+	getServicePlansArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.GetServicePlansInput
+	}
+	getServicePlansReturns struct {
+		result1 []aiven.ServicePlan
+		result2 error
+	}
+	getServicePlansReturnsOnCall map[int]struct {
+		result1 []aiven.ServicePlan
+		result2 error
+	}
+	GetServiceUserStub        func(context.Context, *aiven.GetServiceUserInput) (*aiven.User, error)
+	getServiceUserMutex       sync.RWMutex
+	getServiceUserArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.GetServiceUserInput
+	}
+	getServiceUserReturns struct {
+		result1 *aiven.User
+		result2 error
+	}
+	getServiceUserReturnsOnCall map[int]struct {
+		result1 *aiven.User
+		result2 error
+	}
+	ListIntegrationEndpointsStub        func(context.Context, *aiven.ListIntegrationEndpointsInput) ([]aiven.IntegrationEndpoint, error)
+	listIntegrationEndpointsMutex       sync.RWMutex
+	listIntegrationEndpointsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListIntegrationEndpointsInput
+	}
+	listIntegrationEndpointsReturns struct {
+		result1 []aiven.IntegrationEndpoint
+		result2 error
+	}
+	listIntegrationEndpointsReturnsOnCall map[int]struct {
+		result1 []aiven.IntegrationEndpoint
+		result2 error
+	}
+	ListMaintenanceUpdatesStub        func(context.Context, *aiven.ListMaintenanceUpdatesInput) ([]aiven.MaintenanceUpdate, error)
+	listMaintenanceUpdatesMutex       sync.RWMutex
+	listMaintenanceUpdatesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListMaintenanceUpdatesInput
+	}
+	listMaintenanceUpdatesReturns struct {
+		result1 []aiven.MaintenanceUpdate
+		result2 error
+	}
+	listMaintenanceUpdatesReturnsOnCall map[int]struct {
+		result1 []aiven.MaintenanceUpdate
+		result2 error
+	}
+	ListProjectVPCsStub        func(context.Context, *aiven.ListProjectVPCsInput) ([]aiven.ProjectVPC, error)
+	listProjectVPCsMutex       sync.RWMutex
+	listProjectVPCsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListProjectVPCsInput
+	}
+	listProjectVPCsReturns struct {
+		result1 []aiven.ProjectVPC
+		result2 error
+	}
+	listProjectVPCsReturnsOnCall map[int]struct {
+		result1 []aiven.ProjectVPC
+		result2 error
+	}
+	ListServiceBackupsStub        func(context.Context, *aiven.ListServiceBackupsInput) ([]aiven.ServiceBackup, error)
+	listServiceBackupsMutex       sync.RWMutex
+	listServiceBackupsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListServiceBackupsInput
+	}
+	listServiceBackupsReturns struct {
+		result1 []aiven.ServiceBackup
+		result2 error
+	}
+	listServiceBackupsReturnsOnCall map[int]struct {
+		result1 []aiven.ServiceBackup
+		result2 error
+	}
+	ListServiceIntegrationsStub        func(context.Context, *aiven.ListServiceIntegrationsInput) ([]aiven.ServiceIntegration, error)
+	listServiceIntegrationsMutex       sync.RWMutex
+	listServiceIntegrationsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListServiceIntegrationsInput
+	}
+	listServiceIntegrationsReturns struct {
+		result1 []aiven.ServiceIntegration
+		result2 error
+	}
+	listServiceIntegrationsReturnsOnCall map[int]struct {
+		result1 []aiven.ServiceIntegration
+		result2 error
+	}
+	ListServiceUsersStub        func(context.Context, *aiven.ListServiceUsersInput) ([]aiven.User, error)
+	listServiceUsersMutex       sync.RWMutex
+	listServiceUsersArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListServiceUsersInput
+	}
+	listServiceUsersReturns struct {
+		result1 []aiven.User
+		result2 error
+	}
+	listServiceUsersReturnsOnCall map[int]struct {
+		result1 []aiven.User
+		result2 error
+	}
+	ListServicesStub        func(context.Context, *aiven.ListServicesInput) ([]aiven.Service, error)
+	listServicesMutex       sync.RWMutex
+	listServicesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListServicesInput
+	}
+	listServicesReturns struct {
+		result1 []aiven.Service
+		result2 error
+	}
+	listServicesReturnsOnCall map[int]struct {
+		result1 []aiven.Service
+		result2 error
+	}
+	ListStaticIPsStub        func(context.Context, *aiven.ListStaticIPsInput) ([]aiven.StaticIP, error)
+	listStaticIPsMutex       sync.RWMutex
+	listStaticIPsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ListStaticIPsInput
+	}
+	listStaticIPsReturns struct {
+		result1 []aiven.StaticIP
+		result2 error
+	}
+	listStaticIPsReturnsOnCall map[int]struct {
+		result1 []aiven.StaticIP
+		result2 error
+	}
+	PowerServiceStub        func(context.Context, *aiven.PowerServiceInput) error
+	powerServiceMutex       sync.RWMutex
+	powerServiceArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.PowerServiceInput
+	}
+	powerServiceReturns struct {
+		result1 error
+	}
+	powerServiceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResetServiceUserCredentialsStub        func(context.Context, *aiven.ResetServiceUserCredentialsInput) (string, error)
+	resetServiceUserCredentialsMutex       sync.RWMutex
+	resetServiceUserCredentialsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.ResetServiceUserCredentialsInput
+	}
+	resetServiceUserCredentialsReturns struct {
+		result1 string
+		result2 error
+	}
+	resetServiceUserCredentialsReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	StartMaintenanceStub        func(context.Context, *aiven.StartMaintenanceInput) error
+	startMaintenanceMutex       sync.RWMutex
+	startMaintenanceArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.StartMaintenanceInput
+	}
+	startMaintenanceReturns struct {
+		result1 error
+	}
+	startMaintenanceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateACLConfigStub        func(context.Context, *aiven.UpdateACLConfigInput) error
+	updateACLConfigMutex       sync.RWMutex
+	updateACLConfigArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.UpdateACLConfigInput
+	}
+	updateACLConfigReturns struct {
+		result1 error
+	}
+	updateACLConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateServiceStub        func(context.Context, *aiven.UpdateServiceInput) (string, error)
+	updateServiceMutex       sync.RWMutex
+	updateServiceArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.UpdateServiceInput
+	}
+	updateServiceReturns struct {
+		result1 string
+		result2 error
+	}
+	updateServiceReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	UpdateServiceTagsStub        func(context.Context, *aiven.UpdateServiceTagsInput) error
+	updateServiceTagsMutex       sync.RWMutex
+	updateServiceTagsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *aiven.UpdateServiceTagsInput
+	}
+	updateServiceTagsReturns struct {
+		result1 error
+	}
+	updateServiceTagsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeClient) AssignProjectToBillingGroup(arg1 context.Context, arg2 *aiven.AssignProjectToBillingGroupInput) error {
+	fake.assignProjectToBillingGroupMutex.Lock()
+	ret, specificReturn := fake.assignProjectToBillingGroupReturnsOnCall[len(fake.assignProjectToBillingGroupArgsForCall)]
+	fake.assignProjectToBillingGroupArgsForCall = append(fake.assignProjectToBillingGroupArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.AssignProjectToBillingGroupInput
+	}{arg1, arg2})
+	stub := fake.AssignProjectToBillingGroupStub
+	fakeReturns := fake.assignProjectToBillingGroupReturns
+	fake.recordInvocation("AssignProjectToBillingGroup", []interface{}{arg1, arg2})
+	fake.assignProjectToBillingGroupMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) AssignProjectToBillingGroupCallCount() int {
+	fake.assignProjectToBillingGroupMutex.RLock()
+	defer fake.assignProjectToBillingGroupMutex.RUnlock()
+	return len(fake.assignProjectToBillingGroupArgsForCall)
+}
+
+func (fake *FakeClient) AssignProjectToBillingGroupCalls(stub func(context.Context, *aiven.AssignProjectToBillingGroupInput) error) {
+	fake.assignProjectToBillingGroupMutex.Lock()
+	defer fake.assignProjectToBillingGroupMutex.Unlock()
+	fake.AssignProjectToBillingGroupStub = stub
+}
+
+func (fake *FakeClient) AssignProjectToBillingGroupArgsForCall(i int) (context.Context, *aiven.AssignProjectToBillingGroupInput) {
+	fake.assignProjectToBillingGroupMutex.RLock()
+	defer fake.assignProjectToBillingGroupMutex.RUnlock()
+	argsForCall := fake.assignProjectToBillingGroupArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) AssignProjectToBillingGroupReturns(result1 error) {
+	fake.assignProjectToBillingGroupMutex.Lock()
+	defer fake.assignProjectToBillingGroupMutex.Unlock()
+	fake.AssignProjectToBillingGroupStub = nil
+	fake.assignProjectToBillingGroupReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) AssignProjectToBillingGroupReturnsOnCall(i int, result1 error) {
+	fake.assignProjectToBillingGroupMutex.Lock()
+	defer fake.assignProjectToBillingGroupMutex.Unlock()
+	fake.AssignProjectToBillingGroupStub = nil
+	if fake.assignProjectToBillingGroupReturnsOnCall == nil {
+		fake.assignProjectToBillingGroupReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.assignProjectToBillingGroupReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateConnectionPool(arg1 context.Context, arg2 *aiven.CreateConnectionPoolInput) error {
+	fake.createConnectionPoolMutex.Lock()
+	ret, specificReturn := fake.createConnectionPoolReturnsOnCall[len(fake.createConnectionPoolArgsForCall)]
+	fake.createConnectionPoolArgsForCall = append(fake.createConnectionPoolArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.CreateConnectionPoolInput
+	}{arg1, arg2})
+	stub := fake.CreateConnectionPoolStub
+	fakeReturns := fake.createConnectionPoolReturns
+	fake.recordInvocation("CreateConnectionPool", []interface{}{arg1, arg2})
+	fake.createConnectionPoolMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateConnectionPoolCallCount() int {
+	fake.createConnectionPoolMutex.RLock()
+	defer fake.createConnectionPoolMutex.RUnlock()
+	return len(fake.createConnectionPoolArgsForCall)
+}
+
+func (fake *FakeClient) CreateConnectionPoolCalls(stub func(context.Context, *aiven.CreateConnectionPoolInput) error) {
+	fake.createConnectionPoolMutex.Lock()
+	defer fake.createConnectionPoolMutex.Unlock()
+	fake.CreateConnectionPoolStub = stub
+}
+
+func (fake *FakeClient) CreateConnectionPoolArgsForCall(i int) (context.Context, *aiven.CreateConnectionPoolInput) {
+	fake.createConnectionPoolMutex.RLock()
+	defer fake.createConnectionPoolMutex.RUnlock()
+	argsForCall := fake.createConnectionPoolArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateConnectionPoolReturns(result1 error) {
+	fake.createConnectionPoolMutex.Lock()
+	defer fake.createConnectionPoolMutex.Unlock()
+	fake.CreateConnectionPoolStub = nil
+	fake.createConnectionPoolReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateConnectionPoolReturnsOnCall(i int, result1 error) {
+	fake.createConnectionPoolMutex.Lock()
+	defer fake.createConnectionPoolMutex.Unlock()
+	fake.CreateConnectionPoolStub = nil
+	if fake.createConnectionPoolReturnsOnCall == nil {
+		fake.createConnectionPoolReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createConnectionPoolReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateIntegrationEndpoint(arg1 context.Context, arg2 *aiven.CreateIntegrationEndpointInput) (string, error) {
+	fake.createIntegrationEndpointMutex.Lock()
+	ret, specificReturn := fake.createIntegrationEndpointReturnsOnCall[len(fake.createIntegrationEndpointArgsForCall)]
+	fake.createIntegrationEndpointArgsForCall = append(fake.createIntegrationEndpointArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.CreateIntegrationEndpointInput
+	}{arg1, arg2})
+	stub := fake.CreateIntegrationEndpointStub
+	fakeReturns := fake.createIntegrationEndpointReturns
+	fake.recordInvocation("CreateIntegrationEndpoint", []interface{}{arg1, arg2})
+	fake.createIntegrationEndpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CreateIntegrationEndpointCallCount() int {
+	fake.createIntegrationEndpointMutex.RLock()
+	defer fake.createIntegrationEndpointMutex.RUnlock()
+	return len(fake.createIntegrationEndpointArgsForCall)
+}
+
+func (fake *FakeClient) CreateIntegrationEndpointCalls(stub func(context.Context, *aiven.CreateIntegrationEndpointInput) (string, error)) {
+	fake.createIntegrationEndpointMutex.Lock()
+	defer fake.createIntegrationEndpointMutex.Unlock()
+	fake.CreateIntegrationEndpointStub = stub
+}
+
+func (fake *FakeClient) CreateIntegrationEndpointArgsForCall(i int) (context.Context, *aiven.CreateIntegrationEndpointInput) {
+	fake.createIntegrationEndpointMutex.RLock()
+	defer fake.createIntegrationEndpointMutex.RUnlock()
+	argsForCall := fake.createIntegrationEndpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateIntegrationEndpointReturns(result1 string, result2 error) {
+	fake.createIntegrationEndpointMutex.Lock()
+	defer fake.createIntegrationEndpointMutex.Unlock()
+	fake.CreateIntegrationEndpointStub = nil
+	fake.createIntegrationEndpointReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateIntegrationEndpointReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createIntegrationEndpointMutex.Lock()
+	defer fake.createIntegrationEndpointMutex.Unlock()
+	fake.CreateIntegrationEndpointStub = nil
+	if fake.createIntegrationEndpointReturnsOnCall == nil {
+		fake.createIntegrationEndpointReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createIntegrationEndpointReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateService(arg1 context.Context, arg2 *aiven.CreateServiceInput) (string, error) {
+	fake.createServiceMutex.Lock()
+	ret, specificReturn := fake.createServiceReturnsOnCall[len(fake.createServiceArgsForCall)]
+	fake.createServiceArgsForCall = append(fake.createServiceArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.CreateServiceInput
+	}{arg1, arg2})
+	stub := fake.CreateServiceStub
+	fakeReturns := fake.createServiceReturns
+	fake.recordInvocation("CreateService", []interface{}{arg1, arg2})
+	fake.createServiceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CreateServiceCallCount() int {
+	fake.createServiceMutex.RLock()
+	defer fake.createServiceMutex.RUnlock()
+	return len(fake.createServiceArgsForCall)
+}
+
+func (fake *FakeClient) CreateServiceCalls(stub func(context.Context, *aiven.CreateServiceInput) (string, error)) {
+	fake.createServiceMutex.Lock()
+	defer fake.createServiceMutex.Unlock()
+	fake.CreateServiceStub = stub
+}
+
+func (fake *FakeClient) CreateServiceArgsForCall(i int) (context.Context, *aiven.CreateServiceInput) {
+	fake.createServiceMutex.RLock()
+	defer fake.createServiceMutex.RUnlock()
+	argsForCall := fake.createServiceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateServiceReturns(result1 string, result2 error) {
+	fake.createServiceMutex.Lock()
+	defer fake.createServiceMutex.Unlock()
+	fake.CreateServiceStub = nil
+	fake.createServiceReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateServiceReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createServiceMutex.Lock()
+	defer fake.createServiceMutex.Unlock()
+	fake.CreateServiceStub = nil
+	if fake.createServiceReturnsOnCall == nil {
+		fake.createServiceReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createServiceReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateServiceDatabase(arg1 context.Context, arg2 *aiven.CreateServiceDatabaseInput) error {
+	fake.createServiceDatabaseMutex.Lock()
+	ret, specificReturn := fake.createServiceDatabaseReturnsOnCall[len(fake.createServiceDatabaseArgsForCall)]
+	fake.createServiceDatabaseArgsForCall = append(fake.createServiceDatabaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.CreateServiceDatabaseInput
+	}{arg1, arg2})
+	stub := fake.CreateServiceDatabaseStub
+	fakeReturns := fake.createServiceDatabaseReturns
+	fake.recordInvocation("CreateServiceDatabase", []interface{}{arg1, arg2})
+	fake.createServiceDatabaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateServiceDatabaseCallCount() int {
+	fake.createServiceDatabaseMutex.RLock()
+	defer fake.createServiceDatabaseMutex.RUnlock()
+	return len(fake.createServiceDatabaseArgsForCall)
+}
+
+func (fake *FakeClient) CreateServiceDatabaseCalls(stub func(context.Context, *aiven.CreateServiceDatabaseInput) error) {
+	fake.createServiceDatabaseMutex.Lock()
+	defer fake.createServiceDatabaseMutex.Unlock()
+	fake.CreateServiceDatabaseStub = stub
+}
+
+func (fake *FakeClient) CreateServiceDatabaseArgsForCall(i int) (context.Context, *aiven.CreateServiceDatabaseInput) {
+	fake.createServiceDatabaseMutex.RLock()
+	defer fake.createServiceDatabaseMutex.RUnlock()
+	argsForCall := fake.createServiceDatabaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateServiceDatabaseReturns(result1 error) {
+	fake.createServiceDatabaseMutex.Lock()
+	defer fake.createServiceDatabaseMutex.Unlock()
+	fake.CreateServiceDatabaseStub = nil
+	fake.createServiceDatabaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateServiceDatabaseReturnsOnCall(i int, result1 error) {
+	fake.createServiceDatabaseMutex.Lock()
+	defer fake.createServiceDatabaseMutex.Unlock()
+	fake.CreateServiceDatabaseStub = nil
+	if fake.createServiceDatabaseReturnsOnCall == nil {
+		fake.createServiceDatabaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createServiceDatabaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateServiceIntegration(arg1 context.Context, arg2 *aiven.CreateServiceIntegrationInput) (string, error) {
+	fake.createServiceIntegrationMutex.Lock()
+	ret, specificReturn := fake.createServiceIntegrationReturnsOnCall[len(fake.createServiceIntegrationArgsForCall)]
+	fake.createServiceIntegrationArgsForCall = append(fake.createServiceIntegrationArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.CreateServiceIntegrationInput
+	}{arg1, arg2})
+	stub := fake.CreateServiceIntegrationStub
+	fakeReturns := fake.createServiceIntegrationReturns
+	fake.recordInvocation("CreateServiceIntegration", []interface{}{arg1, arg2})
+	fake.createServiceIntegrationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CreateServiceIntegrationCallCount() int {
+	fake.createServiceIntegrationMutex.RLock()
+	defer fake.createServiceIntegrationMutex.RUnlock()
+	return len(fake.createServiceIntegrationArgsForCall)
+}
+
+func (fake *FakeClient) CreateServiceIntegrationCalls(stub func(context.Context, *aiven.CreateServiceIntegrationInput) (string, error)) {
+	fake.createServiceIntegrationMutex.Lock()
+	defer fake.createServiceIntegrationMutex.Unlock()
+	fake.CreateServiceIntegrationStub = stub
+}
+
+func (fake *FakeClient) CreateServiceIntegrationArgsForCall(i int) (context.Context, *aiven.CreateServiceIntegrationInput) {
+	fake.createServiceIntegrationMutex.RLock()
+	defer fake.createServiceIntegrationMutex.RUnlock()
+	argsForCall := fake.createServiceIntegrationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateServiceIntegrationReturns(result1 string, result2 error) {
+	fake.createServiceIntegrationMutex.Lock()
+	defer fake.createServiceIntegrationMutex.Unlock()
+	fake.CreateServiceIntegrationStub = nil
+	fake.createServiceIntegrationReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateServiceIntegrationReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createServiceIntegrationMutex.Lock()
+	defer fake.createServiceIntegrationMutex.Unlock()
+	fake.CreateServiceIntegrationStub = nil
+	if fake.createServiceIntegrationReturnsOnCall == nil {
+		fake.createServiceIntegrationReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createServiceIntegrationReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateServiceUser(arg1 context.Context, arg2 *aiven.CreateServiceUserInput) (string, error) {
+	fake.createServiceUserMutex.Lock()
+	ret, specificReturn := fake.createServiceUserReturnsOnCall[len(fake.createServiceUserArgsForCall)]
+	fake.createServiceUserArgsForCall = append(fake.createServiceUserArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.CreateServiceUserInput
+	}{arg1, arg2})
+	stub := fake.CreateServiceUserStub
+	fakeReturns := fake.createServiceUserReturns
+	fake.recordInvocation("CreateServiceUser", []interface{}{arg1, arg2})
+	fake.createServiceUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CreateServiceUserCallCount() int {
+	fake.createServiceUserMutex.RLock()
+	defer fake.createServiceUserMutex.RUnlock()
+	return len(fake.createServiceUserArgsForCall)
+}
+
+func (fake *FakeClient) CreateServiceUserCalls(stub func(context.Context, *aiven.CreateServiceUserInput) (string, error)) {
+	fake.createServiceUserMutex.Lock()
+	defer fake.createServiceUserMutex.Unlock()
+	fake.CreateServiceUserStub = stub
+}
+
+func (fake *FakeClient) CreateServiceUserArgsForCall(i int) (context.Context, *aiven.CreateServiceUserInput) {
+	fake.createServiceUserMutex.RLock()
+	defer fake.createServiceUserMutex.RUnlock()
+	argsForCall := fake.createServiceUserArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateServiceUserReturns(result1 string, result2 error) {
+	fake.createServiceUserMutex.Lock()
+	defer fake.createServiceUserMutex.Unlock()
+	fake.CreateServiceUserStub = nil
+	fake.createServiceUserReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateServiceUserReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createServiceUserMutex.Lock()
+	defer fake.createServiceUserMutex.Unlock()
+	fake.CreateServiceUserStub = nil
+	if fake.createServiceUserReturnsOnCall == nil {
+		fake.createServiceUserReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createServiceUserReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateStaticIP(arg1 context.Context, arg2 *aiven.CreateStaticIPInput) (*aiven.StaticIP, error) {
+	fake.createStaticIPMutex.Lock()
+	ret, specificReturn := fake.createStaticIPReturnsOnCall[len(fake.createStaticIPArgsForCall)]
+	fake.createStaticIPArgsForCall = append(fake.createStaticIPArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.CreateStaticIPInput
+	}{arg1, arg2})
+	stub := fake.CreateStaticIPStub
+	fakeReturns := fake.createStaticIPReturns
+	fake.recordInvocation("CreateStaticIP", []interface{}{arg1, arg2})
+	fake.createStaticIPMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CreateStaticIPCallCount() int {
+	fake.createStaticIPMutex.RLock()
+	defer fake.createStaticIPMutex.RUnlock()
+	return len(fake.createStaticIPArgsForCall)
+}
+
+func (fake *FakeClient) CreateStaticIPCalls(stub func(context.Context, *aiven.CreateStaticIPInput) (*aiven.StaticIP, error)) {
+	fake.createStaticIPMutex.Lock()
+	defer fake.createStaticIPMutex.Unlock()
+	fake.CreateStaticIPStub = stub
+}
+
+func (fake *FakeClient) CreateStaticIPArgsForCall(i int) (context.Context, *aiven.CreateStaticIPInput) {
+	fake.createStaticIPMutex.RLock()
+	defer fake.createStaticIPMutex.RUnlock()
+	argsForCall := fake.createStaticIPArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) CreateStaticIPReturns(result1 *aiven.StaticIP, result2 error) {
+	fake.createStaticIPMutex.Lock()
+	defer fake.createStaticIPMutex.Unlock()
+	fake.CreateStaticIPStub = nil
+	fake.createStaticIPReturns = struct {
+		result1 *aiven.StaticIP
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CreateStaticIPReturnsOnCall(i int, result1 *aiven.StaticIP, result2 error) {
+	fake.createStaticIPMutex.Lock()
+	defer fake.createStaticIPMutex.Unlock()
+	fake.CreateStaticIPStub = nil
+	if fake.createStaticIPReturnsOnCall == nil {
+		fake.createStaticIPReturnsOnCall = make(map[int]struct {
+			result1 *aiven.StaticIP
+			result2 error
+		})
+	}
+	fake.createStaticIPReturnsOnCall[i] = struct {
+		result1 *aiven.StaticIP
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteConnectionPool(arg1 context.Context, arg2 *aiven.DeleteConnectionPoolInput) error {
+	fake.deleteConnectionPoolMutex.Lock()
+	ret, specificReturn := fake.deleteConnectionPoolReturnsOnCall[len(fake.deleteConnectionPoolArgsForCall)]
+	fake.deleteConnectionPoolArgsForCall = append(fake.deleteConnectionPoolArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteConnectionPoolInput
+	}{arg1, arg2})
+	stub := fake.DeleteConnectionPoolStub
+	fakeReturns := fake.deleteConnectionPoolReturns
+	fake.recordInvocation("DeleteConnectionPool", []interface{}{arg1, arg2})
+	fake.deleteConnectionPoolMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteConnectionPoolCallCount() int {
+	fake.deleteConnectionPoolMutex.RLock()
+	defer fake.deleteConnectionPoolMutex.RUnlock()
+	return len(fake.deleteConnectionPoolArgsForCall)
+}
+
+func (fake *FakeClient) DeleteConnectionPoolCalls(stub func(context.Context, *aiven.DeleteConnectionPoolInput) error) {
+	fake.deleteConnectionPoolMutex.Lock()
+	defer fake.deleteConnectionPoolMutex.Unlock()
+	fake.DeleteConnectionPoolStub = stub
+}
+
+func (fake *FakeClient) DeleteConnectionPoolArgsForCall(i int) (context.Context, *aiven.DeleteConnectionPoolInput) {
+	fake.deleteConnectionPoolMutex.RLock()
+	defer fake.deleteConnectionPoolMutex.RUnlock()
+	argsForCall := fake.deleteConnectionPoolArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteConnectionPoolReturns(result1 error) {
+	fake.deleteConnectionPoolMutex.Lock()
+	defer fake.deleteConnectionPoolMutex.Unlock()
+	fake.DeleteConnectionPoolStub = nil
+	fake.deleteConnectionPoolReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteConnectionPoolReturnsOnCall(i int, result1 error) {
+	fake.deleteConnectionPoolMutex.Lock()
+	defer fake.deleteConnectionPoolMutex.Unlock()
+	fake.DeleteConnectionPoolStub = nil
+	if fake.deleteConnectionPoolReturnsOnCall == nil {
+		fake.deleteConnectionPoolReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteConnectionPoolReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteIntegrationEndpoint(arg1 context.Context, arg2 *aiven.DeleteIntegrationEndpointInput) error {
+	fake.deleteIntegrationEndpointMutex.Lock()
+	ret, specificReturn := fake.deleteIntegrationEndpointReturnsOnCall[len(fake.deleteIntegrationEndpointArgsForCall)]
+	fake.deleteIntegrationEndpointArgsForCall = append(fake.deleteIntegrationEndpointArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteIntegrationEndpointInput
+	}{arg1, arg2})
+	stub := fake.DeleteIntegrationEndpointStub
+	fakeReturns := fake.deleteIntegrationEndpointReturns
+	fake.recordInvocation("DeleteIntegrationEndpoint", []interface{}{arg1, arg2})
+	fake.deleteIntegrationEndpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteIntegrationEndpointCallCount() int {
+	fake.deleteIntegrationEndpointMutex.RLock()
+	defer fake.deleteIntegrationEndpointMutex.RUnlock()
+	return len(fake.deleteIntegrationEndpointArgsForCall)
+}
+
+func (fake *FakeClient) DeleteIntegrationEndpointCalls(stub func(context.Context, *aiven.DeleteIntegrationEndpointInput) error) {
+	fake.deleteIntegrationEndpointMutex.Lock()
+	defer fake.deleteIntegrationEndpointMutex.Unlock()
+	fake.DeleteIntegrationEndpointStub = stub
+}
+
+func (fake *FakeClient) DeleteIntegrationEndpointArgsForCall(i int) (context.Context, *aiven.DeleteIntegrationEndpointInput) {
+	fake.deleteIntegrationEndpointMutex.RLock()
+	defer fake.deleteIntegrationEndpointMutex.RUnlock()
+	argsForCall := fake.deleteIntegrationEndpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteIntegrationEndpointReturns(result1 error) {
+	fake.deleteIntegrationEndpointMutex.Lock()
+	defer fake.deleteIntegrationEndpointMutex.Unlock()
+	fake.DeleteIntegrationEndpointStub = nil
+	fake.deleteIntegrationEndpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteIntegrationEndpointReturnsOnCall(i int, result1 error) {
+	fake.deleteIntegrationEndpointMutex.Lock()
+	defer fake.deleteIntegrationEndpointMutex.Unlock()
+	fake.DeleteIntegrationEndpointStub = nil
+	if fake.deleteIntegrationEndpointReturnsOnCall == nil {
+		fake.deleteIntegrationEndpointReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteIntegrationEndpointReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteService(arg1 context.Context, arg2 *aiven.DeleteServiceInput) error {
+	fake.deleteServiceMutex.Lock()
+	ret, specificReturn := fake.deleteServiceReturnsOnCall[len(fake.deleteServiceArgsForCall)]
+	fake.deleteServiceArgsForCall = append(fake.deleteServiceArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceInput
+	}{arg1, arg2})
+	stub := fake.DeleteServiceStub
+	fakeReturns := fake.deleteServiceReturns
+	fake.recordInvocation("DeleteService", []interface{}{arg1, arg2})
+	fake.deleteServiceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteServiceCallCount() int {
+	fake.deleteServiceMutex.RLock()
+	defer fake.deleteServiceMutex.RUnlock()
+	return len(fake.deleteServiceArgsForCall)
+}
+
+func (fake *FakeClient) DeleteServiceCalls(stub func(context.Context, *aiven.DeleteServiceInput) error) {
+	fake.deleteServiceMutex.Lock()
+	defer fake.deleteServiceMutex.Unlock()
+	fake.DeleteServiceStub = stub
+}
+
+func (fake *FakeClient) DeleteServiceArgsForCall(i int) (context.Context, *aiven.DeleteServiceInput) {
+	fake.deleteServiceMutex.RLock()
+	defer fake.deleteServiceMutex.RUnlock()
+	argsForCall := fake.deleteServiceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteServiceReturns(result1 error) {
+	fake.deleteServiceMutex.Lock()
+	defer fake.deleteServiceMutex.Unlock()
+	fake.DeleteServiceStub = nil
+	fake.deleteServiceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceReturnsOnCall(i int, result1 error) {
+	fake.deleteServiceMutex.Lock()
+	defer fake.deleteServiceMutex.Unlock()
+	fake.DeleteServiceStub = nil
+	if fake.deleteServiceReturnsOnCall == nil {
+		fake.deleteServiceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteServiceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceDatabase(arg1 context.Context, arg2 *aiven.DeleteServiceDatabaseInput) error {
+	fake.deleteServiceDatabaseMutex.Lock()
+	ret, specificReturn := fake.deleteServiceDatabaseReturnsOnCall[len(fake.deleteServiceDatabaseArgsForCall)]
+	fake.deleteServiceDatabaseArgsForCall = append(fake.deleteServiceDatabaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceDatabaseInput
+	}{arg1, arg2})
+	stub := fake.DeleteServiceDatabaseStub
+	fakeReturns := fake.deleteServiceDatabaseReturns
+	fake.recordInvocation("DeleteServiceDatabase", []interface{}{arg1, arg2})
+	fake.deleteServiceDatabaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteServiceDatabaseCallCount() int {
+	fake.deleteServiceDatabaseMutex.RLock()
+	defer fake.deleteServiceDatabaseMutex.RUnlock()
+	return len(fake.deleteServiceDatabaseArgsForCall)
+}
+
+func (fake *FakeClient) DeleteServiceDatabaseCalls(stub func(context.Context, *aiven.DeleteServiceDatabaseInput) error) {
+	fake.deleteServiceDatabaseMutex.Lock()
+	defer fake.deleteServiceDatabaseMutex.Unlock()
+	fake.DeleteServiceDatabaseStub = stub
+}
+
+func (fake *FakeClient) DeleteServiceDatabaseArgsForCall(i int) (context.Context, *aiven.DeleteServiceDatabaseInput) {
+	fake.deleteServiceDatabaseMutex.RLock()
+	defer fake.deleteServiceDatabaseMutex.RUnlock()
+	argsForCall := fake.deleteServiceDatabaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteServiceDatabaseReturns(result1 error) {
+	fake.deleteServiceDatabaseMutex.Lock()
+	defer fake.deleteServiceDatabaseMutex.Unlock()
+	fake.DeleteServiceDatabaseStub = nil
+	fake.deleteServiceDatabaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceDatabaseReturnsOnCall(i int, result1 error) {
+	fake.deleteServiceDatabaseMutex.Lock()
+	defer fake.deleteServiceDatabaseMutex.Unlock()
+	fake.DeleteServiceDatabaseStub = nil
+	if fake.deleteServiceDatabaseReturnsOnCall == nil {
+		fake.deleteServiceDatabaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteServiceDatabaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceIntegration(arg1 context.Context, arg2 *aiven.DeleteServiceIntegrationInput) error {
+	fake.deleteServiceIntegrationMutex.Lock()
+	ret, specificReturn := fake.deleteServiceIntegrationReturnsOnCall[len(fake.deleteServiceIntegrationArgsForCall)]
+	fake.deleteServiceIntegrationArgsForCall = append(fake.deleteServiceIntegrationArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceIntegrationInput
+	}{arg1, arg2})
+	stub := fake.DeleteServiceIntegrationStub
+	fakeReturns := fake.deleteServiceIntegrationReturns
+	fake.recordInvocation("DeleteServiceIntegration", []interface{}{arg1, arg2})
+	fake.deleteServiceIntegrationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteServiceIntegrationCallCount() int {
+	fake.deleteServiceIntegrationMutex.RLock()
+	defer fake.deleteServiceIntegrationMutex.RUnlock()
+	return len(fake.deleteServiceIntegrationArgsForCall)
+}
+
+func (fake *FakeClient) DeleteServiceIntegrationCalls(stub func(context.Context, *aiven.DeleteServiceIntegrationInput) error) {
+	fake.deleteServiceIntegrationMutex.Lock()
+	defer fake.deleteServiceIntegrationMutex.Unlock()
+	fake.DeleteServiceIntegrationStub = stub
+}
+
+func (fake *FakeClient) DeleteServiceIntegrationArgsForCall(i int) (context.Context, *aiven.DeleteServiceIntegrationInput) {
+	fake.deleteServiceIntegrationMutex.RLock()
+	defer fake.deleteServiceIntegrationMutex.RUnlock()
+	argsForCall := fake.deleteServiceIntegrationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteServiceIntegrationReturns(result1 error) {
+	fake.deleteServiceIntegrationMutex.Lock()
+	defer fake.deleteServiceIntegrationMutex.Unlock()
+	fake.DeleteServiceIntegrationStub = nil
+	fake.deleteServiceIntegrationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceIntegrationReturnsOnCall(i int, result1 error) {
+	fake.deleteServiceIntegrationMutex.Lock()
+	defer fake.deleteServiceIntegrationMutex.Unlock()
+	fake.DeleteServiceIntegrationStub = nil
+	if fake.deleteServiceIntegrationReturnsOnCall == nil {
+		fake.deleteServiceIntegrationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteServiceIntegrationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteServiceUser(arg1 context.Context, arg2 *aiven.DeleteServiceUserInput) (string, error) {
+	fake.deleteServiceUserMutex.Lock()
+	ret, specificReturn := fake.deleteServiceUserReturnsOnCall[len(fake.deleteServiceUserArgsForCall)]
+	fake.deleteServiceUserArgsForCall = append(fake.deleteServiceUserArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteServiceUserInput
+	}{arg1, arg2})
+	stub := fake.DeleteServiceUserStub
+	fakeReturns := fake.deleteServiceUserReturns
+	fake.recordInvocation("DeleteServiceUser", []interface{}{arg1, arg2})
+	fake.deleteServiceUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteServiceUserCallCount() int {
+	fake.deleteServiceUserMutex.RLock()
+	defer fake.deleteServiceUserMutex.RUnlock()
+	return len(fake.deleteServiceUserArgsForCall)
+}
+
+func (fake *FakeClient) DeleteServiceUserCalls(stub func(context.Context, *aiven.DeleteServiceUserInput) (string, error)) {
+	fake.deleteServiceUserMutex.Lock()
+	defer fake.deleteServiceUserMutex.Unlock()
+	fake.DeleteServiceUserStub = stub
+}
+
+func (fake *FakeClient) DeleteServiceUserArgsForCall(i int) (context.Context, *aiven.DeleteServiceUserInput) {
+	fake.deleteServiceUserMutex.RLock()
+	defer fake.deleteServiceUserMutex.RUnlock()
+	argsForCall := fake.deleteServiceUserArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteServiceUserReturns(result1 string, result2 error) {
+	fake.deleteServiceUserMutex.Lock()
+	defer fake.deleteServiceUserMutex.Unlock()
+	fake.DeleteServiceUserStub = nil
+	fake.deleteServiceUserReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteServiceUserReturnsOnCall(i int, result1 string, result2 error) {
+	fake.deleteServiceUserMutex.Lock()
+	defer fake.deleteServiceUserMutex.Unlock()
+	fake.DeleteServiceUserStub = nil
+	if fake.deleteServiceUserReturnsOnCall == nil {
+		fake.deleteServiceUserReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.deleteServiceUserReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteStaticIP(arg1 context.Context, arg2 *aiven.DeleteStaticIPInput) error {
+	fake.deleteStaticIPMutex.Lock()
+	ret, specificReturn := fake.deleteStaticIPReturnsOnCall[len(fake.deleteStaticIPArgsForCall)]
+	fake.deleteStaticIPArgsForCall = append(fake.deleteStaticIPArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.DeleteStaticIPInput
+	}{arg1, arg2})
+	stub := fake.DeleteStaticIPStub
+	fakeReturns := fake.deleteStaticIPReturns
+	fake.recordInvocation("DeleteStaticIP", []interface{}{arg1, arg2})
+	fake.deleteStaticIPMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteStaticIPCallCount() int {
+	fake.deleteStaticIPMutex.RLock()
+	defer fake.deleteStaticIPMutex.RUnlock()
+	return len(fake.deleteStaticIPArgsForCall)
+}
+
+func (fake *FakeClient) DeleteStaticIPCalls(stub func(context.Context, *aiven.DeleteStaticIPInput) error) {
+	fake.deleteStaticIPMutex.Lock()
+	defer fake.deleteStaticIPMutex.Unlock()
+	fake.DeleteStaticIPStub = stub
+}
+
+func (fake *FakeClient) DeleteStaticIPArgsForCall(i int) (context.Context, *aiven.DeleteStaticIPInput) {
+	fake.deleteStaticIPMutex.RLock()
+	defer fake.deleteStaticIPMutex.RUnlock()
+	argsForCall := fake.deleteStaticIPArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteStaticIPReturns(result1 error) {
+	fake.deleteStaticIPMutex.Lock()
+	defer fake.deleteStaticIPMutex.Unlock()
+	fake.DeleteStaticIPStub = nil
+	fake.deleteStaticIPReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteStaticIPReturnsOnCall(i int, result1 error) {
+	fake.deleteStaticIPMutex.Lock()
+	defer fake.deleteStaticIPMutex.Unlock()
+	fake.DeleteStaticIPStub = nil
+	if fake.deleteStaticIPReturnsOnCall == nil {
+		fake.deleteStaticIPReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteStaticIPReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetACLConfig(arg1 context.Context, arg2 *aiven.GetACLConfigInput) (*aiven.ACLConfig, error) {
+	fake.getACLConfigMutex.Lock()
+	ret, specificReturn := fake.getACLConfigReturnsOnCall[len(fake.getACLConfigArgsForCall)]
+	fake.getACLConfigArgsForCall = append(fake.getACLConfigArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.GetACLConfigInput
+	}{arg1, arg2})
+	stub := fake.GetACLConfigStub
+	fakeReturns := fake.getACLConfigReturns
+	fake.recordInvocation("GetACLConfig", []interface{}{arg1, arg2})
+	fake.getACLConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetACLConfigCallCount() int {
+	fake.getACLConfigMutex.RLock()
+	defer fake.getACLConfigMutex.RUnlock()
+	return len(fake.getACLConfigArgsForCall)
+}
+
+func (fake *FakeClient) GetACLConfigCalls(stub func(context.Context, *aiven.GetACLConfigInput) (*aiven.ACLConfig, error)) {
+	fake.getACLConfigMutex.Lock()
+	defer fake.getACLConfigMutex.Unlock()
+	fake.GetACLConfigStub = stub
+}
+
+func (fake *FakeClient) GetACLConfigArgsForCall(i int) (context.Context, *aiven.GetACLConfigInput) {
+	fake.getACLConfigMutex.RLock()
+	defer fake.getACLConfigMutex.RUnlock()
+	argsForCall := fake.getACLConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) GetACLConfigReturns(result1 *aiven.ACLConfig, result2 error) {
+	fake.getACLConfigMutex.Lock()
+	defer fake.getACLConfigMutex.Unlock()
+	fake.GetACLConfigStub = nil
+	fake.getACLConfigReturns = struct {
+		result1 *aiven.ACLConfig
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetACLConfigReturnsOnCall(i int, result1 *aiven.ACLConfig, result2 error) {
+	fake.getACLConfigMutex.Lock()
+	defer fake.getACLConfigMutex.Unlock()
+	fake.GetACLConfigStub = nil
+	if fake.getACLConfigReturnsOnCall == nil {
+		fake.getACLConfigReturnsOnCall = make(map[int]struct {
+			result1 *aiven.ACLConfig
+			result2 error
+		})
+	}
+	fake.getACLConfigReturnsOnCall[i] = struct {
+		result1 *aiven.ACLConfig
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetProjectCA(arg1 context.Context, arg2 *aiven.GetProjectCAInput) (string, error) {
+	fake.getProjectCAMutex.Lock()
+	ret, specificReturn := fake.getProjectCAReturnsOnCall[len(fake.getProjectCAArgsForCall)]
+	fake.getProjectCAArgsForCall = append(fake.getProjectCAArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.GetProjectCAInput
+	}{arg1, arg2})
+	stub := fake.GetProjectCAStub
+	fakeReturns := fake.getProjectCAReturns
+	fake.recordInvocation("GetProjectCA", []interface{}{arg1, arg2})
+	fake.getProjectCAMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetProjectCACallCount() int {
+	fake.getProjectCAMutex.RLock()
+	defer fake.getProjectCAMutex.RUnlock()
+	return len(fake.getProjectCAArgsForCall)
+}
+
+func (fake *FakeClient) GetProjectCACalls(stub func(context.Context, *aiven.GetProjectCAInput) (string, error)) {
+	fake.getProjectCAMutex.Lock()
+	defer fake.getProjectCAMutex.Unlock()
+	fake.GetProjectCAStub = stub
+}
+
+func (fake *FakeClient) GetProjectCAArgsForCall(i int) (context.Context, *aiven.GetProjectCAInput) {
+	fake.getProjectCAMutex.RLock()
+	defer fake.getProjectCAMutex.RUnlock()
+	argsForCall := fake.getProjectCAArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) GetProjectCAReturns(result1 string, result2 error) {
+	fake.getProjectCAMutex.Lock()
+	defer fake.getProjectCAMutex.Unlock()
+	fake.GetProjectCAStub = nil
+	fake.getProjectCAReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetProjectCAReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getProjectCAMutex.Lock()
+	defer fake.getProjectCAMutex.Unlock()
+	fake.GetProjectCAStub = nil
+	if fake.getProjectCAReturnsOnCall == nil {
+		fake.getProjectCAReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getProjectCAReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetService(arg1 context.Context, arg2 *aiven.GetServiceInput) (*aiven.Service, error) {
+	fake.getServiceMutex.Lock()
+	ret, specificReturn := fake.getServiceReturnsOnCall[len(fake.getServiceArgsForCall)]
+	fake.getServiceArgsForCall = append(fake.getServiceArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.GetServiceInput
+	}{arg1, arg2})
+	stub := fake.GetServiceStub
+	fakeReturns := fake.getServiceReturns
+	fake.recordInvocation("GetService", []interface{}{arg1, arg2})
+	fake.getServiceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetServiceCallCount() int {
+	fake.getServiceMutex.RLock()
+	defer fake.getServiceMutex.RUnlock()
+	return len(fake.getServiceArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceCalls(stub func(context.Context, *aiven.GetServiceInput) (*aiven.Service, error)) {
+	fake.getServiceMutex.Lock()
+	defer fake.getServiceMutex.Unlock()
+	fake.GetServiceStub = stub
+}
+
+func (fake *FakeClient) GetServiceArgsForCall(i int) (context.Context, *aiven.GetServiceInput) {
+	fake.getServiceMutex.RLock()
+	defer fake.getServiceMutex.RUnlock()
+	argsForCall := fake.getServiceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) GetServiceReturns(result1 *aiven.Service, result2 error) {
+	fake.getServiceMutex.Lock()
+	defer fake.getServiceMutex.Unlock()
+	fake.GetServiceStub = nil
+	fake.getServiceReturns = struct {
+		result1 *aiven.Service
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceReturnsOnCall(i int, result1 *aiven.Service, result2 error) {
+	fake.getServiceMutex.Lock()
+	defer fake.getServiceMutex.Unlock()
+	fake.GetServiceStub = nil
+	if fake.getServiceReturnsOnCall == nil {
+		fake.getServiceReturnsOnCall = make(map[int]struct {
+			result1 *aiven.Service
+			result2 error
+		})
+	}
+	fake.getServiceReturnsOnCall[i] = struct {
+		result1 *aiven.Service
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceDiskUsage(arg1 context.Context, arg2 *aiven.GetServiceDiskUsageInput) (float64, error) {
+	fake.getServiceDiskUsageMutex.Lock()
+	ret, specificReturn := fake.getServiceDiskUsageReturnsOnCall[len(fake.getServiceDiskUsageArgsForCall)]
+	fake.getServiceDiskUsageArgsForCall = append(fake.getServiceDiskUsageArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.GetServiceDiskUsageInput
+	}{arg1, arg2})
+	stub := fake.GetServiceDiskUsageStub
+	fakeReturns := fake.getServiceDiskUsageReturns
+	fake.recordInvocation("GetServiceDiskUsage", []interface{}{arg1, arg2})
+	fake.getServiceDiskUsageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetServiceDiskUsageCallCount() int {
+	fake.getServiceDiskUsageMutex.RLock()
+	defer fake.getServiceDiskUsageMutex.RUnlock()
+	return len(fake.getServiceDiskUsageArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceDiskUsageCalls(stub func(context.Context, *aiven.GetServiceDiskUsageInput) (float64, error)) {
+	fake.getServiceDiskUsageMutex.Lock()
+	defer fake.getServiceDiskUsageMutex.Unlock()
+	fake.GetServiceDiskUsageStub = stub
+}
+
+func (fake *FakeClient) GetServiceDiskUsageArgsForCall(i int) (context.Context, *aiven.GetServiceDiskUsageInput) {
+	fake.getServiceDiskUsageMutex.RLock()
+	defer fake.getServiceDiskUsageMutex.RUnlock()
+	argsForCall := fake.getServiceDiskUsageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) GetServiceDiskUsageReturns(result1 float64, result2 error) {
+	fake.getServiceDiskUsageMutex.Lock()
+	defer fake.getServiceDiskUsageMutex.Unlock()
+	fake.GetServiceDiskUsageStub = nil
+	fake.getServiceDiskUsageReturns = struct {
+		result1 float64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceDiskUsageReturnsOnCall(i int, result1 float64, result2 error) {
+	fake.getServiceDiskUsageMutex.Lock()
+	defer fake.getServiceDiskUsageMutex.Unlock()
+	fake.GetServiceDiskUsageStub = nil
+	if fake.getServiceDiskUsageReturnsOnCall == nil {
+		fake.getServiceDiskUsageReturnsOnCall = make(map[int]struct {
+			result1 float64
+			result2 error
+		})
+	}
+	fake.getServiceDiskUsageReturnsOnCall[i] = struct {
+		result1 float64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServicePlans(arg1 context.Context, arg2 *aiven.GetServicePlansInput) ([]aiven.ServicePlan, error) {
+	fake.getServicePlansMutex.Lock()
+	ret, specificReturn := fake.getServicePlansReturnsOnCall[len(fake.getServicePlansArgsForCall)]
+	fake.getServicePlansArgsForCall = append(fake.getServicePlansArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.GetServicePlansInput
+	}{arg1, arg2})
+	stub := fake.GetServicePlansStub
+	fakeReturns := fake.getServicePlansReturns
+	fake.recordInvocation("GetServicePlans", []interface{}{arg1, arg2})
+	fake.getServicePlansMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetServicePlansCallCount() int {
+	fake.getServicePlansMutex.RLock()
+	defer fake.getServicePlansMutex.RUnlock()
+	return len(fake.getServicePlansArgsForCall)
+}
+
+func (fake *FakeClient) GetServicePlansCalls(stub func(context.Context, *aiven.GetServicePlansInput) ([]aiven.ServicePlan, error)) {
+	fake.getServicePlansMutex.Lock()
+	defer fake.getServicePlansMutex.Unlock()
+	fake.GetServicePlansStub = stub
+}
+
+func (fake *FakeClient) GetServicePlansArgsForCall(i int) (context.Context, *aiven.GetServicePlansInput) {
+	fake.getServicePlansMutex.RLock()
+	defer fake.getServicePlansMutex.RUnlock()
+	argsForCall := fake.getServicePlansArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) GetServicePlansReturns(result1 []aiven.ServicePlan, result2 error) {
+	fake.getServicePlansMutex.Lock()
+	defer fake.getServicePlansMutex.Unlock()
+	fake.GetServicePlansStub = nil
+	fake.getServicePlansReturns = struct {
+		result1 []aiven.ServicePlan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServicePlansReturnsOnCall(i int, result1 []aiven.ServicePlan, result2 error) {
+	fake.getServicePlansMutex.Lock()
+	defer fake.getServicePlansMutex.Unlock()
+	fake.GetServicePlansStub = nil
+	if fake.getServicePlansReturnsOnCall == nil {
+		fake.getServicePlansReturnsOnCall = make(map[int]struct {
+			result1 []aiven.ServicePlan
+			result2 error
+		})
+	}
+	fake.getServicePlansReturnsOnCall[i] = struct {
+		result1 []aiven.ServicePlan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceUser(arg1 context.Context, arg2 *aiven.GetServiceUserInput) (*aiven.User, error) {
+	fake.getServiceUserMutex.Lock()
+	ret, specificReturn := fake.getServiceUserReturnsOnCall[len(fake.getServiceUserArgsForCall)]
+	fake.getServiceUserArgsForCall = append(fake.getServiceUserArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.GetServiceUserInput
+	}{arg1, arg2})
+	stub := fake.GetServiceUserStub
+	fakeReturns := fake.getServiceUserReturns
+	fake.recordInvocation("GetServiceUser", []interface{}{arg1, arg2})
+	fake.getServiceUserMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetServiceUserCallCount() int {
+	fake.getServiceUserMutex.RLock()
+	defer fake.getServiceUserMutex.RUnlock()
+	return len(fake.getServiceUserArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceUserCalls(stub func(context.Context, *aiven.GetServiceUserInput) (*aiven.User, error)) {
+	fake.getServiceUserMutex.Lock()
+	defer fake.getServiceUserMutex.Unlock()
+	fake.GetServiceUserStub = stub
+}
+
+func (fake *FakeClient) GetServiceUserArgsForCall(i int) (context.Context, *aiven.GetServiceUserInput) {
+	fake.getServiceUserMutex.RLock()
+	defer fake.getServiceUserMutex.RUnlock()
+	argsForCall := fake.getServiceUserArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) GetServiceUserReturns(result1 *aiven.User, result2 error) {
+	fake.getServiceUserMutex.Lock()
+	defer fake.getServiceUserMutex.Unlock()
+	fake.GetServiceUserStub = nil
+	fake.getServiceUserReturns = struct {
+		result1 *aiven.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceUserReturnsOnCall(i int, result1 *aiven.User, result2 error) {
+	fake.getServiceUserMutex.Lock()
+	defer fake.getServiceUserMutex.Unlock()
+	fake.GetServiceUserStub = nil
+	if fake.getServiceUserReturnsOnCall == nil {
+		fake.getServiceUserReturnsOnCall = make(map[int]struct {
+			result1 *aiven.User
+			result2 error
+		})
+	}
+	fake.getServiceUserReturnsOnCall[i] = struct {
+		result1 *aiven.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListIntegrationEndpoints(arg1 context.Context, arg2 *aiven.ListIntegrationEndpointsInput) ([]aiven.IntegrationEndpoint, error) {
+	fake.listIntegrationEndpointsMutex.Lock()
+	ret, specificReturn := fake.listIntegrationEndpointsReturnsOnCall[len(fake.listIntegrationEndpointsArgsForCall)]
+	fake.listIntegrationEndpointsArgsForCall = append(fake.listIntegrationEndpointsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListIntegrationEndpointsInput
+	}{arg1, arg2})
+	stub := fake.ListIntegrationEndpointsStub
+	fakeReturns := fake.listIntegrationEndpointsReturns
+	fake.recordInvocation("ListIntegrationEndpoints", []interface{}{arg1, arg2})
+	fake.listIntegrationEndpointsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListIntegrationEndpointsCallCount() int {
+	fake.listIntegrationEndpointsMutex.RLock()
+	defer fake.listIntegrationEndpointsMutex.RUnlock()
+	return len(fake.listIntegrationEndpointsArgsForCall)
+}
+
+func (fake *FakeClient) ListIntegrationEndpointsCalls(stub func(context.Context, *aiven.ListIntegrationEndpointsInput) ([]aiven.IntegrationEndpoint, error)) {
+	fake.listIntegrationEndpointsMutex.Lock()
+	defer fake.listIntegrationEndpointsMutex.Unlock()
+	fake.ListIntegrationEndpointsStub = stub
+}
+
+func (fake *FakeClient) ListIntegrationEndpointsArgsForCall(i int) (context.Context, *aiven.ListIntegrationEndpointsInput) {
+	fake.listIntegrationEndpointsMutex.RLock()
+	defer fake.listIntegrationEndpointsMutex.RUnlock()
+	argsForCall := fake.listIntegrationEndpointsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListIntegrationEndpointsReturns(result1 []aiven.IntegrationEndpoint, result2 error) {
+	fake.listIntegrationEndpointsMutex.Lock()
+	defer fake.listIntegrationEndpointsMutex.Unlock()
+	fake.ListIntegrationEndpointsStub = nil
+	fake.listIntegrationEndpointsReturns = struct {
+		result1 []aiven.IntegrationEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListIntegrationEndpointsReturnsOnCall(i int, result1 []aiven.IntegrationEndpoint, result2 error) {
+	fake.listIntegrationEndpointsMutex.Lock()
+	defer fake.listIntegrationEndpointsMutex.Unlock()
+	fake.ListIntegrationEndpointsStub = nil
+	if fake.listIntegrationEndpointsReturnsOnCall == nil {
+		fake.listIntegrationEndpointsReturnsOnCall = make(map[int]struct {
+			result1 []aiven.IntegrationEndpoint
+			result2 error
+		})
+	}
+	fake.listIntegrationEndpointsReturnsOnCall[i] = struct {
+		result1 []aiven.IntegrationEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListMaintenanceUpdates(arg1 context.Context, arg2 *aiven.ListMaintenanceUpdatesInput) ([]aiven.MaintenanceUpdate, error) {
+	fake.listMaintenanceUpdatesMutex.Lock()
+	ret, specificReturn := fake.listMaintenanceUpdatesReturnsOnCall[len(fake.listMaintenanceUpdatesArgsForCall)]
+	fake.listMaintenanceUpdatesArgsForCall = append(fake.listMaintenanceUpdatesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListMaintenanceUpdatesInput
+	}{arg1, arg2})
+	stub := fake.ListMaintenanceUpdatesStub
+	fakeReturns := fake.listMaintenanceUpdatesReturns
+	fake.recordInvocation("ListMaintenanceUpdates", []interface{}{arg1, arg2})
+	fake.listMaintenanceUpdatesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListMaintenanceUpdatesCallCount() int {
+	fake.listMaintenanceUpdatesMutex.RLock()
+	defer fake.listMaintenanceUpdatesMutex.RUnlock()
+	return len(fake.listMaintenanceUpdatesArgsForCall)
+}
+
+func (fake *FakeClient) ListMaintenanceUpdatesCalls(stub func(context.Context, *aiven.ListMaintenanceUpdatesInput) ([]aiven.MaintenanceUpdate, error)) {
+	fake.listMaintenanceUpdatesMutex.Lock()
+	defer fake.listMaintenanceUpdatesMutex.Unlock()
+	fake.ListMaintenanceUpdatesStub = stub
+}
+
+func (fake *FakeClient) ListMaintenanceUpdatesArgsForCall(i int) (context.Context, *aiven.ListMaintenanceUpdatesInput) {
+	fake.listMaintenanceUpdatesMutex.RLock()
+	defer fake.listMaintenanceUpdatesMutex.RUnlock()
+	argsForCall := fake.listMaintenanceUpdatesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListMaintenanceUpdatesReturns(result1 []aiven.MaintenanceUpdate, result2 error) {
+	fake.listMaintenanceUpdatesMutex.Lock()
+	defer fake.listMaintenanceUpdatesMutex.Unlock()
+	fake.ListMaintenanceUpdatesStub = nil
+	fake.listMaintenanceUpdatesReturns = struct {
+		result1 []aiven.MaintenanceUpdate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListMaintenanceUpdatesReturnsOnCall(i int, result1 []aiven.MaintenanceUpdate, result2 error) {
+	fake.listMaintenanceUpdatesMutex.Lock()
+	defer fake.listMaintenanceUpdatesMutex.Unlock()
+	fake.ListMaintenanceUpdatesStub = nil
+	if fake.listMaintenanceUpdatesReturnsOnCall == nil {
+		fake.listMaintenanceUpdatesReturnsOnCall = make(map[int]struct {
+			result1 []aiven.MaintenanceUpdate
+			result2 error
+		})
+	}
+	fake.listMaintenanceUpdatesReturnsOnCall[i] = struct {
+		result1 []aiven.MaintenanceUpdate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListProjectVPCs(arg1 context.Context, arg2 *aiven.ListProjectVPCsInput) ([]aiven.ProjectVPC, error) {
+	fake.listProjectVPCsMutex.Lock()
+	ret, specificReturn := fake.listProjectVPCsReturnsOnCall[len(fake.listProjectVPCsArgsForCall)]
+	fake.listProjectVPCsArgsForCall = append(fake.listProjectVPCsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListProjectVPCsInput
+	}{arg1, arg2})
+	stub := fake.ListProjectVPCsStub
+	fakeReturns := fake.listProjectVPCsReturns
+	fake.recordInvocation("ListProjectVPCs", []interface{}{arg1, arg2})
+	fake.listProjectVPCsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListProjectVPCsCallCount() int {
+	fake.listProjectVPCsMutex.RLock()
+	defer fake.listProjectVPCsMutex.RUnlock()
+	return len(fake.listProjectVPCsArgsForCall)
+}
+
+func (fake *FakeClient) ListProjectVPCsCalls(stub func(context.Context, *aiven.ListProjectVPCsInput) ([]aiven.ProjectVPC, error)) {
+	fake.listProjectVPCsMutex.Lock()
+	defer fake.listProjectVPCsMutex.Unlock()
+	fake.ListProjectVPCsStub = stub
+}
+
+func (fake *FakeClient) ListProjectVPCsArgsForCall(i int) (context.Context, *aiven.ListProjectVPCsInput) {
+	fake.listProjectVPCsMutex.RLock()
+	defer fake.listProjectVPCsMutex.RUnlock()
+	argsForCall := fake.listProjectVPCsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListProjectVPCsReturns(result1 []aiven.ProjectVPC, result2 error) {
+	fake.listProjectVPCsMutex.Lock()
+	defer fake.listProjectVPCsMutex.Unlock()
+	fake.ListProjectVPCsStub = nil
+	fake.listProjectVPCsReturns = struct {
+		result1 []aiven.ProjectVPC
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListProjectVPCsReturnsOnCall(i int, result1 []aiven.ProjectVPC, result2 error) {
+	fake.listProjectVPCsMutex.Lock()
+	defer fake.listProjectVPCsMutex.Unlock()
+	fake.ListProjectVPCsStub = nil
+	if fake.listProjectVPCsReturnsOnCall == nil {
+		fake.listProjectVPCsReturnsOnCall = make(map[int]struct {
+			result1 []aiven.ProjectVPC
+			result2 error
+		})
+	}
+	fake.listProjectVPCsReturnsOnCall[i] = struct {
+		result1 []aiven.ProjectVPC
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListServiceBackups(arg1 context.Context, arg2 *aiven.ListServiceBackupsInput) ([]aiven.ServiceBackup, error) {
+	fake.listServiceBackupsMutex.Lock()
+	ret, specificReturn := fake.listServiceBackupsReturnsOnCall[len(fake.listServiceBackupsArgsForCall)]
+	fake.listServiceBackupsArgsForCall = append(fake.listServiceBackupsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListServiceBackupsInput
+	}{arg1, arg2})
+	stub := fake.ListServiceBackupsStub
+	fakeReturns := fake.listServiceBackupsReturns
+	fake.recordInvocation("ListServiceBackups", []interface{}{arg1, arg2})
+	fake.listServiceBackupsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListServiceBackupsCallCount() int {
+	fake.listServiceBackupsMutex.RLock()
+	defer fake.listServiceBackupsMutex.RUnlock()
+	return len(fake.listServiceBackupsArgsForCall)
+}
+
+func (fake *FakeClient) ListServiceBackupsCalls(stub func(context.Context, *aiven.ListServiceBackupsInput) ([]aiven.ServiceBackup, error)) {
+	fake.listServiceBackupsMutex.Lock()
+	defer fake.listServiceBackupsMutex.Unlock()
+	fake.ListServiceBackupsStub = stub
+}
+
+func (fake *FakeClient) ListServiceBackupsArgsForCall(i int) (context.Context, *aiven.ListServiceBackupsInput) {
+	fake.listServiceBackupsMutex.RLock()
+	defer fake.listServiceBackupsMutex.RUnlock()
+	argsForCall := fake.listServiceBackupsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListServiceBackupsReturns(result1 []aiven.ServiceBackup, result2 error) {
+	fake.listServiceBackupsMutex.Lock()
+	defer fake.listServiceBackupsMutex.Unlock()
+	fake.ListServiceBackupsStub = nil
+	fake.listServiceBackupsReturns = struct {
+		result1 []aiven.ServiceBackup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListServiceBackupsReturnsOnCall(i int, result1 []aiven.ServiceBackup, result2 error) {
+	fake.listServiceBackupsMutex.Lock()
+	defer fake.listServiceBackupsMutex.Unlock()
+	fake.ListServiceBackupsStub = nil
+	if fake.listServiceBackupsReturnsOnCall == nil {
+		fake.listServiceBackupsReturnsOnCall = make(map[int]struct {
+			result1 []aiven.ServiceBackup
+			result2 error
+		})
+	}
+	fake.listServiceBackupsReturnsOnCall[i] = struct {
+		result1 []aiven.ServiceBackup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListServiceIntegrations(arg1 context.Context, arg2 *aiven.ListServiceIntegrationsInput) ([]aiven.ServiceIntegration, error) {
+	fake.listServiceIntegrationsMutex.Lock()
+	ret, specificReturn := fake.listServiceIntegrationsReturnsOnCall[len(fake.listServiceIntegrationsArgsForCall)]
+	fake.listServiceIntegrationsArgsForCall = append(fake.listServiceIntegrationsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListServiceIntegrationsInput
+	}{arg1, arg2})
+	stub := fake.ListServiceIntegrationsStub
+	fakeReturns := fake.listServiceIntegrationsReturns
+	fake.recordInvocation("ListServiceIntegrations", []interface{}{arg1, arg2})
+	fake.listServiceIntegrationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListServiceIntegrationsCallCount() int {
+	fake.listServiceIntegrationsMutex.RLock()
+	defer fake.listServiceIntegrationsMutex.RUnlock()
+	return len(fake.listServiceIntegrationsArgsForCall)
+}
+
+func (fake *FakeClient) ListServiceIntegrationsCalls(stub func(context.Context, *aiven.ListServiceIntegrationsInput) ([]aiven.ServiceIntegration, error)) {
+	fake.listServiceIntegrationsMutex.Lock()
+	defer fake.listServiceIntegrationsMutex.Unlock()
+	fake.ListServiceIntegrationsStub = stub
+}
+
+func (fake *FakeClient) ListServiceIntegrationsArgsForCall(i int) (context.Context, *aiven.ListServiceIntegrationsInput) {
+	fake.listServiceIntegrationsMutex.RLock()
+	defer fake.listServiceIntegrationsMutex.RUnlock()
+	argsForCall := fake.listServiceIntegrationsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListServiceIntegrationsReturns(result1 []aiven.ServiceIntegration, result2 error) {
+	fake.listServiceIntegrationsMutex.Lock()
+	defer fake.listServiceIntegrationsMutex.Unlock()
+	fake.ListServiceIntegrationsStub = nil
+	fake.listServiceIntegrationsReturns = struct {
+		result1 []aiven.ServiceIntegration
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListServiceIntegrationsReturnsOnCall(i int, result1 []aiven.ServiceIntegration, result2 error) {
+	fake.listServiceIntegrationsMutex.Lock()
+	defer fake.listServiceIntegrationsMutex.Unlock()
+	fake.ListServiceIntegrationsStub = nil
+	if fake.listServiceIntegrationsReturnsOnCall == nil {
+		fake.listServiceIntegrationsReturnsOnCall = make(map[int]struct {
+			result1 []aiven.ServiceIntegration
+			result2 error
+		})
+	}
+	fake.listServiceIntegrationsReturnsOnCall[i] = struct {
+		result1 []aiven.ServiceIntegration
 		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListServiceUsers(arg1 context.Context, arg2 *aiven.ListServiceUsersInput) ([]aiven.User, error) {
+	fake.listServiceUsersMutex.Lock()
+	ret, specificReturn := fake.listServiceUsersReturnsOnCall[len(fake.listServiceUsersArgsForCall)]
+	fake.listServiceUsersArgsForCall = append(fake.listServiceUsersArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListServiceUsersInput
+	}{arg1, arg2})
+	stub := fake.ListServiceUsersStub
+	fakeReturns := fake.listServiceUsersReturns
+	fake.recordInvocation("ListServiceUsers", []interface{}{arg1, arg2})
+	fake.listServiceUsersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
-	UpdateServiceStub        func(*aiven.UpdateServiceInput) (string, error)
-	updateServiceMutex       sync.RWMutex
-	updateServiceArgsForCall []struct {
-		arg1 *aiven.UpdateServiceInput
+	if specificReturn {
+		return ret.result1, ret.result2
 	}
-	updateServiceReturns struct {
-		result1 string
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListServiceUsersCallCount() int {
+	fake.listServiceUsersMutex.RLock()
+	defer fake.listServiceUsersMutex.RUnlock()
+	return len(fake.listServiceUsersArgsForCall)
+}
+
+func (fake *FakeClient) ListServiceUsersCalls(stub func(context.Context, *aiven.ListServiceUsersInput) ([]aiven.User, error)) {
+	fake.listServiceUsersMutex.Lock()
+	defer fake.listServiceUsersMutex.Unlock()
+	fake.ListServiceUsersStub = stub
+}
+
+func (fake *FakeClient) ListServiceUsersArgsForCall(i int) (context.Context, *aiven.ListServiceUsersInput) {
+	fake.listServiceUsersMutex.RLock()
+	defer fake.listServiceUsersMutex.RUnlock()
+	argsForCall := fake.listServiceUsersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListServiceUsersReturns(result1 []aiven.User, result2 error) {
+	fake.listServiceUsersMutex.Lock()
+	defer fake.listServiceUsersMutex.Unlock()
+	fake.ListServiceUsersStub = nil
+	fake.listServiceUsersReturns = struct {
+		result1 []aiven.User
 		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListServiceUsersReturnsOnCall(i int, result1 []aiven.User, result2 error) {
+	fake.listServiceUsersMutex.Lock()
+	defer fake.listServiceUsersMutex.Unlock()
+	fake.ListServiceUsersStub = nil
+	if fake.listServiceUsersReturnsOnCall == nil {
+		fake.listServiceUsersReturnsOnCall = make(map[int]struct {
+			result1 []aiven.User
+			result2 error
+		})
 	}
-	updateServiceReturnsOnCall map[int]struct {
-		result1 string
+	fake.listServiceUsersReturnsOnCall[i] = struct {
+		result1 []aiven.User
 		result2 error
-	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
+	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateService(arg1 *aiven.CreateServiceInput) (string, error) {
-	fake.createServiceMutex.Lock()
-	ret, specificReturn := fake.createServiceReturnsOnCall[len(fake.createServiceArgsForCall)]
-	fake.createServiceArgsForCall = append(fake.createServiceArgsForCall, struct {
-		arg1 *aiven.CreateServiceInput
-	}{arg1})
-	fake.recordInvocation("CreateService", []interface{}{arg1})
-	fake.createServiceMutex.Unlock()
-	if fake.CreateServiceStub != nil {
-		return fake.CreateServiceStub(arg1)
+func (fake *FakeClient) ListServices(arg1 context.Context, arg2 *aiven.ListServicesInput) ([]aiven.Service, error) {
+	fake.listServicesMutex.Lock()
+	ret, specificReturn := fake.listServicesReturnsOnCall[len(fake.listServicesArgsForCall)]
+	fake.listServicesArgsForCall = append(fake.listServicesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListServicesInput
+	}{arg1, arg2})
+	stub := fake.ListServicesStub
+	fakeReturns := fake.listServicesReturns
+	fake.recordInvocation("ListServices", []interface{}{arg1, arg2})
+	fake.listServicesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.createServiceReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) CreateServiceCallCount() int {
-	fake.createServiceMutex.RLock()
-	defer fake.createServiceMutex.RUnlock()
-	return len(fake.createServiceArgsForCall)
+func (fake *FakeClient) ListServicesCallCount() int {
+	fake.listServicesMutex.RLock()
+	defer fake.listServicesMutex.RUnlock()
+	return len(fake.listServicesArgsForCall)
 }
 
-func (fake *FakeClient) CreateServiceCalls(stub func(*aiven.CreateServiceInput) (string, error)) {
-	fake.createServiceMutex.Lock()
-	defer fake.createServiceMutex.Unlock()
-	fake.CreateServiceStub = stub
+func (fake *FakeClient) ListServicesCalls(stub func(context.Context, *aiven.ListServicesInput) ([]aiven.Service, error)) {
+	fake.listServicesMutex.Lock()
+	defer fake.listServicesMutex.Unlock()
+	fake.ListServicesStub = stub
 }
 
-func (fake *FakeClient) CreateServiceArgsForCall(i int) *aiven.CreateServiceInput {
-	fake.createServiceMutex.RLock()
-	defer fake.createServiceMutex.RUnlock()
-	argsForCall := fake.createServiceArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) ListServicesArgsForCall(i int) (context.Context, *aiven.ListServicesInput) {
+	fake.listServicesMutex.RLock()
+	defer fake.listServicesMutex.RUnlock()
+	argsForCall := fake.listServicesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeClient) CreateServiceReturns(result1 string, result2 error) {
-	fake.createServiceMutex.Lock()
-	defer fake.createServiceMutex.Unlock()
-	fake.CreateServiceStub = nil
-	fake.createServiceReturns = struct {
-		result1 string
+func (fake *FakeClient) ListServicesReturns(result1 []aiven.Service, result2 error) {
+	fake.listServicesMutex.Lock()
+	defer fake.listServicesMutex.Unlock()
+	fake.ListServicesStub = nil
+	fake.listServicesReturns = struct {
+		result1 []aiven.Service
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateServiceReturnsOnCall(i int, result1 string, result2 error) {
-	fake.createServiceMutex.Lock()
-	defer fake.createServiceMutex.Unlock()
-	fake.CreateServiceStub = nil
-	if fake.createServiceReturnsOnCall == nil {
-		fake.createServiceReturnsOnCall = make(map[int]struct {
-			result1 string
+func (fake *FakeClient) ListServicesReturnsOnCall(i int, result1 []aiven.Service, result2 error) {
+	fake.listServicesMutex.Lock()
+	defer fake.listServicesMutex.Unlock()
+	fake.ListServicesStub = nil
+	if fake.listServicesReturnsOnCall == nil {
+		fake.listServicesReturnsOnCall = make(map[int]struct {
+			result1 []aiven.Service
 			result2 error
 		})
 	}
-	fake.createServiceReturnsOnCall[i] = struct {
-		result1 string
+	fake.listServicesReturnsOnCall[i] = struct {
+		result1 []aiven.Service
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateServiceUser(arg1 *aiven.CreateServiceUserInput) (string, error) {
-	fake.createServiceUserMutex.Lock()
-	ret, specificReturn := fake.createServiceUserReturnsOnCall[len(fake.createServiceUserArgsForCall)]
-	fake.createServiceUserArgsForCall = append(fake.createServiceUserArgsForCall, struct {
-		arg1 *aiven.CreateServiceUserInput
-	}{arg1})
-	fake.recordInvocation("CreateServiceUser", []interface{}{arg1})
-	fake.createServiceUserMutex.Unlock()
-	if fake.CreateServiceUserStub != nil {
-		return fake.CreateServiceUserStub(arg1)
+func (fake *FakeClient) ListStaticIPs(arg1 context.Context, arg2 *aiven.ListStaticIPsInput) ([]aiven.StaticIP, error) {
+	fake.listStaticIPsMutex.Lock()
+	ret, specificReturn := fake.listStaticIPsReturnsOnCall[len(fake.listStaticIPsArgsForCall)]
+	fake.listStaticIPsArgsForCall = append(fake.listStaticIPsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ListStaticIPsInput
+	}{arg1, arg2})
+	stub := fake.ListStaticIPsStub
+	fakeReturns := fake.listStaticIPsReturns
+	fake.recordInvocation("ListStaticIPs", []interface{}{arg1, arg2})
+	fake.listStaticIPsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.createServiceUserReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) CreateServiceUserCallCount() int {
-	fake.createServiceUserMutex.RLock()
-	defer fake.createServiceUserMutex.RUnlock()
-	return len(fake.createServiceUserArgsForCall)
+func (fake *FakeClient) ListStaticIPsCallCount() int {
+	fake.listStaticIPsMutex.RLock()
+	defer fake.listStaticIPsMutex.RUnlock()
+	return len(fake.listStaticIPsArgsForCall)
 }
 
-func (fake *FakeClient) CreateServiceUserCalls(stub func(*aiven.CreateServiceUserInput) (string, error)) {
-	fake.createServiceUserMutex.Lock()
-	defer fake.createServiceUserMutex.Unlock()
-	fake.CreateServiceUserStub = stub
+func (fake *FakeClient) ListStaticIPsCalls(stub func(context.Context, *aiven.ListStaticIPsInput) ([]aiven.StaticIP, error)) {
+	fake.listStaticIPsMutex.Lock()
+	defer fake.listStaticIPsMutex.Unlock()
+	fake.ListStaticIPsStub = stub
 }
 
-func (fake *FakeClient) CreateServiceUserArgsForCall(i int) *aiven.CreateServiceUserInput {
-	fake.createServiceUserMutex.RLock()
-	defer fake.createServiceUserMutex.RUnlock()
-	argsForCall := fake.createServiceUserArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) ListStaticIPsArgsForCall(i int) (context.Context, *aiven.ListStaticIPsInput) {
+	fake.listStaticIPsMutex.RLock()
+	defer fake.listStaticIPsMutex.RUnlock()
+	argsForCall := fake.listStaticIPsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeClient) CreateServiceUserReturns(result1 string, result2 error) {
-	fake.createServiceUserMutex.Lock()
-	defer fake.createServiceUserMutex.Unlock()
-	fake.CreateServiceUserStub = nil
-	fake.createServiceUserReturns = struct {
-		result1 string
+func (fake *FakeClient) ListStaticIPsReturns(result1 []aiven.StaticIP, result2 error) {
+	fake.listStaticIPsMutex.Lock()
+	defer fake.listStaticIPsMutex.Unlock()
+	fake.ListStaticIPsStub = nil
+	fake.listStaticIPsReturns = struct {
+		result1 []aiven.StaticIP
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateServiceUserReturnsOnCall(i int, result1 string, result2 error) {
-	fake.createServiceUserMutex.Lock()
-	defer fake.createServiceUserMutex.Unlock()
-	fake.CreateServiceUserStub = nil
-	if fake.createServiceUserReturnsOnCall == nil {
-		fake.createServiceUserReturnsOnCall = make(map[int]struct {
-			result1 string
+func (fake *FakeClient) ListStaticIPsReturnsOnCall(i int, result1 []aiven.StaticIP, result2 error) {
+	fake.listStaticIPsMutex.Lock()
+	defer fake.listStaticIPsMutex.Unlock()
+	fake.ListStaticIPsStub = nil
+	if fake.listStaticIPsReturnsOnCall == nil {
+		fake.listStaticIPsReturnsOnCall = make(map[int]struct {
+			result1 []aiven.StaticIP
 			result2 error
 		})
 	}
-	fake.createServiceUserReturnsOnCall[i] = struct {
-		result1 string
+	fake.listStaticIPsReturnsOnCall[i] = struct {
+		result1 []aiven.StaticIP
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) DeleteService(arg1 *aiven.DeleteServiceInput) error {
-	fake.deleteServiceMutex.Lock()
-	ret, specificReturn := fake.deleteServiceReturnsOnCall[len(fake.deleteServiceArgsForCall)]
-	fake.deleteServiceArgsForCall = append(fake.deleteServiceArgsForCall, struct {
-		arg1 *aiven.DeleteServiceInput
-	}{arg1})
-	fake.recordInvocation("DeleteService", []interface{}{arg1})
-	fake.deleteServiceMutex.Unlock()
-	if fake.DeleteServiceStub != nil {
-		return fake.DeleteServiceStub(arg1)
+func (fake *FakeClient) PowerService(arg1 context.Context, arg2 *aiven.PowerServiceInput) error {
+	fake.powerServiceMutex.Lock()
+	ret, specificReturn := fake.powerServiceReturnsOnCall[len(fake.powerServiceArgsForCall)]
+	fake.powerServiceArgsForCall = append(fake.powerServiceArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.PowerServiceInput
+	}{arg1, arg2})
+	stub := fake.PowerServiceStub
+	fakeReturns := fake.powerServiceReturns
+	fake.recordInvocation("PowerService", []interface{}{arg1, arg2})
+	fake.powerServiceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.deleteServiceReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeClient) DeleteServiceCallCount() int {
-	fake.deleteServiceMutex.RLock()
-	defer fake.deleteServiceMutex.RUnlock()
-	return len(fake.deleteServiceArgsForCall)
+func (fake *FakeClient) PowerServiceCallCount() int {
+	fake.powerServiceMutex.RLock()
+	defer fake.powerServiceMutex.RUnlock()
+	return len(fake.powerServiceArgsForCall)
 }
 
-func (fake *FakeClient) DeleteServiceCalls(stub func(*aiven.DeleteServiceInput) error) {
-	fake.deleteServiceMutex.Lock()
-	defer fake.deleteServiceMutex.Unlock()
-	fake.DeleteServiceStub = stub
+func (fake *FakeClient) PowerServiceCalls(stub func(context.Context, *aiven.PowerServiceInput) error) {
+	fake.powerServiceMutex.Lock()
+	defer fake.powerServiceMutex.Unlock()
+	fake.PowerServiceStub = stub
 }
 
-func (fake *FakeClient) DeleteServiceArgsForCall(i int) *aiven.DeleteServiceInput {
-	fake.deleteServiceMutex.RLock()
-	defer fake.deleteServiceMutex.RUnlock()
-	argsForCall := fake.deleteServiceArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) PowerServiceArgsForCall(i int) (context.Context, *aiven.PowerServiceInput) {
+	fake.powerServiceMutex.RLock()
+	defer fake.powerServiceMutex.RUnlock()
+	argsForCall := fake.powerServiceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeClient) DeleteServiceReturns(result1 error) {
-	fake.deleteServiceMutex.Lock()
-	defer fake.deleteServiceMutex.Unlock()
-	fake.DeleteServiceStub = nil
-	fake.deleteServiceReturns = struct {
+func (fake *FakeClient) PowerServiceReturns(result1 error) {
+	fake.powerServiceMutex.Lock()
+	defer fake.powerServiceMutex.Unlock()
+	fake.PowerServiceStub = nil
+	fake.powerServiceReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) DeleteServiceReturnsOnCall(i int, result1 error) {
-	fake.deleteServiceMutex.Lock()
-	defer fake.deleteServiceMutex.Unlock()
-	fake.DeleteServiceStub = nil
-	if fake.deleteServiceReturnsOnCall == nil {
-		fake.deleteServiceReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) PowerServiceReturnsOnCall(i int, result1 error) {
+	fake.powerServiceMutex.Lock()
+	defer fake.powerServiceMutex.Unlock()
+	fake.PowerServiceStub = nil
+	if fake.powerServiceReturnsOnCall == nil {
+		fake.powerServiceReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deleteServiceReturnsOnCall[i] = struct {
+	fake.powerServiceReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) DeleteServiceUser(arg1 *aiven.DeleteServiceUserInput) (string, error) {
-	fake.deleteServiceUserMutex.Lock()
-	ret, specificReturn := fake.deleteServiceUserReturnsOnCall[len(fake.deleteServiceUserArgsForCall)]
-	fake.deleteServiceUserArgsForCall = append(fake.deleteServiceUserArgsForCall, struct {
-		arg1 *aiven.DeleteServiceUserInput
-	}{arg1})
-	fake.recordInvocation("DeleteServiceUser", []interface{}{arg1})
-	fake.deleteServiceUserMutex.Unlock()
-	if fake.DeleteServiceUserStub != nil {
-		return fake.DeleteServiceUserStub(arg1)
+func (fake *FakeClient) ResetServiceUserCredentials(arg1 context.Context, arg2 *aiven.ResetServiceUserCredentialsInput) (string, error) {
+	fake.resetServiceUserCredentialsMutex.Lock()
+	ret, specificReturn := fake.resetServiceUserCredentialsReturnsOnCall[len(fake.resetServiceUserCredentialsArgsForCall)]
+	fake.resetServiceUserCredentialsArgsForCall = append(fake.resetServiceUserCredentialsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.ResetServiceUserCredentialsInput
+	}{arg1, arg2})
+	stub := fake.ResetServiceUserCredentialsStub
+	fakeReturns := fake.resetServiceUserCredentialsReturns
+	fake.recordInvocation("ResetServiceUserCredentials", []interface{}{arg1, arg2})
+	fake.resetServiceUserCredentialsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.deleteServiceUserReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) DeleteServiceUserCallCount() int {
-	fake.deleteServiceUserMutex.RLock()
-	defer fake.deleteServiceUserMutex.RUnlock()
-	return len(fake.deleteServiceUserArgsForCall)
+func (fake *FakeClient) ResetServiceUserCredentialsCallCount() int {
+	fake.resetServiceUserCredentialsMutex.RLock()
+	defer fake.resetServiceUserCredentialsMutex.RUnlock()
+	return len(fake.resetServiceUserCredentialsArgsForCall)
 }
 
-func (fake *FakeClient) DeleteServiceUserCalls(stub func(*aiven.DeleteServiceUserInput) (string, error)) {
-	fake.deleteServiceUserMutex.Lock()
-	defer fake.deleteServiceUserMutex.Unlock()
-	fake.DeleteServiceUserStub = stub
+func (fake *FakeClient) ResetServiceUserCredentialsCalls(stub func(context.Context, *aiven.ResetServiceUserCredentialsInput) (string, error)) {
+	fake.resetServiceUserCredentialsMutex.Lock()
+	defer fake.resetServiceUserCredentialsMutex.Unlock()
+	fake.ResetServiceUserCredentialsStub = stub
 }
 
-func (fake *FakeClient) DeleteServiceUserArgsForCall(i int) *aiven.DeleteServiceUserInput {
-	fake.deleteServiceUserMutex.RLock()
-	defer fake.deleteServiceUserMutex.RUnlock()
-	argsForCall := fake.deleteServiceUserArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) ResetServiceUserCredentialsArgsForCall(i int) (context.Context, *aiven.ResetServiceUserCredentialsInput) {
+	fake.resetServiceUserCredentialsMutex.RLock()
+	defer fake.resetServiceUserCredentialsMutex.RUnlock()
+	argsForCall := fake.resetServiceUserCredentialsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeClient) DeleteServiceUserReturns(result1 string, result2 error) {
-	fake.deleteServiceUserMutex.Lock()
-	defer fake.deleteServiceUserMutex.Unlock()
-	fake.DeleteServiceUserStub = nil
-	fake.deleteServiceUserReturns = struct {
+func (fake *FakeClient) ResetServiceUserCredentialsReturns(result1 string, result2 error) {
+	fake.resetServiceUserCredentialsMutex.Lock()
+	defer fake.resetServiceUserCredentialsMutex.Unlock()
+	fake.ResetServiceUserCredentialsStub = nil
+	fake.resetServiceUserCredentialsReturns = struct {
 		result1 string
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) DeleteServiceUserReturnsOnCall(i int, result1 string, result2 error) {
-	fake.deleteServiceUserMutex.Lock()
-	defer fake.deleteServiceUserMutex.Unlock()
-	fake.DeleteServiceUserStub = nil
-	if fake.deleteServiceUserReturnsOnCall == nil {
-		fake.deleteServiceUserReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) ResetServiceUserCredentialsReturnsOnCall(i int, result1 string, result2 error) {
+	fake.resetServiceUserCredentialsMutex.Lock()
+	defer fake.resetServiceUserCredentialsMutex.Unlock()
+	fake.ResetServiceUserCredentialsStub = nil
+	if fake.resetServiceUserCredentialsReturnsOnCall == nil {
+		fake.resetServiceUserCredentialsReturnsOnCall = make(map[int]struct {
 			result1 string
 			result2 error
 		})
 	}
-	fake.deleteServiceUserReturnsOnCall[i] = struct {
+	fake.resetServiceUserCredentialsReturnsOnCall[i] = struct {
 		result1 string
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) GetService(arg1 *aiven.GetServiceInput) (*aiven.Service, error) {
-	fake.getServiceMutex.Lock()
-	ret, specificReturn := fake.getServiceReturnsOnCall[len(fake.getServiceArgsForCall)]
-	fake.getServiceArgsForCall = append(fake.getServiceArgsForCall, struct {
-		arg1 *aiven.GetServiceInput
-	}{arg1})
-	fake.recordInvocation("GetService", []interface{}{arg1})
-	fake.getServiceMutex.Unlock()
-	if fake.GetServiceStub != nil {
-		return fake.GetServiceStub(arg1)
+func (fake *FakeClient) StartMaintenance(arg1 context.Context, arg2 *aiven.StartMaintenanceInput) error {
+	fake.startMaintenanceMutex.Lock()
+	ret, specificReturn := fake.startMaintenanceReturnsOnCall[len(fake.startMaintenanceArgsForCall)]
+	fake.startMaintenanceArgsForCall = append(fake.startMaintenanceArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.StartMaintenanceInput
+	}{arg1, arg2})
+	stub := fake.StartMaintenanceStub
+	fakeReturns := fake.startMaintenanceReturns
+	fake.recordInvocation("StartMaintenance", []interface{}{arg1, arg2})
+	fake.startMaintenanceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.getServiceReturns
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) GetServiceCallCount() int {
-	fake.getServiceMutex.RLock()
-	defer fake.getServiceMutex.RUnlock()
-	return len(fake.getServiceArgsForCall)
+func (fake *FakeClient) StartMaintenanceCallCount() int {
+	fake.startMaintenanceMutex.RLock()
+	defer fake.startMaintenanceMutex.RUnlock()
+	return len(fake.startMaintenanceArgsForCall)
 }
 
-func (fake *FakeClient) GetServiceCalls(stub func(*aiven.GetServiceInput) (*aiven.Service, error)) {
-	fake.getServiceMutex.Lock()
-	defer fake.getServiceMutex.Unlock()
-	fake.GetServiceStub = stub
+func (fake *FakeClient) StartMaintenanceCalls(stub func(context.Context, *aiven.StartMaintenanceInput) error) {
+	fake.startMaintenanceMutex.Lock()
+	defer fake.startMaintenanceMutex.Unlock()
+	fake.StartMaintenanceStub = stub
 }
 
-func (fake *FakeClient) GetServiceArgsForCall(i int) *aiven.GetServiceInput {
-	fake.getServiceMutex.RLock()
-	defer fake.getServiceMutex.RUnlock()
-	argsForCall := fake.getServiceArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) StartMaintenanceArgsForCall(i int) (context.Context, *aiven.StartMaintenanceInput) {
+	fake.startMaintenanceMutex.RLock()
+	defer fake.startMaintenanceMutex.RUnlock()
+	argsForCall := fake.startMaintenanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeClient) GetServiceReturns(result1 *aiven.Service, result2 error) {
-	fake.getServiceMutex.Lock()
-	defer fake.getServiceMutex.Unlock()
-	fake.GetServiceStub = nil
-	fake.getServiceReturns = struct {
-		result1 *aiven.Service
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) StartMaintenanceReturns(result1 error) {
+	fake.startMaintenanceMutex.Lock()
+	defer fake.startMaintenanceMutex.Unlock()
+	fake.StartMaintenanceStub = nil
+	fake.startMaintenanceReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) GetServiceReturnsOnCall(i int, result1 *aiven.Service, result2 error) {
-	fake.getServiceMutex.Lock()
-	defer fake.getServiceMutex.Unlock()
-	fake.GetServiceStub = nil
-	if fake.getServiceReturnsOnCall == nil {
-		fake.getServiceReturnsOnCall = make(map[int]struct {
-			result1 *aiven.Service
-			result2 error
+func (fake *FakeClient) StartMaintenanceReturnsOnCall(i int, result1 error) {
+	fake.startMaintenanceMutex.Lock()
+	defer fake.startMaintenanceMutex.Unlock()
+	fake.StartMaintenanceStub = nil
+	if fake.startMaintenanceReturnsOnCall == nil {
+		fake.startMaintenanceReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.getServiceReturnsOnCall[i] = struct {
-		result1 *aiven.Service
-		result2 error
-	}{result1, result2}
+	fake.startMaintenanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpdateACLConfig(arg1 context.Context, arg2 *aiven.UpdateACLConfigInput) error {
+	fake.updateACLConfigMutex.Lock()
+	ret, specificReturn := fake.updateACLConfigReturnsOnCall[len(fake.updateACLConfigArgsForCall)]
+	fake.updateACLConfigArgsForCall = append(fake.updateACLConfigArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.UpdateACLConfigInput
+	}{arg1, arg2})
+	stub := fake.UpdateACLConfigStub
+	fakeReturns := fake.updateACLConfigReturns
+	fake.recordInvocation("UpdateACLConfig", []interface{}{arg1, arg2})
+	fake.updateACLConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) UpdateACLConfigCallCount() int {
+	fake.updateACLConfigMutex.RLock()
+	defer fake.updateACLConfigMutex.RUnlock()
+	return len(fake.updateACLConfigArgsForCall)
+}
+
+func (fake *FakeClient) UpdateACLConfigCalls(stub func(context.Context, *aiven.UpdateACLConfigInput) error) {
+	fake.updateACLConfigMutex.Lock()
+	defer fake.updateACLConfigMutex.Unlock()
+	fake.UpdateACLConfigStub = stub
+}
+
+func (fake *FakeClient) UpdateACLConfigArgsForCall(i int) (context.Context, *aiven.UpdateACLConfigInput) {
+	fake.updateACLConfigMutex.RLock()
+	defer fake.updateACLConfigMutex.RUnlock()
+	argsForCall := fake.updateACLConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) UpdateACLConfigReturns(result1 error) {
+	fake.updateACLConfigMutex.Lock()
+	defer fake.updateACLConfigMutex.Unlock()
+	fake.UpdateACLConfigStub = nil
+	fake.updateACLConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpdateACLConfigReturnsOnCall(i int, result1 error) {
+	fake.updateACLConfigMutex.Lock()
+	defer fake.updateACLConfigMutex.Unlock()
+	fake.UpdateACLConfigStub = nil
+	if fake.updateACLConfigReturnsOnCall == nil {
+		fake.updateACLConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateACLConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) UpdateService(arg1 *aiven.UpdateServiceInput) (string, error) {
+func (fake *FakeClient) UpdateService(arg1 context.Context, arg2 *aiven.UpdateServiceInput) (string, error) {
 	fake.updateServiceMutex.Lock()
 	ret, specificReturn := fake.updateServiceReturnsOnCall[len(fake.updateServiceArgsForCall)]
 	fake.updateServiceArgsForCall = append(fake.updateServiceArgsForCall, struct {
-		arg1 *aiven.UpdateServiceInput
-	}{arg1})
-	fake.recordInvocation("UpdateService", []interface{}{arg1})
+		arg1 context.Context
+		arg2 *aiven.UpdateServiceInput
+	}{arg1, arg2})
+	stub := fake.UpdateServiceStub
+	fakeReturns := fake.updateServiceReturns
+	fake.recordInvocation("UpdateService", []interface{}{arg1, arg2})
 	fake.updateServiceMutex.Unlock()
-	if fake.UpdateServiceStub != nil {
-		return fake.UpdateServiceStub(arg1)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.updateServiceReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -424,17 +2612,17 @@ func (fake *FakeClient) UpdateServiceCallCount() int {
 	return len(fake.updateServiceArgsForCall)
 }
 
-func (fake *FakeClient) UpdateServiceCalls(stub func(*aiven.UpdateServiceInput) (string, error)) {
+func (fake *FakeClient) UpdateServiceCalls(stub func(context.Context, *aiven.UpdateServiceInput) (string, error)) {
 	fake.updateServiceMutex.Lock()
 	defer fake.updateServiceMutex.Unlock()
 	fake.UpdateServiceStub = stub
 }
 
-func (fake *FakeClient) UpdateServiceArgsForCall(i int) *aiven.UpdateServiceInput {
+func (fake *FakeClient) UpdateServiceArgsForCall(i int) (context.Context, *aiven.UpdateServiceInput) {
 	fake.updateServiceMutex.RLock()
 	defer fake.updateServiceMutex.RUnlock()
 	argsForCall := fake.updateServiceArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeClient) UpdateServiceReturns(result1 string, result2 error) {
@@ -463,21 +2651,71 @@ func (fake *FakeClient) UpdateServiceReturnsOnCall(i int, result1 string, result
 	}{result1, result2}
 }
 
+func (fake *FakeClient) UpdateServiceTags(arg1 context.Context, arg2 *aiven.UpdateServiceTagsInput) error {
+	fake.updateServiceTagsMutex.Lock()
+	ret, specificReturn := fake.updateServiceTagsReturnsOnCall[len(fake.updateServiceTagsArgsForCall)]
+	fake.updateServiceTagsArgsForCall = append(fake.updateServiceTagsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *aiven.UpdateServiceTagsInput
+	}{arg1, arg2})
+	stub := fake.UpdateServiceTagsStub
+	fakeReturns := fake.updateServiceTagsReturns
+	fake.recordInvocation("UpdateServiceTags", []interface{}{arg1, arg2})
+	fake.updateServiceTagsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) UpdateServiceTagsCallCount() int {
+	fake.updateServiceTagsMutex.RLock()
+	defer fake.updateServiceTagsMutex.RUnlock()
+	return len(fake.updateServiceTagsArgsForCall)
+}
+
+func (fake *FakeClient) UpdateServiceTagsCalls(stub func(context.Context, *aiven.UpdateServiceTagsInput) error) {
+	fake.updateServiceTagsMutex.Lock()
+	defer fake.updateServiceTagsMutex.Unlock()
+	fake.UpdateServiceTagsStub = stub
+}
+
+func (fake *FakeClient) UpdateServiceTagsArgsForCall(i int) (context.Context, *aiven.UpdateServiceTagsInput) {
+	fake.updateServiceTagsMutex.RLock()
+	defer fake.updateServiceTagsMutex.RUnlock()
+	argsForCall := fake.updateServiceTagsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) UpdateServiceTagsReturns(result1 error) {
+	fake.updateServiceTagsMutex.Lock()
+	defer fake.updateServiceTagsMutex.Unlock()
+	fake.UpdateServiceTagsStub = nil
+	fake.updateServiceTagsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpdateServiceTagsReturnsOnCall(i int, result1 error) {
+	fake.updateServiceTagsMutex.Lock()
+	defer fake.updateServiceTagsMutex.Unlock()
+	fake.UpdateServiceTagsStub = nil
+	if fake.updateServiceTagsReturnsOnCall == nil {
+		fake.updateServiceTagsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateServiceTagsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.createServiceMutex.RLock()
-	defer fake.createServiceMutex.RUnlock()
-	fake.createServiceUserMutex.RLock()
-	defer fake.createServiceUserMutex.RUnlock()
-	fake.deleteServiceMutex.RLock()
-	defer fake.deleteServiceMutex.RUnlock()
-	fake.deleteServiceUserMutex.RLock()
-	defer fake.deleteServiceUserMutex.RUnlock()
-	fake.getServiceMutex.RLock()
-	defer fake.getServiceMutex.RUnlock()
-	fake.updateServiceMutex.RLock()
-	defer fake.updateServiceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
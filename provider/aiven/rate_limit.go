@@ -0,0 +1,125 @@
+package aiven
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of a RateLimiter, which tests can fake.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock is the real time.
+var SystemClock Clock = systemClock{}
+
+// Request priorities. Changes are let through before polling, so that a
+// backlog of polls does not hold up the changes they are waiting for.
+const (
+	priorityMutating = iota
+	priorityPolling
+	priorities
+)
+
+// RateLimiter paces requests to Aiven to stay under its per-token rate
+// limits, with a token bucket which lets through bursts of up to burst
+// requests, refilling at requestsPerSecond.
+type RateLimiter struct {
+	requestsPerSecond float64
+	burst             float64
+	clock             Clock
+
+	lock    sync.Mutex
+	tokens  float64
+	updated time.Time
+	// queues hold the requests waiting for a token, by priority.
+	queues    [priorities][]chan struct{}
+	scheduled bool
+}
+
+func NewRateLimiter(requestsPerSecond float64, burst int, clock Clock) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             float64(burst),
+		clock:             clock,
+		tokens:            float64(burst),
+		updated:           clock.Now(),
+	}
+}
+
+// Wait blocks until the request can be made, or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context, mutating bool) error {
+	priority := priorityPolling
+	if mutating {
+		priority = priorityMutating
+	}
+	ready := make(chan struct{})
+
+	l.lock.Lock()
+	l.queues[priority] = append(l.queues[priority], ready)
+	l.dispatch()
+	l.lock.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		for i, waiting := range l.queues[priority] {
+			if waiting == ready {
+				l.queues[priority] = append(l.queues[priority][:i:i], l.queues[priority][i+1:]...)
+				return fmt.Errorf("Error waiting to make request to Aiven: %w", ctx.Err())
+			}
+		}
+		// The request was given a token as the context finished, so is
+		// made rather than wasting it.
+		return nil
+	}
+}
+
+// dispatch gives the tokens there are to waiting requests, in priority
+// order, and schedules itself for when the next token is due if requests
+// are left waiting. It must be called with the lock held.
+func (l *RateLimiter) dispatch() {
+	now := l.clock.Now()
+	l.tokens += now.Sub(l.updated).Seconds() * l.requestsPerSecond
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.updated = now
+
+	waiting := false
+	for priority := range l.queues {
+		for len(l.queues[priority]) > 0 && l.tokens >= 1 {
+			close(l.queues[priority][0])
+			l.queues[priority] = l.queues[priority][1:]
+			l.tokens--
+		}
+		waiting = waiting || len(l.queues[priority]) > 0
+	}
+	if !waiting || l.scheduled {
+		return
+	}
+
+	l.scheduled = true
+	next := l.clock.After(time.Duration((1 - l.tokens) / l.requestsPerSecond * float64(time.Second)))
+	go func() {
+		<-next
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.scheduled = false
+		l.dispatch()
+	}()
+}
@@ -0,0 +1,150 @@
+package aiven_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeClock only moves when advanced. It counts calls to Now, which the
+// rate limiter makes once each time a request joins the queue, so that
+// tests can wait for requests to be queued.
+type fakeClock struct {
+	lock     sync.Mutex
+	now      time.Time
+	nowCalls int
+	timers   []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.nowCalls++
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	timer := fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	return timer.c
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	pending := []fakeTimer{}
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			pending = append(pending, timer)
+		} else {
+			timer.c <- c.now
+		}
+	}
+	c.timers = pending
+}
+
+func (c *fakeClock) NowCalls() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.nowCalls
+}
+
+var _ = Describe("RateLimiter", func() {
+	var (
+		clock   *fakeClock
+		limiter *aiven.RateLimiter
+	)
+
+	// wait makes a request in the background, returning a channel which
+	// gets its result.
+	wait := func(ctx context.Context, mutating bool) <-chan error {
+		done := make(chan error, 1)
+		go func(limiter *aiven.RateLimiter) {
+			done <- limiter.Wait(ctx, mutating)
+		}(limiter)
+		return done
+	}
+
+	BeforeEach(func() {
+		clock = &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	})
+
+	It("lets a burst through, then paces requests", func() {
+		limiter = aiven.NewRateLimiter(2, 2, clock)
+
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+
+		done := wait(context.Background(), false)
+		Consistently(done).ShouldNot(Receive())
+
+		clock.Advance(499 * time.Millisecond)
+		Consistently(done).ShouldNot(Receive())
+
+		clock.Advance(time.Millisecond)
+		Eventually(done).Should(Receive(BeNil()))
+	})
+
+	It("refills the bucket up to the burst while idle", func() {
+		limiter = aiven.NewRateLimiter(1, 2, clock)
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+
+		clock.Advance(time.Hour)
+
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+		done := wait(context.Background(), false)
+		Consistently(done).ShouldNot(Receive())
+	})
+
+	It("lets changes through before polling", func() {
+		limiter = aiven.NewRateLimiter(1, 1, clock)
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+		queued := clock.NowCalls()
+
+		polling := wait(context.Background(), false)
+		Eventually(clock.NowCalls).Should(Equal(queued + 1))
+		mutating := wait(context.Background(), true)
+		Eventually(clock.NowCalls).Should(Equal(queued + 2))
+
+		clock.Advance(time.Second)
+		Eventually(mutating).Should(Receive(BeNil()))
+		Consistently(polling).ShouldNot(Receive())
+
+		clock.Advance(time.Second)
+		Eventually(polling).Should(Receive(BeNil()))
+	})
+
+	It("gives up waiting when the context is done, without using a token", func() {
+		limiter = aiven.NewRateLimiter(1, 1, clock)
+		Expect(limiter.Wait(context.Background(), false)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := wait(ctx, true)
+		Consistently(done).ShouldNot(Receive())
+		cancel()
+
+		var err error
+		Eventually(done).Should(Receive(&err))
+		Expect(err).To(MatchError("Error waiting to make request to Aiven: context canceled"))
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+
+		clock.Advance(time.Second)
+		Eventually(wait(context.Background(), false)).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1,113 @@
+package aiven
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults for retrying requests which fail with transient errors.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 10 * time.Second
+)
+
+// RetryPolicy says how requests which fail with transient errors, such as
+// rate limits and 502s from Aiven's load balancers, are retried. Reads are
+// retried on 429s and 5xx responses. Other requests are only retried on
+// 429s, and 503s with a Retry-After header, which mean Aiven did not act on
+// them, so that retrying cannot make a change twice. Other 503s can come
+// from a load balancer after Aiven has made the change.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a request is made, including the
+	// first. Less than two means requests are not retried.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, which doubles for
+	// each retry after it, up to MaxDelay. The delays are jittered, so
+	// that requests which failed together are not retried together. Zero
+	// means requests are retried straight away.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy is the policy of clients made with NewHttpClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: DefaultMaxAttempts,
+	BaseDelay:   DefaultRetryBaseDelay,
+	MaxDelay:    DefaultRetryMaxDelay,
+}
+
+// shouldRetry says whether a request which got the response can be made
+// again. read says whether the request only queries Aiven.
+func (p RetryPolicy) shouldRetry(read bool, res *http.Response, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return true
+	case res.StatusCode >= 500:
+		return read || (res.StatusCode == http.StatusServiceUnavailable && res.Header.Get("Retry-After") != "")
+	}
+	return false
+}
+
+// delay is how long to wait before the retry after the attempt. A
+// Retry-After header takes precedence over the backoff, though the wait is
+// still at most MaxDelay.
+func (p RetryPolicy) delay(res *http.Response, attempt int) time.Duration {
+	if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return retryAfter
+	}
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	backoff := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || backoff < p.MaxDelay); i++ {
+		backoff *= 2
+	}
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	// Jitter spreads retries over the second half of the backoff.
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header, which Aiven gives in seconds,
+// though HTTP also allows a date.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for the delay, unless the context is done first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
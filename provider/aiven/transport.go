@@ -0,0 +1,61 @@
+package aiven
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Default transport settings, which stop a hung Aiven endpoint holding up
+// requests for longer than the platform waits for the broker.
+const (
+	DefaultRequestTimeout      = 30 * time.Second
+	DefaultDialTimeout         = 10 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// TransportConfig says how requests are sent to Aiven.
+type TransportConfig struct {
+	// RequestTimeout is how long a request, including reading the
+	// response, can take. Retries each get their own.
+	RequestTimeout      time.Duration
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+	// ProxyFromEnvironment sends requests through the proxy given by the
+	// HTTPS_PROXY and NO_PROXY environment variables, for deployments
+	// without direct access to Aiven.
+	ProxyFromEnvironment bool
+}
+
+// DefaultTransportConfig is the transport of clients made with
+// NewHttpClient.
+var DefaultTransportConfig = TransportConfig{
+	RequestTimeout:      DefaultRequestTimeout,
+	DialTimeout:         DefaultDialTimeout,
+	TLSHandshakeTimeout: DefaultTLSHandshakeTimeout,
+}
+
+// HTTPClient makes an http.Client with the settings. Its transport keeps
+// connections alive, so that requests reuse them rather than each dialling
+// Aiven and handshaking again.
+func (c TransportConfig) HTTPClient() *http.Client {
+	transport := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   c.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   c.TLSHandshakeTimeout,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	if c.ProxyFromEnvironment {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   c.RequestTimeout,
+	}
+}
@@ -1,17 +1,122 @@
 package aiven
 
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
 type CommonUserConfig struct {
 	IPFilter []string `json:"ip_filter,omitempty"`
+	// ServiceToForkFrom creates the service from a backup of another
+	// service in the same project.
+	ServiceToForkFrom string `json:"service_to_fork_from,omitempty"`
+	// RecoveryBasebackupName picks the backup a forked service is created
+	// from, rather than the latest. Only some service types support it.
+	RecoveryBasebackupName string `json:"recovery_basebackup_name,omitempty"`
+	// StaticIPs makes the service use the static IP addresses it was
+	// created with.
+	StaticIPs *bool `json:"static_ips,omitempty"`
+	// BackupHour and BackupMinute are the time of day, in UTC, at which the
+	// service is backed up. Only some service types support them.
+	BackupHour   *int `json:"backup_hour,omitempty"`
+	BackupMinute *int `json:"backup_minute,omitempty"`
+	// PublicAccess gives the components of a service in a project VPC
+	// public endpoints as well as private ones.
+	PublicAccess map[string]bool `json:"public_access,omitempty"`
 }
 
 type ElasticsearchUserConfig struct {
 	ElasticsearchVersion string `json:"elasticsearch_version,omitempty"`
+	// IndexPatterns is also used by OpenSearch. Aiven clears them when the
+	// list is empty, and leaves them as they are when it is nil.
+	IndexPatterns *[]IndexPattern `json:"index_patterns,omitempty"`
+}
+
+// IndexPattern limits how many indexes matching the pattern are kept, with
+// the oldest deleted first.
+type IndexPattern struct {
+	Pattern       string `json:"pattern"`
+	MaxIndexCount int    `json:"max_index_count"`
 }
 
 type InfluxDBUserConfig struct{}
 
+type KafkaAuthenticationMethods struct {
+	Certificate bool `json:"certificate"`
+	SASL        bool `json:"sasl"`
+}
+
+type KafkaUserConfig struct {
+	KafkaVersion               string                      `json:"kafka_version,omitempty"`
+	KafkaRest                  *bool                       `json:"kafka_rest,omitempty"`
+	KafkaConnect               *bool                       `json:"kafka_connect,omitempty"`
+	KafkaAuthenticationMethods *KafkaAuthenticationMethods `json:"kafka_authentication_methods,omitempty"`
+}
+
+type OpenSearchUserConfig struct {
+	OpenSearchVersion string `json:"opensearch_version,omitempty"`
+}
+
+type PostgresUserConfig struct {
+	PGVersion string `json:"pg_version,omitempty"`
+}
+
+type RedisUserConfig struct {
+	RedisMaxmemoryPolicy string `json:"redis_maxmemory_policy,omitempty"`
+}
+
 type UserConfig struct {
 	CommonUserConfig
 	ElasticsearchUserConfig
 	InfluxDBUserConfig
+	KafkaUserConfig
+	OpenSearchUserConfig
+	PostgresUserConfig
+	RedisUserConfig
+
+	// Defaults holds any other user config to send to Aiven. The fields
+	// above take precedence over keys with the same name.
+	Defaults map[string]interface{} `json:"-"`
+}
+
+func (u UserConfig) MarshalJSON() ([]byte, error) {
+	type userConfig UserConfig
+	explicit, err := json.Marshal(userConfig(u))
+	if err != nil || len(u.Defaults) == 0 {
+		return explicit, err
+	}
+
+	merged := map[string]interface{}{}
+	for key, value := range u.Defaults {
+		merged[key] = value
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(explicit, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return json.Marshal(merged)
+}
+
+// ExplicitKeys returns the top level keys of the user config fields above,
+// which take precedence over Defaults.
+func (u UserConfig) ExplicitKeys() []string {
+	keys := []string{}
+	t := reflect.TypeOf(u)
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).Anonymous {
+			continue
+		}
+		embedded := t.Field(i).Type
+		for j := 0; j < embedded.NumField(); j++ {
+			key := strings.Split(embedded.Field(j).Tag.Get("json"), ",")[0]
+			if key != "" && key != "-" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
 }
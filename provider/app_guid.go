@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+	"github.com/pivotal-cf/brokerapi"
+)
+
+// Aiven users cannot be given metadata, so the app each binding's user was
+// created for is recorded in a tag on the service instead. Tags are keyed by a
+// hash of the username, as usernames can be longer than tag keys.
+const (
+	appGUIDTagPrefix     = "cf_app_guid_"
+	appGUIDTagHashLength = 16
+)
+
+func appGUIDTag(username string) string {
+	hash := sha256.Sum256([]byte(username))
+	return appGUIDTagPrefix + hex.EncodeToString(hash[:])[:appGUIDTagHashLength]
+}
+
+// bindAppGUID is the app being bound, or empty for service keys.
+func bindAppGUID(details brokerapi.BindDetails) string {
+	if details.BindResource != nil && details.BindResource.AppGuid != "" {
+		return details.BindResource.AppGuid
+	}
+	return details.AppGUID
+}
+
+// appGUIDTags are the tags of the service which record the apps of its
+// bindings, and which have to be kept when the service is retagged.
+func appGUIDTags(service *aiven.Service) map[string]string {
+	tags := map[string]string{}
+	if service == nil {
+		return tags
+	}
+	for key, value := range service.Tags {
+		if strings.HasPrefix(key, appGUIDTagPrefix) {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// tagAppGUID records the app of the binding's user. The tag is only there to
+// help operators, so failing to add it does not fail the binding.
+func (ap *AivenProvider) tagAppGUID(ctx context.Context, project, serviceName string, service *aiven.Service, username, appGUID string) {
+	if appGUID == "" || service.Tags[appGUIDTag(username)] == appGUID {
+		return
+	}
+
+	tags := map[string]string{}
+	for key, value := range service.Tags {
+		tags[key] = value
+	}
+	tags[appGUIDTag(username)] = appGUID
+
+	err := ap.Client.UpdateServiceTags(ctx, &aiven.UpdateServiceTagsInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Tags:        tags,
+	})
+	if err != nil {
+		ap.Logger.Error("tag-app-guid", err, lager.Data{
+			"service":  serviceName,
+			"username": username,
+			"app_guid": appGUID,
+		})
+		return
+	}
+	service.Tags = tags
+}
+
+// untagAppGUID removes the tag of the binding's user, if it has one.
+func (ap *AivenProvider) untagAppGUID(ctx context.Context, project, serviceName string, service *aiven.Service, username string) {
+	if _, ok := service.Tags[appGUIDTag(username)]; !ok {
+		return
+	}
+
+	tags := map[string]string{}
+	for key, value := range service.Tags {
+		if key != appGUIDTag(username) {
+			tags[key] = value
+		}
+	}
+
+	err := ap.Client.UpdateServiceTags(ctx, &aiven.UpdateServiceTagsInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Tags:        tags,
+	})
+	if err != nil {
+		ap.Logger.Error("untag-app-guid", err, lager.Data{
+			"service":  serviceName,
+			"username": username,
+		})
+	}
+}
+
+// BindingUser is a user of an instance, along with the app it was created
+// for if it was recorded.
+type BindingUser struct {
+	Username string `json:"username"`
+	AppGUID  string `json:"app_guid,omitempty"`
+}
+
+// ListBindingUsers lists the users of an instance's bindings, so that
+// operators can tell which app a user seen in Aiven belongs to.
+func (ap *AivenProvider) ListBindingUsers(ctx context.Context, instanceID string) ([]BindingUser, error) {
+	config := ap.currentConfig()
+	serviceName := buildServiceName(config.ServiceNamePrefix, instanceID)
+
+	for _, project := range config.projects() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		services, err := ap.Client.ListServices(ctx, &aiven.ListServicesInput{Project: project})
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range services {
+			if service.ServiceName != serviceName {
+				continue
+			}
+
+			users, err := ap.Client.ListServiceUsers(ctx, &aiven.ListServiceUsersInput{
+				Project:     project,
+				ServiceName: serviceName,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			bindingUsers := []BindingUser{}
+			for _, user := range users {
+				// The primary user is the service's own, not a binding's.
+				if user.Type == "primary" {
+					continue
+				}
+				bindingUsers = append(bindingUsers, BindingUser{
+					Username: user.Username,
+					AppGUID:  service.Tags[appGUIDTag(user.Username)],
+				})
+			}
+			return bindingUsers, nil
+		}
+	}
+	return nil, aiven.ErrServiceDoesNotExist
+}
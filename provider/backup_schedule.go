@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// backupScheduleServiceTypes are the service types whose daily backup time
+// can be chosen. Aiven picks a time for the others.
+var backupScheduleServiceTypes = []string{"postgres", "redis"}
+
+// checkBackupSchedule rejects backup times which are out of range, only
+// give the hour or minute, or are for service types without them.
+func checkBackupSchedule(plan *Plan) error {
+	if plan.BackupHour == nil && plan.BackupMinute == nil {
+		return nil
+	}
+	if !contains(backupScheduleServiceTypes, plan.ServiceType) {
+		return fmt.Errorf(
+			"Config error: plan %s cannot have a backup time, only %s plans can",
+			plan.Name, strings.Join(backupScheduleServiceTypes, " and "),
+		)
+	}
+	if plan.BackupHour == nil || plan.BackupMinute == nil {
+		return fmt.Errorf("Config error: plan %s must have both a backup_hour and a backup_minute, or neither", plan.Name)
+	}
+	if *plan.BackupHour < 0 || *plan.BackupHour > 23 {
+		return fmt.Errorf("Config error: plan %s has backup_hour %d, which must be between 0 and 23", plan.Name, *plan.BackupHour)
+	}
+	if *plan.BackupMinute < 0 || *plan.BackupMinute > 59 {
+		return fmt.Errorf("Config error: plan %s has backup_minute %d, which must be between 0 and 59", plan.Name, *plan.BackupMinute)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-cf/brokerapi"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// checkBillingGroups rejects configs which put plans with different billing
+// groups in the same project. Aiven bills by project, so instances of the
+// plans could not be billed to their groups.
+func checkBillingGroups(config *Config) error {
+	billingGroupPlans := map[string]*Plan{}
+	for i := range config.Catalog.Services {
+		for j := range config.Catalog.Services[i].Plans {
+			plan := &config.Catalog.Services[i].Plans[j]
+			project := config.ProjectForPlan(plan)
+			other, ok := billingGroupPlans[project]
+			if !ok {
+				billingGroupPlans[project] = plan
+				continue
+			}
+			if config.BillingGroupIDForPlan(other) != config.BillingGroupIDForPlan(plan) {
+				return fmt.Errorf(
+					"Config error: plans %s and %s have different billing groups, but are in the same project, and Aiven bills by project",
+					other.Name, plan.Name,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// assignBillingGroup bills the project of a plan's instances to the plan's
+// billing group. Plans without one are left to the project's.
+func (ap *AivenProvider) assignBillingGroup(ctx context.Context, project, billingGroupID string) error {
+	err := ap.Client.AssignProjectToBillingGroup(ctx, &aiven.AssignProjectToBillingGroupInput{
+		Project:        project,
+		BillingGroupID: billingGroupID,
+	})
+	if err != nil {
+		ap.Logger.Error("assign-billing-group", err, lager.Data{
+			"project":          project,
+			"billing_group_id": billingGroupID,
+		})
+	}
+	return err
+}
+
+// retryBillingGroup tries again to assign the billing group of an operation
+// which failed to. Until it works the operation is reported as in progress,
+// so that the platform keeps polling, unless the billing group does not
+// exist, which retrying cannot fix.
+func (ap *AivenProvider) retryBillingGroup(
+	ctx context.Context,
+	project, billingGroupID string,
+	state brokerapi.LastOperationState,
+	description string,
+) (brokerapi.LastOperationState, string) {
+	err := ap.assignBillingGroup(ctx, project, billingGroupID)
+	if err == aiven.ErrBillingGroupDoesNotExist {
+		return brokerapi.Failed, fmt.Sprintf(
+			"Last operation failed: billing group %s does not exist. Contact your platform operators.", billingGroupID,
+		)
+	}
+	if err != nil && state == brokerapi.Succeeded {
+		return brokerapi.InProgress, "Assigning the service to its billing group"
+	}
+	return state, description
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-cf/brokerapi"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// RecoveryRecreated records, in a provision's operation data, that the
+// provision replaced a powered off service which had the instance's name.
+const RecoveryRecreated = "recreated"
+
+// createService creates the instance's service. A service which already has
+// its name is either one an earlier attempt of the request created, as the
+// platform retries provisions which time out, or one an earlier instance
+// with the same ID left powered off when deleting it did not finish, or
+// which Aiven is still deleting. It reports whether this call created the
+// service, and how a powered off service was recovered from.
+func (ap *AivenProvider) createService(
+	ctx context.Context,
+	config *Config,
+	createServiceInput *aiven.CreateServiceInput,
+	plan *Plan,
+) (created bool, recovery string, err error) {
+	_, err = ap.Client.CreateService(ctx, createServiceInput)
+	if err != aiven.ErrServiceAlreadyExists {
+		return err == nil, "", err
+	}
+
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     createServiceInput.Project,
+		ServiceName: createServiceInput.ServiceName,
+	})
+	if err != nil {
+		return false, "", err
+	}
+	if service.State == aiven.Terminating {
+		// The earlier instance's service is on its way out, so the platform
+		// is asked to try again once it has gone, rather than being told
+		// the dying service is the instance.
+		return false, "", brokerapi.NewFailureResponseBuilder(
+			errors.New("A service with the instance's name is still being deleted, try again shortly"),
+			http.StatusUnprocessableEntity,
+			"terminating-service-exists",
+		).WithErrorKey("ConcurrencyError").Build()
+	}
+	if service.State != aiven.PowerOff {
+		return false, "", checkExistingService(service, createServiceInput, plan)
+	}
+	if !config.RecreatePoweredOffServices {
+		return false, "", poweredOffServiceExists(createServiceInput.ServiceName)
+	}
+
+	if err := ap.deletePoweredOffService(ctx, createServiceInput.Project, createServiceInput.ServiceName); err != nil {
+		return false, "", err
+	}
+	_, err = ap.Client.CreateService(ctx, createServiceInput)
+	if err == aiven.ErrServiceAlreadyExists {
+		// Aiven has not finished deleting the service, so the platform is
+		// asked to try again rather than the provision failing.
+		return false, "", brokerapi.NewFailureResponseBuilder(
+			errors.New("A powered off service with the instance's name is still being deleted, try again shortly"),
+			http.StatusUnprocessableEntity,
+			"powered-off-service-deleting",
+		).WithErrorKey("ConcurrencyError").Build()
+	}
+	if err != nil {
+		return false, "", err
+	}
+	return true, RecoveryRecreated, nil
+}
+
+// deletePoweredOffService deletes a service which an earlier instance left
+// powered off, and releases its static IPs.
+func (ap *AivenProvider) deletePoweredOffService(ctx context.Context, project, serviceName string) error {
+	deleted, err := ap.deleteStuckService(ctx, project, serviceName)
+	if err == aiven.ErrTerminationProtectionEnabled {
+		return brokerapi.NewFailureResponse(
+			fmt.Errorf(
+				"A powered off service with the instance's name, %s, has termination protection enabled, so it cannot be replaced. Contact your platform operators.",
+				serviceName,
+			),
+			http.StatusConflict,
+			"powered-off-service-protected",
+		)
+	}
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		// It has been powered back on since it was fetched.
+		return brokerapi.ErrInstanceAlreadyExists
+	}
+	ap.Logger.Info("deleted-powered-off-service", lager.Data{
+		"project": project,
+		"service": serviceName,
+	})
+	return nil
+}
+
+func poweredOffServiceExists(serviceName string) error {
+	return brokerapi.NewFailureResponse(
+		fmt.Errorf(
+			"A powered off service with the instance's name, %s, was left by an earlier instance which was not completely deleted. Contact your platform operators to delete it.",
+			serviceName,
+		),
+		http.StatusConflict,
+		"powered-off-service-exists",
+	)
+}
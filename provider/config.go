@@ -1,22 +1,172 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
 	"github.com/pivotal-cf/brokerapi"
 )
 
 type Config struct {
-	Cloud             string `json:"cloud"`
+	Cloud string `json:"cloud"`
+	// ProjectVPCID is the project VPC, peered with the platform's network,
+	// which services are created in so that their traffic stays private. It
+	// must be in the cloud of every plan which does not override it.
+	ProjectVPCID string `json:"project_vpc_id"`
+	// IPWhitelist is the platform's IP addresses and CIDR blocks, which every
+	// instance accepts connections from. When it is omitted it is read from
+	// the comma separated IP_WHITELIST environment variable.
+	IPWhitelist []string `json:"ip_whitelist"`
+	// RetiredIPWhitelist is addresses and blocks which were in the
+	// IPWhitelist, but are no longer the platform's. Instances' filters keep
+	// the entries they had when they are updated, so these are removed
+	// from them when they are updated or the whitelist is synced.
+	RetiredIPWhitelist []string `json:"retired_ip_whitelist"`
+	// IPWhitelistAllowAll opens every instance to all IP addresses, for
+	// development environments. It is the same as whitelisting 0.0.0.0/0.
+	IPWhitelistAllowAll bool `json:"ip_whitelist_allow_all"`
+	// AllowedClouds are the clouds tenants can choose for their instances
+	// with the cloud parameter, such as for data residency. When it is
+	// empty the parameter is rejected.
+	AllowedClouds []string `json:"allowed_clouds"`
+	// WhitelistGroups are named lists of IP addresses and CIDR blocks, such
+	// as office ranges, which tenants can add to instances by name with the
+	// ip_filter_groups parameter.
+	WhitelistGroups map[string][]string `json:"whitelist_groups"`
+	// ForbidAllowAllIPs stops the whitelist or tenants opening instances to
+	// all IP addresses, to guard production environments.
+	ForbidAllowAllIPs bool `json:"forbid_allow_all_ips"`
+	// IPWhitelistRemovable lets tenants replace the platform whitelist with
+	// their own ip_filter. By default the platform's entries are always kept.
+	IPWhitelistRemovable bool `json:"ip_whitelist_removable"`
+	// IPFilterMaxEntries is the most ip_filter entries Aiven accepts. It
+	// defaults to DefaultIPFilterMaxEntries.
+	IPFilterMaxEntries int `json:"ip_filter_max_entries"`
+	// BindAvailabilityTimeoutSeconds is how long Bind waits for a new user to
+	// be able to connect before returning the binding anyway. It defaults to
+	// DefaultBindAvailabilityTimeoutSeconds.
+	BindAvailabilityTimeoutSeconds int `json:"bind_availability_timeout_seconds"`
+	// SkipBindAvailabilityCheck lists the service types whose new users Bind
+	// does not wait for.
+	SkipBindAvailabilityCheck []string `json:"skip_bind_availability_check"`
+	// CustomUsernamePrefixes, if set, are the prefixes one of which usernames
+	// asked for with the username bind parameter must start with.
+	CustomUsernamePrefixes []string `json:"custom_username_prefixes"`
+	// IncludeAivenMetadata adds the Aiven project, service name, service
+	// type, cloud and plan of the instance to binding credentials, as aiven.
+	IncludeAivenMetadata bool `json:"include_aiven_metadata"`
+	// ProjectServiceLimit is how many services Aiven allows in each project.
+	// When it is set, Provision checks the project has room for another
+	// service before creating it, at the cost of an extra API call, so that
+	// a full project is reported as such. Zero means no check.
+	ProjectServiceLimit int `json:"project_service_limit"`
+	// ProvisionTimeoutSeconds is how long a new service has to start running
+	// before its provision is reported as failed. It defaults to
+	// DefaultProvisionTimeoutSeconds, and zero means no timeout.
+	ProvisionTimeoutSeconds *int `json:"provision_timeout_seconds"`
+	// UpdateTimeoutSeconds and DeprovisionTimeoutSeconds are how long
+	// updates and deprovisions have to finish before they are reported as
+	// failed. The service is left as it is, for the platform operators to
+	// look at. They default to DefaultUpdateTimeoutSeconds and
+	// DefaultDeprovisionTimeoutSeconds, and zero means no timeout.
+	UpdateTimeoutSeconds      *int `json:"update_timeout_seconds"`
+	DeprovisionTimeoutSeconds *int `json:"deprovision_timeout_seconds"`
+	// ServiceStatusCacheSeconds is how long LastOperation reuses a service
+	// it has fetched from Aiven, to cut the requests polling makes. Zero
+	// means services are fetched on every poll.
+	ServiceStatusCacheSeconds int `json:"service_status_cache_seconds"`
+	// DiskUsageWarningPercent is how full a service's disk can get before
+	// its succeeded last operations warn the tenant. Zero means no warning.
+	DiskUsageWarningPercent int `json:"disk_usage_warning_percent"`
+	// AivenMaxAttempts is how many times requests to Aiven which fail with
+	// transient errors are made, and AivenRetryBaseDelayMilliseconds how
+	// long to wait before the first retry. They default to those of
+	// aiven.DefaultRetryPolicy, and are only read on startup.
+	AivenMaxAttempts                int `json:"aiven_max_attempts"`
+	AivenRetryBaseDelayMilliseconds int `json:"aiven_retry_base_delay_ms"`
+	// AivenRequestsPerSecond limits how many requests are made to Aiven,
+	// letting through bursts of up to AivenRequestBurst requests, which
+	// defaults to AivenRequestsPerSecond rounded up. Zero means requests
+	// are not limited. They are only read on startup.
+	AivenRequestsPerSecond float64 `json:"aiven_requests_per_second"`
+	AivenRequestBurst      int     `json:"aiven_request_burst"`
+	// AivenRequestTimeoutSeconds, AivenDialTimeoutSeconds and
+	// AivenTLSHandshakeTimeoutSeconds limit how long requests to Aiven can
+	// take. They default to those of aiven.DefaultTransportConfig.
+	// AivenProxyFromEnvironment sends requests through the HTTPS_PROXY.
+	// They are only read on startup.
+	AivenRequestTimeoutSeconds      int  `json:"aiven_request_timeout_seconds"`
+	AivenDialTimeoutSeconds         int  `json:"aiven_dial_timeout_seconds"`
+	AivenTLSHandshakeTimeoutSeconds int  `json:"aiven_tls_handshake_timeout_seconds"`
+	AivenProxyFromEnvironment       bool `json:"aiven_proxy_from_environment"`
+	// DeleteStuckProvisions deletes services which have not started running
+	// by the provision timeout, so that they are not left being billed.
+	DeleteStuckProvisions bool `json:"delete_stuck_provisions"`
+	// RecreatePoweredOffServices replaces a powered off service which has
+	// the name of an instance being provisioned, as deleting an earlier
+	// instance with the same ID did not finish. Otherwise the provision
+	// fails, leaving the service for the platform operators to look at.
+	RecreatePoweredOffServices bool `json:"recreate_powered_off_services"`
+	// CloneSourcePolicy is what instances cloned with clone_from must have
+	// in common with the new instance: CloneSourcePolicySameOrganization,
+	// the default, or CloneSourcePolicySameSpace.
+	CloneSourcePolicy string `json:"clone_source_policy"`
+	// AlwaysUpdateServices sends every update to Aiven, rather than skipping
+	// those without parameters or a plan change which would not change the
+	// service, so that updates reassert the plans' config.
+	AlwaysUpdateServices bool `json:"always_update_services"`
+	// DisableRestarts stops tenants restarting their instances with the
+	// restart update parameter.
+	DisableRestarts bool `json:"disable_restarts"`
+	// LenientParameters ignores provision and update parameters which the
+	// broker does not know, as it used to, rather than rejecting them. It is
+	// for tenants to fix their parameters while strict checking rolls out.
+	LenientParameters bool `json:"lenient_parameters"`
+	// BillingGroupID is the Aiven billing group which the projects of plans
+	// without their own are billed to. Empty leaves projects' billing as
+	// it is.
+	BillingGroupID string `json:"billing_group_id"`
+	// ConsoleURL is the base URL of the Aiven console which instances'
+	// dashboard URLs link to. It defaults to DefaultConsoleURL.
+	ConsoleURL string `json:"console_url"`
+	// UserConfigAllowList lists, for each service type, the user config
+	// paths such as elasticsearch.action_destructive_requires_name which
+	// tenants can set with the user_config parameter. A path allows every
+	// setting inside it. Keys the broker manages cannot be allowed.
+	UserConfigAllowList map[string][]string `json:"user_config_allow_list"`
+	// AllowedOrgGUIDs, if set, are the only organizations which can create
+	// instances. Instances which already exist can still be bound, updated
+	// and deleted. Empty means every organization can.
+	AllowedOrgGUIDs []string `json:"allowed_org_guids"`
+	// CredHub stores binding credentials in CredHub, so that apps are given a
+	// reference to them rather than the credentials themselves.
+	CredHub           *CredHubConfig `json:"credhub"`
 	ServiceNamePrefix string
 	APIToken          string
 	Project           string
 	Catalog           Catalog `json:"catalog"`
 }
 
+type CredHubConfig struct {
+	URL             string `json:"url"`
+	UAAURL          string `json:"uaa_url"`
+	UAAClientName   string `json:"uaa_client_name"`
+	UAAClientSecret string `json:"uaa_client_secret"`
+	// CACert is trusted as well as the system's CA certificates, for CredHub
+	// and UAA servers with internal certificates.
+	CACert string `json:"ca_cert"`
+}
+
 type Catalog struct {
 	Services []Service `json:"services"`
 }
@@ -39,14 +189,102 @@ type AivenServiceElasticsearchConfig struct {
 
 type AivenServiceInfluxDBConfig struct{}
 
+type AivenServiceKafkaConfig struct {
+	KafkaVersion string `json:"kafka_version"`
+	KafkaRest    bool   `json:"kafka_rest"`
+	KafkaConnect bool   `json:"kafka_connect"`
+}
+
+type AivenServiceOpenSearchConfig struct {
+	OpenSearchVersion string `json:"opensearch_version"`
+	// UpgradeFromElasticsearch allows existing elasticsearch instances to be
+	// converted in place to opensearch by updating them to this plan.
+	UpgradeFromElasticsearch bool `json:"upgrade_from_elasticsearch"`
+}
+
+type AivenServicePostgresConfig struct {
+	PGVersion string `json:"pg_version"`
+}
+
+type AivenServiceRedisConfig struct {
+	RedisMaxmemoryPolicy string `json:"redis_maxmemory_policy"`
+}
+
 type PlanSpecificConfig struct {
 	AivenPlan string `json:"aiven_plan"`
+	// ServiceType is the kind of Aiven service the plan provisions. When it
+	// is omitted it defaults to the name of the service if that is a known
+	// service type, and to elasticsearch otherwise.
+	ServiceType string `json:"service_type"`
+	// Cloud overrides the top level cloud, so that plans can be pinned to
+	// particular regions.
+	Cloud string `json:"cloud"`
+	// ProjectVPCID overrides the top level project_vpc_id, for plans in
+	// other clouds or projects.
+	ProjectVPCID string `json:"project_vpc_id"`
+	// Project overrides the Aiven project set by AIVEN_PROJECT, so that
+	// instances can be spread across projects to stay within their limits.
+	// Instances stay in the project they were created in, so it should not
+	// be changed for plans which have instances.
+	Project string `json:"project"`
+	// OrganizationQuota limits how many instances of the plan each
+	// organization can have. Zero means no limit.
+	OrganizationQuota int `json:"organization_quota"`
+	// MaxBindings limits how many bindings with their own user each instance
+	// of the plan can have, for service types whose user count is limited.
+	// Zero means no limit.
+	MaxBindings int `json:"max_bindings"`
+	// AdditionalDiskSpaceGB is how much disk space, beyond the Aiven plan's,
+	// instances of the plan get by default, and MaxAdditionalDiskSpaceGB the
+	// most tenants can choose with the additional_disk_space_gb parameter.
+	// Tenants cannot choose when it is zero.
+	AdditionalDiskSpaceGB    int `json:"additional_disk_space_gb"`
+	MaxAdditionalDiskSpaceGB int `json:"max_additional_disk_space_gb"`
+	// DiskAutoscalerCapGB turns on Aiven's disk autoscaler for instances of
+	// the plan, which grows their disks as they fill up to at most this
+	// size. Zero leaves it off.
+	DiskAutoscalerCapGB int `json:"disk_autoscaler_cap_gb"`
+	// StaticIPCount is how many static IP addresses are created for
+	// instances of the plan provisioned with static_ips. Zero means the plan
+	// does not support them.
+	StaticIPCount int `json:"static_ip_count"`
+	// BackupHour and BackupMinute are the time of day, in UTC, at which
+	// instances of the plan are backed up. Aiven picks a time when they are
+	// omitted. Only postgres and redis plans can have them.
+	BackupHour   *int `json:"backup_hour"`
+	BackupMinute *int `json:"backup_minute"`
+	// BillingGroupID overrides the top level billing_group_id. Aiven bills
+	// by project, so plans in the same project must have the same one.
+	BillingGroupID string `json:"billing_group_id"`
+	// AllowedVersions lists engine versions, newer than the plan's, which
+	// tenants can upgrade instances of the plan to with the version update
+	// parameter. Plans without them do not accept the parameter.
+	AllowedVersions []string `json:"allowed_versions"`
+	// AllowedUpdatesTo lists the IDs of the plans in the same service which
+	// instances of the plan can be updated to. When it is omitted any plan
+	// change is allowed, and an empty list allows none.
+	AllowedUpdatesTo []string `json:"allowed_updates_to"`
+	// Features turns features of the service type, such as kibana_enabled
+	// for elasticsearch, on or off. They take precedence over UserConfig.
+	Features map[string]bool `json:"features"`
+	// UserConfig is passed through to Aiven as the service's user config.
+	// Settings the broker manages, such as the version and IP filter, take
+	// precedence over it.
+	UserConfig map[string]interface{} `json:"user_config"`
 
 	AivenServiceCommonConfig
 	AivenServiceElasticsearchConfig
 	AivenServiceInfluxDBConfig
+	AivenServiceKafkaConfig
+	AivenServiceOpenSearchConfig
+	AivenServicePostgresConfig
+	AivenServiceRedisConfig
 }
 
+// validServiceNamePrefix matches the prefixes which make valid Aiven
+// service names. They are lowercased in service names.
+var validServiceNamePrefix = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
 func DecodeConfig(b []byte) (*Config, error) {
 	var config *Config
 	err := json.Unmarshal(b, &config)
@@ -57,9 +295,102 @@ func DecodeConfig(b []byte) (*Config, error) {
 	if ok {
 		config.Cloud = aivenCloud
 	}
-	if config.Cloud == "" {
+	if config.Cloud == "" && !config.Catalog.hasPlanWithCloud() {
 		return config, errors.New("Config error: must provide cloud configuration. For example, 'aws-eu-west-1'")
 	}
+	if config.IPWhitelist == nil {
+		config.IPWhitelist, err = ParseIPWhitelist(os.Getenv("IP_WHITELIST"))
+	} else {
+		config.IPWhitelist, err = parseIPWhitelistEntries(config.IPWhitelist)
+	}
+	if err != nil {
+		return config, fmt.Errorf("Config error: %s", err)
+	}
+	if config.RetiredIPWhitelist != nil {
+		config.RetiredIPWhitelist, err = parseIPWhitelistEntries(config.RetiredIPWhitelist)
+		if err != nil {
+			return config, fmt.Errorf("Config error: retired_ip_whitelist: %s", err)
+		}
+	}
+	for name, group := range config.WhitelistGroups {
+		config.WhitelistGroups[name], err = parseIPWhitelistEntries(group)
+		if err != nil {
+			return config, fmt.Errorf("Config error: whitelist group %s: %s", name, err)
+		}
+	}
+	if config.IPWhitelistAllowAll {
+		config.IPWhitelist = []string{AllowAllIPFilterEntry}
+	}
+	if config.ForbidAllowAllIPs && allowsAllIPs(config.IPWhitelist) {
+		return config, errors.New("Config error: the IP whitelist allows all IP addresses but forbid_allow_all_ips is set")
+	}
+	if config.IPFilterMaxEntries < 0 {
+		return config, errors.New("Config error: ip_filter_max_entries cannot be negative")
+	}
+	if config.BindAvailabilityTimeoutSeconds < 0 {
+		return config, errors.New("Config error: bind_availability_timeout_seconds cannot be negative")
+	}
+	for _, serviceType := range config.SkipBindAvailabilityCheck {
+		if !knownServiceTypes[serviceType] {
+			return config, fmt.Errorf("Config error: skip_bind_availability_check has unknown service type %s", serviceType)
+		}
+	}
+	for name, timeout := range map[string]*int{
+		"provision_timeout_seconds":   config.ProvisionTimeoutSeconds,
+		"update_timeout_seconds":      config.UpdateTimeoutSeconds,
+		"deprovision_timeout_seconds": config.DeprovisionTimeoutSeconds,
+	} {
+		if timeout != nil && *timeout < 0 {
+			return config, fmt.Errorf("Config error: %s cannot be negative", name)
+		}
+	}
+	if config.DiskUsageWarningPercent < 0 || config.DiskUsageWarningPercent > 100 {
+		return config, errors.New("Config error: disk_usage_warning_percent must be between 0 and 100")
+	}
+	if config.ServiceStatusCacheSeconds < 0 {
+		return config, errors.New("Config error: service_status_cache_seconds cannot be negative")
+	}
+	if config.AivenMaxAttempts < 0 {
+		return config, errors.New("Config error: aiven_max_attempts cannot be negative")
+	}
+	if config.AivenRetryBaseDelayMilliseconds < 0 {
+		return config, errors.New("Config error: aiven_retry_base_delay_ms cannot be negative")
+	}
+	if config.AivenRequestsPerSecond < 0 {
+		return config, errors.New("Config error: aiven_requests_per_second cannot be negative")
+	}
+	if config.AivenRequestBurst < 0 {
+		return config, errors.New("Config error: aiven_request_burst cannot be negative")
+	}
+	for name, timeout := range map[string]int{
+		"aiven_request_timeout_seconds":       config.AivenRequestTimeoutSeconds,
+		"aiven_dial_timeout_seconds":          config.AivenDialTimeoutSeconds,
+		"aiven_tls_handshake_timeout_seconds": config.AivenTLSHandshakeTimeoutSeconds,
+	} {
+		if timeout < 0 {
+			return config, fmt.Errorf("Config error: %s cannot be negative", name)
+		}
+	}
+	if config.DeleteStuckProvisions && config.operationTimeoutSeconds(OperationProvision) == 0 {
+		return config, errors.New("Config error: delete_stuck_provisions requires a provision_timeout_seconds")
+	}
+	switch config.CloneSourcePolicy {
+	case "", CloneSourcePolicySameOrganization, CloneSourcePolicySameSpace:
+	default:
+		return config, fmt.Errorf(
+			"Config error: clone_source_policy must be %s or %s", CloneSourcePolicySameOrganization, CloneSourcePolicySameSpace,
+		)
+	}
+	if err := checkBillingGroups(config); err != nil {
+		return config, err
+	}
+	if err := checkUserConfigAllowList(config.UserConfigAllowList); err != nil {
+		return config, err
+	}
+	if config.CredHub != nil && (config.CredHub.URL == "" || config.CredHub.UAAURL == "" ||
+		config.CredHub.UAAClientName == "" || config.CredHub.UAAClientSecret == "") {
+		return config, errors.New("Config error: credhub must specify a `url`, `uaa_url`, `uaa_client_name` and `uaa_client_secret`")
+	}
 	if reflect.DeepEqual(config.Catalog, Catalog{}) {
 		return config, errors.New("Config error: no catalog found")
 	}
@@ -71,14 +402,52 @@ func DecodeConfig(b []byte) (*Config, error) {
 		if len(service.Plans) == 0 {
 			return config, errors.New("Config error: at least one plan must be configured for service " + service.Name)
 		}
-		for _, plan := range service.Plans {
+		for i := range service.Plans {
+			plan := &service.Plans[i]
 			if plan.AivenPlan == "" {
 				return config, errors.New("Config error: every plan must specify an `aiven_plan`")
 			}
+			if plan.Cloud == "" && config.Cloud == "" {
+				return config, fmt.Errorf("Config error: plan %s must specify a `cloud` as there is no default cloud configured", plan.Name)
+			}
+
+			if plan.ServiceType == "" {
+				plan.ServiceType = defaultServiceType(service.Name)
+			}
+			if !knownServiceTypes[plan.ServiceType] {
+				return config, fmt.Errorf("Config error: plan %s has unknown service type %s", plan.Name, plan.ServiceType)
+			}
 
-			if service.Name == "elasticsearch" && plan.ElasticsearchVersion == "" {
+			if plan.ServiceType == "elasticsearch" && plan.ElasticsearchVersion == "" {
 				return config, errors.New("Config error: every elasticsearch plan must specify an `elasticsearch_version`")
 			}
+			if plan.ServiceType == "opensearch" && plan.OpenSearchVersion == "" {
+				return config, errors.New("Config error: every opensearch plan must specify an `opensearch_version`")
+			}
+
+			if err := checkPlanFeatures(plan); err != nil {
+				return config, err
+			}
+			if err := checkBackupSchedule(plan); err != nil {
+				return config, err
+			}
+			if err := checkAllowedVersions(plan); err != nil {
+				return config, err
+			}
+			if plan.AdditionalDiskSpaceGB < 0 || plan.MaxAdditionalDiskSpaceGB < 0 || plan.DiskAutoscalerCapGB < 0 {
+				return config, fmt.Errorf("Config error: plan %s cannot have negative disk space", plan.Name)
+			}
+			if plan.MaxAdditionalDiskSpaceGB > 0 && plan.AdditionalDiskSpaceGB > plan.MaxAdditionalDiskSpaceGB {
+				return config, fmt.Errorf(
+					"Config error: plan %s has additional_disk_space_gb larger than its max_additional_disk_space_gb", plan.Name,
+				)
+			}
+
+			for _, planID := range plan.AllowedUpdatesTo {
+				if _, err := findPlanById(planID, service); err != nil {
+					return config, fmt.Errorf("Config error: plan %s allows updates to unknown plan %s", plan.Name, planID)
+				}
+			}
 		}
 	}
 
@@ -89,8 +458,11 @@ func DecodeConfig(b []byte) (*Config, error) {
 
 	// Aiven only allow 64 characters for the service name. The instanceID from Cloud Foundry
 	// is joined with a hyphen to the service name prefix. This gives us 27 characters to use.
-	if len(config.ServiceNamePrefix) > 27 {
-		return config, errors.New("Config error: service name prefix cannot be longer than 8 characters")
+	if len(config.ServiceNamePrefix) > maxServiceNamePrefixLength {
+		return config, fmt.Errorf("Config error: service name prefix cannot be longer than %d characters", maxServiceNamePrefixLength)
+	}
+	if !validServiceNamePrefix.MatchString(config.ServiceNamePrefix) {
+		return config, errors.New("Config error: service name prefix must start with a letter, and only contain letters, digits and hyphens")
 	}
 
 	config.APIToken = os.Getenv("AIVEN_API_TOKEN")
@@ -106,14 +478,256 @@ func DecodeConfig(b []byte) (*Config, error) {
 	return config, nil
 }
 
+func (c *Catalog) hasPlanWithCloud() bool {
+	for _, service := range c.Services {
+		for _, plan := range service.Plans {
+			if plan.Cloud != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DefaultBindAvailabilityTimeoutSeconds leaves Bind time to respond within
+// the broker's own 30 second deadline.
+const DefaultBindAvailabilityTimeoutSeconds = 25
+
+func (c *Config) bindAvailabilityTimeout() time.Duration {
+	if c.BindAvailabilityTimeoutSeconds == 0 {
+		return DefaultBindAvailabilityTimeoutSeconds * time.Second
+	}
+	return time.Duration(c.BindAvailabilityTimeoutSeconds) * time.Second
+}
+
+// aivenRetryPolicy is how the Aiven client retries requests which fail with
+// transient errors.
+func (c *Config) aivenRetryPolicy() aiven.RetryPolicy {
+	policy := aiven.DefaultRetryPolicy
+	if c.AivenMaxAttempts != 0 {
+		policy.MaxAttempts = c.AivenMaxAttempts
+	}
+	if c.AivenRetryBaseDelayMilliseconds != 0 {
+		policy.BaseDelay = time.Duration(c.AivenRetryBaseDelayMilliseconds) * time.Millisecond
+	}
+	return policy
+}
+
+// aivenTransport is how requests are sent to Aiven.
+func (c *Config) aivenTransport() aiven.TransportConfig {
+	transport := aiven.DefaultTransportConfig
+	if c.AivenRequestTimeoutSeconds != 0 {
+		transport.RequestTimeout = time.Duration(c.AivenRequestTimeoutSeconds) * time.Second
+	}
+	if c.AivenDialTimeoutSeconds != 0 {
+		transport.DialTimeout = time.Duration(c.AivenDialTimeoutSeconds) * time.Second
+	}
+	if c.AivenTLSHandshakeTimeoutSeconds != 0 {
+		transport.TLSHandshakeTimeout = time.Duration(c.AivenTLSHandshakeTimeoutSeconds) * time.Second
+	}
+	transport.ProxyFromEnvironment = c.AivenProxyFromEnvironment
+	return transport
+}
+
+// aivenRateLimiter paces requests to Aiven, or is nil if they are not
+// limited.
+func (c *Config) aivenRateLimiter() *aiven.RateLimiter {
+	if c.AivenRequestsPerSecond == 0 {
+		return nil
+	}
+	burst := c.AivenRequestBurst
+	if burst == 0 {
+		burst = int(math.Ceil(c.AivenRequestsPerSecond))
+	}
+	return aiven.NewRateLimiter(c.AivenRequestsPerSecond, burst, aiven.SystemClock)
+}
+
+// Default operation timeouts. Plan changes rebuild the service onto new
+// nodes, and copy all its data, so updates are given longer.
+const (
+	DefaultProvisionTimeoutSeconds   = 2 * 60 * 60
+	DefaultUpdateTimeoutSeconds      = 6 * 60 * 60
+	DefaultDeprovisionTimeoutSeconds = 2 * 60 * 60
+)
+
+// operationTimeoutSeconds is how long operations of the type have to finish,
+// or zero for no timeout.
+func (c *Config) operationTimeoutSeconds(operation string) int {
+	configured, defaultSeconds := c.ProvisionTimeoutSeconds, DefaultProvisionTimeoutSeconds
+	switch operation {
+	case OperationUpdate:
+		configured, defaultSeconds = c.UpdateTimeoutSeconds, DefaultUpdateTimeoutSeconds
+	case OperationDeprovision:
+		configured, defaultSeconds = c.DeprovisionTimeoutSeconds, DefaultDeprovisionTimeoutSeconds
+	}
+	if configured == nil {
+		return defaultSeconds
+	}
+	return *configured
+}
+
+// Clone source policies say which instances tenants can clone the data of.
+const (
+	CloneSourcePolicySameOrganization = "same_organization"
+	CloneSourcePolicySameSpace        = "same_space"
+)
+
+// DefaultConsoleURL is Aiven's global console.
+const DefaultConsoleURL = "https://console.aiven.io"
+
+// dashboardURL links to the service in the Aiven console.
+func (c *Config) dashboardURL(project, serviceName string) string {
+	consoleURL := c.ConsoleURL
+	if consoleURL == "" {
+		consoleURL = DefaultConsoleURL
+	}
+	return fmt.Sprintf(
+		"%s/project/%s/services/%s",
+		strings.TrimSuffix(consoleURL, "/"), url.PathEscape(project), url.PathEscape(serviceName),
+	)
+}
+
+// CloudForPlan returns the cloud the plan's services should run in.
+func (c *Config) CloudForPlan(plan *Plan) string {
+	if plan.Cloud != "" {
+		return plan.Cloud
+	}
+	return c.Cloud
+}
+
+// ProjectVPCIDForPlan returns the project VPC the plan's services are
+// created in, or empty if they are not created in one.
+func (c *Config) ProjectVPCIDForPlan(plan *Plan) string {
+	if plan.ProjectVPCID != "" {
+		return plan.ProjectVPCID
+	}
+	return c.ProjectVPCID
+}
+
+// BillingGroupIDForPlan returns the billing group the project of the plan's
+// instances is billed to.
+func (c *Config) BillingGroupIDForPlan(plan *Plan) string {
+	if plan.BillingGroupID != "" {
+		return plan.BillingGroupID
+	}
+	return c.BillingGroupID
+}
+
+// ProjectForPlan returns the Aiven project the plan's services are created in.
+func (c *Config) ProjectForPlan(plan *Plan) string {
+	if plan.Project != "" {
+		return plan.Project
+	}
+	return c.Project
+}
+
+// projects lists every Aiven project instances can be in, starting with the
+// default one.
+func (c *Config) projects() []string {
+	projects := []string{c.Project}
+	for _, service := range c.Catalog.Services {
+		for _, plan := range service.Plans {
+			if plan.Project != "" && !contains(projects, plan.Project) {
+				projects = append(projects, plan.Project)
+			}
+		}
+	}
+	return projects
+}
+
+// hasPlanTransitions reports whether any plan of the service restricts which
+// plans it can be updated to.
+func (c *Config) hasPlanTransitions(serviceID string) bool {
+	service, err := findServiceById(serviceID, &c.Catalog)
+	if err != nil {
+		return false
+	}
+	for _, plan := range service.Plans {
+		if plan.AllowedUpdatesTo != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// plansWithAivenPlan returns the plans of the service which use the Aiven plan.
+func (c *Config) plansWithAivenPlan(serviceID, aivenPlan string) []Plan {
+	plans := []Plan{}
+	service, err := findServiceById(serviceID, &c.Catalog)
+	if err != nil {
+		return plans
+	}
+	for _, plan := range service.Plans {
+		if plan.AivenPlan == aivenPlan {
+			plans = append(plans, plan)
+		}
+	}
+	return plans
+}
+
+func (p *Plan) allowsUpdateTo(planID string) bool {
+	return p.AllowedUpdatesTo == nil || p.ID == planID || contains(p.AllowedUpdatesTo, planID)
+}
+
+var knownServiceTypes = map[string]bool{
+	"elasticsearch": true,
+	"influxdb":      true,
+	"kafka":         true,
+	"opensearch":    true,
+	"postgres":      true,
+	"redis":         true,
+}
+
+// defaultServiceType keeps configs which predate per-plan service types
+// working, as those named their services after the service type.
+func defaultServiceType(serviceName string) string {
+	serviceType := strings.ToLower(serviceName)
+	if knownServiceTypes[serviceType] {
+		return serviceType
+	}
+	return "elasticsearch"
+}
+
+// PlanNotFoundError is returned when a service or plan is not in the config,
+// for example because the catalog has changed since an instance was created.
+type PlanNotFoundError struct {
+	ServiceID string
+	PlanID    string
+	// AvailablePlans are the plans configured for the service, and are empty
+	// if the service itself is unknown.
+	AvailablePlans []Plan
+}
+
+func (e *PlanNotFoundError) Error() string {
+	if e.AvailablePlans == nil {
+		return fmt.Sprintf("could not find service with id %s for plan %s", e.ServiceID, e.PlanID)
+	}
+
+	available := []string{}
+	for _, plan := range e.AvailablePlans {
+		available = append(available, fmt.Sprintf("%s (%s)", plan.Name, plan.ID))
+	}
+	if len(available) == 0 {
+		available = append(available, "none")
+	}
+	return fmt.Sprintf(
+		"could not find plan with id %s for service %s, available plans: %s",
+		e.PlanID, e.ServiceID, strings.Join(available, ", "),
+	)
+}
+
 func (c *Config) FindPlan(serviceId, planId string) (*Plan, error) {
 	service, err := findServiceById(serviceId, &c.Catalog)
 	if err != nil {
-		return &Plan{}, err
+		return &Plan{}, &PlanNotFoundError{ServiceID: serviceId, PlanID: planId}
 	}
 	plan, err := findPlanById(planId, service)
 	if err != nil {
-		return &Plan{}, err
+		availablePlans := service.Plans
+		if availablePlans == nil {
+			availablePlans = []Plan{}
+		}
+		return &Plan{}, &PlanNotFoundError{ServiceID: serviceId, PlanID: planId, AvailablePlans: availablePlans}
 	}
 	return &plan, nil
 }
@@ -135,3 +749,146 @@ func findPlanById(id string, service Service) (Plan, error) {
 	}
 	return Plan{}, errors.New("could not find plan with id " + id)
 }
+
+// ValidatePlans checks that the Aiven plan of every configured plan exists,
+// and is available in the plan's cloud, so that mistakes are found when the
+// broker starts rather than when someone tries to provision.
+func (c *Config) ValidatePlans(ctx context.Context, client aiven.Client) error {
+	// Plans are looked up once for each project and service type.
+	servicePlans := map[string][]aiven.ServicePlan{}
+	invalidPlans := []string{}
+
+	for _, service := range c.Catalog.Services {
+		for i := range service.Plans {
+			plan := &service.Plans[i]
+			serviceType := aivenServiceType(plan.ServiceType)
+			project := c.ProjectForPlan(plan)
+			key := project + "/" + serviceType
+
+			if _, ok := servicePlans[key]; !ok {
+				plans, err := client.GetServicePlans(ctx, &aiven.GetServicePlansInput{
+					Project:     project,
+					ServiceType: serviceType,
+				})
+				if err != nil {
+					return err
+				}
+				servicePlans[key] = plans
+			}
+
+			cloud := c.CloudForPlan(plan)
+			if !servicePlanAvailable(servicePlans[key], plan.AivenPlan, cloud) {
+				invalidPlans = append(invalidPlans, fmt.Sprintf(
+					"%s/%s (%s %s in %s)", service.Name, plan.Name, serviceType, plan.AivenPlan, cloud,
+				))
+			}
+		}
+	}
+
+	if len(invalidPlans) > 0 {
+		return fmt.Errorf("Config error: plans not available in Aiven: %s", strings.Join(invalidPlans, ", "))
+	}
+	return nil
+}
+
+// ValidateProjectVPCs checks the project VPC of each plan exists, in the
+// plan's project and cloud.
+func (c *Config) ValidateProjectVPCs(ctx context.Context, client aiven.Client) error {
+	// VPCs are looked up once for each project.
+	projectVPCs := map[string][]aiven.ProjectVPC{}
+	invalidPlans := []string{}
+
+	for _, service := range c.Catalog.Services {
+		for i := range service.Plans {
+			plan := &service.Plans[i]
+			projectVPCID := c.ProjectVPCIDForPlan(plan)
+			if projectVPCID == "" {
+				continue
+			}
+
+			project := c.ProjectForPlan(plan)
+			if _, ok := projectVPCs[project]; !ok {
+				vpcs, err := client.ListProjectVPCs(ctx, &aiven.ListProjectVPCsInput{Project: project})
+				if err != nil {
+					return err
+				}
+				projectVPCs[project] = vpcs
+			}
+
+			cloud := c.CloudForPlan(plan)
+			if !projectVPCAvailable(projectVPCs[project], projectVPCID, cloud) {
+				invalidPlans = append(invalidPlans, fmt.Sprintf(
+					"%s/%s (%s in %s)", service.Name, plan.Name, projectVPCID, cloud,
+				))
+			}
+		}
+	}
+
+	if len(invalidPlans) > 0 {
+		return fmt.Errorf("Config error: project VPCs not found in Aiven: %s", strings.Join(invalidPlans, ", "))
+	}
+	return nil
+}
+
+func projectVPCAvailable(vpcs []aiven.ProjectVPC, projectVPCID, cloud string) bool {
+	for _, vpc := range vpcs {
+		if vpc.ProjectVPCID == projectVPCID {
+			return vpc.CloudName == cloud
+		}
+	}
+	return false
+}
+
+func servicePlanAvailable(servicePlans []aiven.ServicePlan, aivenPlan, cloud string) bool {
+	for _, servicePlan := range servicePlans {
+		if servicePlan.ServicePlan == aivenPlan {
+			_, ok := servicePlan.Regions[cloud]
+			return ok
+		}
+	}
+	return false
+}
+
+// PlanMaintenanceInfo returns the maintenance_info advertised for the plan,
+// which platforms use to offer upgrades when the plan's engine version is
+// changed. Plans without an engine version have none.
+func (c *Config) PlanMaintenanceInfo(serviceID, planID string) (*brokerapi.MaintenanceInfo, error) {
+	plan, err := c.FindPlan(serviceID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	version := plan.engineVersion()
+	if version == "" {
+		return nil, nil
+	}
+	return &brokerapi.MaintenanceInfo{
+		Version:     semanticVersion(version),
+		Description: fmt.Sprintf("%s version %s", plan.ServiceType, version),
+	}, nil
+}
+
+func (p *Plan) engineVersion() string {
+	switch p.ServiceType {
+	case "elasticsearch":
+		return p.ElasticsearchVersion
+	case "kafka":
+		return p.KafkaVersion
+	case "opensearch":
+		return p.OpenSearchVersion
+	case "postgres":
+		return p.PGVersion
+	default:
+		return ""
+	}
+}
+
+// semanticVersion pads versions such as "6" or "2.4" out to the three
+// components maintenance_info versions must have.
+func semanticVersion(version string) string {
+	parts := strings.Split(version, ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts, ".")
+}
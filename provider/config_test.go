@@ -1,10 +1,17 @@
 package provider_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 
 	"github.com/alphagov/paas-aiven-broker/provider"
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+	"github.com/alphagov/paas-aiven-broker/provider/aiven/fakes"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/brokerapi"
 )
@@ -41,13 +48,16 @@ var _ = Describe("Config", func() {
 
 			elasticsearchPlanSpecificConfig := provider.PlanSpecificConfig{}
 			elasticsearchPlanSpecificConfig.AivenPlan = "startup-1"
+			elasticsearchPlanSpecificConfig.ServiceType = "elasticsearch"
 			elasticsearchPlanSpecificConfig.ElasticsearchVersion = "6"
 
 			influxDBPlanSpecificConfig := provider.PlanSpecificConfig{}
 			influxDBPlanSpecificConfig.AivenPlan = "startup-2"
+			influxDBPlanSpecificConfig.ServiceType = "influxdb"
 
 			expectedConfig := &provider.Config{
 				Cloud:             "aws-eu-west-1",
+				IPWhitelist:       []string{},
 				ServiceNamePrefix: "test",
 				APIToken:          "token",
 				Project:           "project",
@@ -83,6 +93,39 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Context("when a Kafka plan is configured", func() {
+		It("decodes the Kafka settings", func() {
+			rawConfig = json.RawMessage(`
+				{
+					"cloud": "aws-eu-west-1",
+					"catalog": {
+						"services": [
+							{
+								"id": "kafka-service",
+								"name": "kafka",
+								"plans": [{
+									"id": "kafka-plan",
+									"aiven_plan": "business-4",
+									"kafka_version": "2.4",
+									"kafka_rest": true,
+									"kafka_connect": true
+								}]
+							}
+						]
+					}
+				}
+			`)
+			config, err := provider.DecodeConfig(rawConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			plan, err := config.FindPlan("kafka-service", "kafka-plan")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.KafkaVersion).To(Equal("2.4"))
+			Expect(plan.KafkaRest).To(BeTrue())
+			Expect(plan.KafkaConnect).To(BeTrue())
+		})
+	})
+
 	Context("when there is no Catalog defined", func() {
 		It("returns an error", func() {
 			rawConfig = json.RawMessage(`{"cloud": "aws-eu-west-1"}`)
@@ -139,6 +182,47 @@ var _ = Describe("Config", func() {
 			Expect(err).To(MatchError("Config error: must provide cloud configuration. For example, 'aws-eu-west-1'"))
 		})
 
+		It("does not require a top level `cloud` if every plan has one", func() {
+			rawConfig = json.RawMessage(`
+						{
+							"catalog": {
+								"services": [
+									{
+										"name": "elasticsearch",
+										"plans": [
+											{"aiven_plan": "startup-1", "elasticsearch_version": "6", "cloud": "aws-eu-west-1"},
+											{"aiven_plan": "startup-1", "elasticsearch_version": "6", "cloud": "aws-eu-west-2"}
+										]
+									}
+								]
+							}
+						}
+					`)
+			config, err := provider.DecodeConfig(rawConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.CloudForPlan(&config.Catalog.Services[0].Plans[1])).To(Equal("aws-eu-west-2"))
+		})
+
+		It("returns an error if neither the plan nor the top level specify a `cloud`", func() {
+			rawConfig = json.RawMessage(`
+						{
+							"catalog": {
+								"services": [
+									{
+										"name": "elasticsearch",
+										"plans": [
+											{"name": "plan-a", "aiven_plan": "startup-1", "elasticsearch_version": "6", "cloud": "aws-eu-west-1"},
+											{"name": "plan-b", "aiven_plan": "startup-1", "elasticsearch_version": "6"}
+										]
+									}
+								]
+							}
+						}
+					`)
+			_, err := provider.DecodeConfig(rawConfig)
+			Expect(err).To(MatchError("Config error: plan plan-b must specify a `cloud` as there is no default cloud configured"))
+		})
+
 		It("returns an error if a plan is missing the Aiven plan details", func() {
 			rawConfig = json.RawMessage(`
 						{
@@ -177,6 +261,111 @@ var _ = Describe("Config", func() {
 			})
 		})
 
+		Context("when the plan is opensearch", func() {
+			It("returns an error if a plan is missing the OpenSearch version", func() {
+				rawConfig = json.RawMessage(`
+							{
+								"cloud": "aws-eu-west-1",
+								"catalog": {
+									"services": [
+										{
+											"name": "elasticsearch",
+											"plans": [{"aiven_plan": "plan-a", "service_type": "opensearch"}]
+										}
+									]
+								}
+							}
+						`)
+				_, err := provider.DecodeConfig(rawConfig)
+				Expect(err).To(MatchError("Config error: every opensearch plan must specify an `opensearch_version`"))
+			})
+
+			It("does not require an Elasticsearch version", func() {
+				rawConfig = json.RawMessage(`
+							{
+								"cloud": "aws-eu-west-1",
+								"catalog": {
+									"services": [
+										{
+											"name": "elasticsearch",
+											"plans": [{
+												"aiven_plan": "plan-a",
+												"service_type": "opensearch",
+												"opensearch_version": "1",
+												"upgrade_from_elasticsearch": true
+											}]
+										}
+									]
+								}
+							}
+						`)
+				config, err := provider.DecodeConfig(rawConfig)
+				Expect(err).NotTo(HaveOccurred())
+				plan := config.Catalog.Services[0].Plans[0]
+				Expect(plan.ServiceType).To(Equal("opensearch"))
+				Expect(plan.OpenSearchVersion).To(Equal("1"))
+				Expect(plan.UpgradeFromElasticsearch).To(BeTrue())
+			})
+		})
+
+		Context("when the plan does not specify a service type", func() {
+			It("defaults to the service name when it is a known service type", func() {
+				rawConfig = json.RawMessage(`
+							{
+								"cloud": "aws-eu-west-1",
+								"catalog": {
+									"services": [
+										{
+											"name": "kafka",
+											"plans": [{"aiven_plan": "plan-a"}]
+										}
+									]
+								}
+							}
+						`)
+				config, err := provider.DecodeConfig(rawConfig)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Catalog.Services[0].Plans[0].ServiceType).To(Equal("kafka"))
+			})
+
+			It("defaults to elasticsearch otherwise", func() {
+				rawConfig = json.RawMessage(`
+							{
+								"cloud": "aws-eu-west-1",
+								"catalog": {
+									"services": [
+										{
+											"name": "search",
+											"plans": [{"aiven_plan": "plan-a", "elasticsearch_version": "6"}]
+										}
+									]
+								}
+							}
+						`)
+				config, err := provider.DecodeConfig(rawConfig)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Catalog.Services[0].Plans[0].ServiceType).To(Equal("elasticsearch"))
+			})
+		})
+
+		It("returns an error if a plan has an unknown service type", func() {
+			rawConfig = json.RawMessage(`
+						{
+							"cloud": "aws-eu-west-1",
+							"catalog": {
+								"services": [
+									{
+										"name": "elasticsearch",
+										"plans": [{"name": "plan-a", "aiven_plan": "startup-1", "service_type": "mongodb"}]
+									}
+								]
+							}
+						}
+					`)
+			_, err := provider.DecodeConfig(rawConfig)
+			Expect(err).To(MatchError("Config error: plan plan-a has unknown service type mongodb"))
+		})
+
 		Context("when the service is InfluxDB", func() {
 			It("does not care about the Elasticsearch version", func() {
 				rawConfig = json.RawMessage(`
@@ -197,4 +386,789 @@ var _ = Describe("Config", func() {
 			})
 		})
 	})
+
+	Describe("the IP whitelist", func() {
+		whitelistConfig := func(ipWhitelist string) json.RawMessage {
+			return json.RawMessage(`
+				{
+					"cloud": "aws-eu-west-1",
+					` + ipWhitelist + `
+					"catalog": {
+						"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+					}
+				}
+			`)
+		}
+
+		BeforeEach(func() {
+			os.Setenv("IP_WHITELIST", "5.6.7.8")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("IP_WHITELIST")
+		})
+
+		It("parses the whitelist from the config", func() {
+			config, err := provider.DecodeConfig(whitelistConfig(`"ip_whitelist": ["1.2.3.4", " 10.1.2.3/8"],`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.IPWhitelist).To(Equal([]string{"1.2.3.4/32", "10.0.0.0/8"}))
+		})
+
+		It("reads the whitelist from IP_WHITELIST when the config has none", func() {
+			config, err := provider.DecodeConfig(whitelistConfig(``))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.IPWhitelist).To(Equal([]string{"5.6.7.8/32"}))
+		})
+
+		It("prefers an empty whitelist in the config to IP_WHITELIST", func() {
+			config, err := provider.DecodeConfig(whitelistConfig(`"ip_whitelist": [],`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.IPWhitelist).To(BeEmpty())
+		})
+
+		It("returns an error if the whitelist in the config is malformed", func() {
+			_, err := provider.DecodeConfig(whitelistConfig(`"ip_whitelist": ["1.2.3.4", "999.1.1.1"],`))
+			Expect(err).To(MatchError("Config error: malformed whitelist IP: 999.1.1.1"))
+		})
+
+		It("parses the retired whitelist", func() {
+			config, err := provider.DecodeConfig(whitelistConfig(`"retired_ip_whitelist": ["9.8.7.6"],`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.RetiredIPWhitelist).To(Equal([]string{"9.8.7.6/32"}))
+
+			_, err = provider.DecodeConfig(whitelistConfig(`"retired_ip_whitelist": ["999.1.1.1"],`))
+			Expect(err).To(MatchError("Config error: retired_ip_whitelist: malformed whitelist IP: 999.1.1.1"))
+		})
+
+		It("allows all addresses when ip_whitelist_allow_all is set", func() {
+			config, err := provider.DecodeConfig(whitelistConfig(`"ip_whitelist_allow_all": true,`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.IPWhitelist).To(Equal([]string{"0.0.0.0/0"}))
+		})
+
+		It("returns an error if allowing all addresses is forbidden", func() {
+			_, err := provider.DecodeConfig(whitelistConfig(`"ip_whitelist_allow_all": true, "forbid_allow_all_ips": true,`))
+			Expect(err).To(MatchError("Config error: the IP whitelist allows all IP addresses but forbid_allow_all_ips is set"))
+
+			_, err = provider.DecodeConfig(whitelistConfig(`"ip_whitelist": ["1.2.3.4", "0.0.0.0/0"], "forbid_allow_all_ips": true,`))
+			Expect(err).To(MatchError("Config error: the IP whitelist allows all IP addresses but forbid_allow_all_ips is set"))
+		})
+
+		It("parses the whitelist groups", func() {
+			config, err := provider.DecodeConfig(whitelistConfig(`"whitelist_groups": {"office": ["1.2.3.0/24", "1.2.4.5"]},`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.WhitelistGroups).To(Equal(map[string][]string{"office": {"1.2.3.0/24", "1.2.4.5/32"}}))
+		})
+
+		It("returns an error if a whitelist group is malformed", func() {
+			_, err := provider.DecodeConfig(whitelistConfig(`"whitelist_groups": {"office": ["1.2.3.0/24", "1.2.3"]},`))
+			Expect(err).To(MatchError("Config error: whitelist group office: malformed whitelist IP: 1.2.3"))
+		})
+
+		It("returns an error if IP_WHITELIST is malformed", func() {
+			os.Setenv("IP_WHITELIST", "1.2.3.4,a.b.c.d")
+			_, err := provider.DecodeConfig(whitelistConfig(``))
+			Expect(err).To(MatchError("Config error: malformed whitelist IP: a.b.c.d"))
+		})
+	})
+
+	It("returns an error if the ip_filter limit is negative", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"ip_filter_max_entries": -1,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: ip_filter_max_entries cannot be negative"))
+	})
+
+	It("returns an error if the bind availability timeout is negative", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"bind_availability_timeout_seconds": -1,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: bind_availability_timeout_seconds cannot be negative"))
+	})
+
+	It("returns an error if the bind availability check is skipped for an unknown service type", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"skip_bind_availability_check": ["mongodb"],
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: skip_bind_availability_check has unknown service type mongodb"))
+	})
+
+	Describe("service name prefix", func() {
+		prefixConfig := json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		var originalPrefix string
+
+		BeforeEach(func() {
+			originalPrefix = os.Getenv("SERVICE_NAME_PREFIX")
+		})
+
+		AfterEach(func() {
+			os.Setenv("SERVICE_NAME_PREFIX", originalPrefix)
+		})
+
+		It("accepts a prefix which fits alongside an instance GUID", func() {
+			os.Setenv("SERVICE_NAME_PREFIX", "Prod-Env-abcdefghijklmnopqr")
+
+			config, err := provider.DecodeConfig(prefixConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.ServiceNamePrefix).To(Equal("Prod-Env-abcdefghijklmnopqr"))
+		})
+
+		It("returns an error if the prefix is too long to fit alongside an instance GUID", func() {
+			os.Setenv("SERVICE_NAME_PREFIX", "prod-env-abcdefghijklmnopqrs")
+
+			_, err := provider.DecodeConfig(prefixConfig)
+			Expect(err).To(MatchError("Config error: service name prefix cannot be longer than 27 characters"))
+		})
+
+		It("returns an error if the prefix would make an invalid service name", func() {
+			for _, prefix := range []string{"prod_env", "1env", "-env", "env.prod"} {
+				os.Setenv("SERVICE_NAME_PREFIX", prefix)
+
+				_, err := provider.DecodeConfig(prefixConfig)
+				Expect(err).To(MatchError(
+					"Config error: service name prefix must start with a letter, and only contain letters, digits and hyphens",
+				), prefix)
+			}
+		})
+	})
+
+	It("returns an error if a plan's default additional disk space is more than tenants can choose", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [
+						{"name": "big", "aiven_plan": "startup-2", "additional_disk_space_gb": 20, "max_additional_disk_space_gb": 10}
+					]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: plan big has additional_disk_space_gb larger than its max_additional_disk_space_gb"))
+	})
+
+	It("returns an error if a plan has a feature its service type does not have", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"catalog": {
+					"services": [{"name": "elasticsearch", "plans": [
+						{"name": "tiny", "aiven_plan": "startup-4", "elasticsearch_version": "7", "features": {"kibana": true}}
+					]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: plan tiny has unknown feature kibana, elasticsearch plans have: kibana_enabled"))
+	})
+
+	DescribeTable("returns an error if a plan has an invalid backup time",
+		func(plan, message string) {
+			rawConfig = json.RawMessage(`
+				{
+					"cloud": "aws-eu-west-1",
+					"catalog": {
+						"services": [{"name": "postgres", "plans": [` + plan + `]}]
+					}
+				}
+			`)
+
+			_, err := provider.DecodeConfig(rawConfig)
+			Expect(err).To(MatchError(message))
+		},
+		Entry("with an hour out of range",
+			`{"name": "prod", "aiven_plan": "business-4", "pg_version": "14", "backup_hour": 24, "backup_minute": 0}`,
+			"Config error: plan prod has backup_hour 24, which must be between 0 and 23",
+		),
+		Entry("with a negative hour",
+			`{"name": "prod", "aiven_plan": "business-4", "pg_version": "14", "backup_hour": -1, "backup_minute": 0}`,
+			"Config error: plan prod has backup_hour -1, which must be between 0 and 23",
+		),
+		Entry("with a minute out of range",
+			`{"name": "prod", "aiven_plan": "business-4", "pg_version": "14", "backup_hour": 2, "backup_minute": 60}`,
+			"Config error: plan prod has backup_minute 60, which must be between 0 and 59",
+		),
+		Entry("with only an hour",
+			`{"name": "prod", "aiven_plan": "business-4", "pg_version": "14", "backup_hour": 2}`,
+			"Config error: plan prod must have both a backup_hour and a backup_minute, or neither",
+		),
+		Entry("for a service type without backup times",
+			`{"name": "prod", "aiven_plan": "business-4", "service_type": "kafka", "kafka_version": "3.2", "backup_hour": 2, "backup_minute": 0}`,
+			"Config error: plan prod cannot have a backup time, only postgres and redis plans can",
+		),
+	)
+
+	It("accepts a backup time at midnight", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"catalog": {
+					"services": [{"name": "postgres", "plans": [
+						{"name": "prod", "aiven_plan": "business-4", "pg_version": "14", "backup_hour": 0, "backup_minute": 0}
+					]}]
+				}
+			}
+		`)
+
+		config, err := provider.DecodeConfig(rawConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*config.Catalog.Services[0].Plans[0].BackupHour).To(Equal(0))
+	})
+
+	DescribeTable("returns an error for invalid allowed versions",
+		func(plan, expectedError string) {
+			rawConfig = json.RawMessage(fmt.Sprintf(`
+				{
+					"cloud": "aws-eu-west-1",
+					"catalog": {"services": [{"name": "service", "plans": [%s]}]}
+				}
+			`, plan))
+
+			_, err := provider.DecodeConfig(rawConfig)
+			Expect(err).To(MatchError(expectedError))
+		},
+		Entry("without a version",
+			`{"name": "small", "aiven_plan": "startup-2", "service_type": "influxdb", "allowed_versions": ["2"]}`,
+			"Config error: plan small cannot have allowed_versions, as influxdb plans have no version",
+		),
+		Entry("malformed",
+			`{"name": "small", "aiven_plan": "startup-2", "service_type": "postgres", "pg_version": "13", "allowed_versions": ["14-beta"]}`,
+			"Config error: plan small has invalid allowed version 14-beta",
+		),
+		Entry("older than the plan's",
+			`{"name": "small", "aiven_plan": "startup-2", "elasticsearch_version": "7.10", "allowed_versions": ["7"]}`,
+			"Config error: plan small allows version 7, which is older than its own version 7.10",
+		),
+	)
+
+	It("returns an error if plans in the same project have different billing groups", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"billing_group_id": "development",
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [
+						{"name": "small", "aiven_plan": "startup-2"},
+						{"name": "large", "aiven_plan": "business-4", "billing_group_id": "production"}
+					]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError(
+			"Config error: plans small and large have different billing groups, but are in the same project, and Aiven bills by project",
+		))
+	})
+
+	It("allows plans in different projects to have different billing groups", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"billing_group_id": "development",
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [
+						{"name": "small", "aiven_plan": "startup-2"},
+						{"name": "large", "aiven_plan": "business-4", "project": "production", "billing_group_id": "production"}
+					]}]
+				}
+			}
+		`)
+
+		config, err := provider.DecodeConfig(rawConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.BillingGroupIDForPlan(&config.Catalog.Services[0].Plans[1])).To(Equal("production"))
+	})
+
+	It("returns an error if the clone source policy is unknown", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"clone_source_policy": "anyone",
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: clone_source_policy must be same_organization or same_space"))
+	})
+
+	It("returns an error if the disk usage warning is not a percentage", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"disk_usage_warning_percent": 101,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: disk_usage_warning_percent must be between 0 and 100"))
+	})
+
+	It("returns an error if the Aiven retry settings are negative", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"aiven_max_attempts": -1,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: aiven_max_attempts cannot be negative"))
+	})
+
+	It("returns an error if the Aiven rate limit is negative", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"aiven_requests_per_second": -5,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: aiven_requests_per_second cannot be negative"))
+	})
+
+	It("returns an error if an Aiven request timeout is negative", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"aiven_dial_timeout_seconds": -1,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: aiven_dial_timeout_seconds cannot be negative"))
+	})
+
+	It("returns an error if an operation timeout is negative", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"update_timeout_seconds": -1,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: update_timeout_seconds cannot be negative"))
+	})
+
+	It("returns an error if stuck provisions are deleted without a provision timeout", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"delete_stuck_provisions": true,
+				"provision_timeout_seconds": 0,
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: delete_stuck_provisions requires a provision_timeout_seconds"))
+	})
+
+	It("returns an error if the user config allow-list has an unknown service type", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"user_config_allow_list": {"mongodb": ["mongodb"]},
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: user_config_allow_list has unknown service type mongodb"))
+	})
+
+	It("returns an error if the user config allow-list includes a key the broker manages", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"user_config_allow_list": {"postgres": ["pg", "pg_version"]},
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: user_config_allow_list for postgres cannot include pg_version, as the broker manages it"))
+	})
+
+	It("returns an error if the credhub config is incomplete", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"credhub": {"url": "https://credhub.example.com", "uaa_url": "https://uaa.example.com"},
+				"catalog": {
+					"services": [{"name": "influxdb", "plans": [{"aiven_plan": "startup-2"}]}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: credhub must specify a `url`, `uaa_url`, `uaa_client_name` and `uaa_client_secret`"))
+	})
+
+	It("returns an error if a plan allows updates to an unknown plan", func() {
+		rawConfig = json.RawMessage(`
+			{
+				"cloud": "aws-eu-west-1",
+				"catalog": {
+					"services": [{
+						"id": "postgres-service",
+						"name": "postgres",
+						"plans": [
+							{"id": "small", "name": "small", "aiven_plan": "startup-4", "allowed_updates_to": ["large"]},
+							{"id": "large", "name": "large", "aiven_plan": "startup-8", "allowed_updates_to": ["huge"]}
+						]
+					}]
+				}
+			}
+		`)
+
+		_, err := provider.DecodeConfig(rawConfig)
+		Expect(err).To(MatchError("Config error: plan large allows updates to unknown plan huge"))
+	})
+
+	Describe("FindPlan", func() {
+		BeforeEach(func() {
+			rawConfig = json.RawMessage(`
+				{
+					"cloud": "aws-eu-west-1",
+					"catalog": {
+						"services": [
+							{
+								"id": "elasticsearch-service",
+								"name": "elasticsearch",
+								"plans": [{
+									"id": "elasticsearch-plan",
+									"name": "basic",
+									"aiven_plan": "startup-1",
+									"elasticsearch_version": "6"
+								}]
+							},
+							{
+								"id": "influxdb-service",
+								"name": "influxdb",
+								"plans": [{
+									"id": "influxdb-plan",
+									"aiven_plan": "startup-2"
+								}]
+							}
+						]
+					}
+				}
+			`)
+		})
+
+		It("finds an InfluxDB plan alongside Elasticsearch plans", func() {
+			config, err := provider.DecodeConfig(rawConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			plan, err := config.FindPlan("influxdb-service", "influxdb-plan")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plan.ID).To(Equal("influxdb-plan"))
+			Expect(plan.AivenPlan).To(Equal("startup-2"))
+			Expect(plan.ElasticsearchVersion).To(BeEmpty())
+		})
+
+		It("does not find a plan under the wrong service", func() {
+			config, err := provider.DecodeConfig(rawConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = config.FindPlan("elasticsearch-service", "influxdb-plan")
+			Expect(err).To(MatchError(
+				"could not find plan with id influxdb-plan for service elasticsearch-service, available plans: basic (elasticsearch-plan)",
+			))
+		})
+
+		It("returns the plans available for the service", func() {
+			config, err := provider.DecodeConfig(rawConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = config.FindPlan("influxdb-service", "unknown-plan")
+			notFound, ok := err.(*provider.PlanNotFoundError)
+			Expect(ok).To(BeTrue())
+			Expect(notFound.ServiceID).To(Equal("influxdb-service"))
+			Expect(notFound.PlanID).To(Equal("unknown-plan"))
+			Expect(notFound.AvailablePlans).To(HaveLen(1))
+			Expect(notFound.AvailablePlans[0].ID).To(Equal("influxdb-plan"))
+		})
+
+		It("names the plan when the service is unknown", func() {
+			config, err := provider.DecodeConfig(rawConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = config.FindPlan("unknown-service", "influxdb-plan")
+			Expect(err).To(MatchError("could not find service with id unknown-service for plan influxdb-plan"))
+		})
+	})
+	Describe("PlanMaintenanceInfo", func() {
+		var config *provider.Config
+
+		BeforeEach(func() {
+			var err error
+			config, err = provider.DecodeConfig(json.RawMessage(`
+				{
+					"cloud": "aws-eu-west-1",
+					"catalog": {
+						"services": [
+							{
+								"id": "elasticsearch-service",
+								"name": "elasticsearch",
+								"plans": [{"id": "elasticsearch-plan", "aiven_plan": "startup-1", "elasticsearch_version": "6"}]
+							},
+							{
+								"id": "kafka-service",
+								"name": "kafka",
+								"plans": [{"id": "kafka-plan", "aiven_plan": "business-4", "kafka_version": "2.4"}]
+							},
+							{
+								"id": "influxdb-service",
+								"name": "influxdb",
+								"plans": [{"id": "influxdb-plan", "aiven_plan": "startup-2"}]
+							}
+						]
+					}
+				}
+			`))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("derives a semantic version from the engine version", func() {
+			maintenanceInfo, err := config.PlanMaintenanceInfo("elasticsearch-service", "elasticsearch-plan")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(maintenanceInfo).To(Equal(&brokerapi.MaintenanceInfo{
+				Version:     "6.0.0",
+				Description: "elasticsearch version 6",
+			}))
+
+			maintenanceInfo, err = config.PlanMaintenanceInfo("kafka-service", "kafka-plan")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(maintenanceInfo.Version).To(Equal("2.4.0"))
+		})
+
+		It("has none for plans without an engine version", func() {
+			maintenanceInfo, err := config.PlanMaintenanceInfo("influxdb-service", "influxdb-plan")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(maintenanceInfo).To(BeNil())
+		})
+
+		It("returns an error for an unknown plan", func() {
+			_, err := config.PlanMaintenanceInfo("elasticsearch-service", "unknown-plan")
+			Expect(err).To(MatchError(ContainSubstring("could not find plan with id unknown-plan")))
+		})
+	})
+	Describe("ValidatePlans", func() {
+		var (
+			config          *provider.Config
+			fakeAivenClient *fakes.FakeClient
+		)
+
+		BeforeEach(func() {
+			var err error
+			config, err = provider.DecodeConfig(json.RawMessage(`
+				{
+					"cloud": "aws-eu-west-1",
+					"catalog": {
+						"services": [
+							{
+								"id": "postgres-service",
+								"name": "postgres",
+								"plans": [
+									{"id": "small", "name": "small", "aiven_plan": "startup-4", "pg_version": "12"},
+									{"id": "london", "name": "london", "aiven_plan": "startup-4", "pg_version": "12", "cloud": "aws-eu-west-2"}
+								]
+							},
+							{
+								"id": "redis-service",
+								"name": "redis",
+								"plans": [{"id": "redis", "name": "redis", "aiven_plan": "startup-4x"}]
+							}
+						]
+					}
+				}
+			`))
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeAivenClient = &fakes.FakeClient{}
+			fakeAivenClient.GetServicePlansStub = func(_ context.Context, input *aiven.GetServicePlansInput) ([]aiven.ServicePlan, error) {
+				return []aiven.ServicePlan{{
+					ServicePlan: "startup-4",
+					Regions: map[string]interface{}{
+						"aws-eu-west-1": map[string]interface{}{},
+						"aws-eu-west-2": map[string]interface{}{},
+					},
+				}}, nil
+			}
+		})
+
+		It("succeeds when every plan is available", func() {
+			config.Catalog.Services[1].Plans[0].AivenPlan = "startup-4"
+
+			Expect(config.ValidatePlans(context.Background(), fakeAivenClient)).To(Succeed())
+		})
+
+		It("looks up the plans once per Aiven service type", func() {
+			config.ValidatePlans(context.Background(), fakeAivenClient)
+
+			Expect(fakeAivenClient.GetServicePlansCallCount()).To(Equal(2))
+			Expect(aivenInput(fakeAivenClient.GetServicePlansArgsForCall(0)).ServiceType).To(Equal("pg"))
+			Expect(aivenInput(fakeAivenClient.GetServicePlansArgsForCall(1)).ServiceType).To(Equal("redis"))
+		})
+
+		It("looks up the plans in the project of each plan", func() {
+			config.Project = "default-project"
+			config.Catalog.Services[0].Plans[1].Project = "other-project"
+
+			config.ValidatePlans(context.Background(), fakeAivenClient)
+
+			Expect(fakeAivenClient.GetServicePlansCallCount()).To(Equal(3))
+			Expect(aivenInput(fakeAivenClient.GetServicePlansArgsForCall(0)).Project).To(Equal("default-project"))
+			Expect(aivenInput(fakeAivenClient.GetServicePlansArgsForCall(1)).Project).To(Equal("other-project"))
+		})
+
+		It("lists every plan which is not available", func() {
+			config.Catalog.Services[0].Plans[1].Cloud = "google-europe-west1"
+
+			err := config.ValidatePlans(context.Background(), fakeAivenClient)
+
+			Expect(err).To(MatchError(
+				"Config error: plans not available in Aiven: " +
+					"postgres/london (pg startup-4 in google-europe-west1), " +
+					"redis/redis (redis startup-4x in aws-eu-west-1)",
+			))
+		})
+
+		It("returns an error if the plans cannot be fetched", func() {
+			fakeAivenClient.GetServicePlansStub = nil
+			fakeAivenClient.GetServicePlansReturns(nil, errors.New("some-error"))
+
+			Expect(config.ValidatePlans(context.Background(), fakeAivenClient)).To(MatchError("some-error"))
+		})
+	})
+
+	Describe("ValidateProjectVPCs", func() {
+		var (
+			config          *provider.Config
+			fakeAivenClient *fakes.FakeClient
+		)
+
+		BeforeEach(func() {
+			var err error
+			config, err = provider.DecodeConfig(json.RawMessage(`
+				{
+					"cloud": "aws-eu-west-1",
+					"project_vpc_id": "vpc-ireland",
+					"catalog": {
+						"services": [
+							{
+								"id": "postgres-service",
+								"name": "postgres",
+								"plans": [
+									{"id": "small", "name": "small", "aiven_plan": "startup-4", "pg_version": "12"},
+									{"id": "london", "name": "london", "aiven_plan": "startup-4", "pg_version": "12", "cloud": "aws-eu-west-2", "project_vpc_id": "vpc-london"}
+								]
+							}
+						]
+					}
+				}
+			`))
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeAivenClient = &fakes.FakeClient{}
+			fakeAivenClient.ListProjectVPCsReturns([]aiven.ProjectVPC{
+				{ProjectVPCID: "vpc-ireland", CloudName: "aws-eu-west-1"},
+				{ProjectVPCID: "vpc-london", CloudName: "aws-eu-west-2"},
+			}, nil)
+		})
+
+		It("succeeds when every plan's VPC is in its cloud", func() {
+			Expect(config.ValidateProjectVPCs(context.Background(), fakeAivenClient)).To(Succeed())
+			Expect(fakeAivenClient.ListProjectVPCsCallCount()).To(Equal(1))
+		})
+
+		It("does not look up VPCs when no plan uses one", func() {
+			config.ProjectVPCID = ""
+			config.Catalog.Services[0].Plans[1].ProjectVPCID = ""
+
+			Expect(config.ValidateProjectVPCs(context.Background(), fakeAivenClient)).To(Succeed())
+			Expect(fakeAivenClient.ListProjectVPCsCallCount()).To(Equal(0))
+		})
+
+		It("lists every plan whose VPC does not exist, or is in another cloud", func() {
+			config.ProjectVPCID = "vpc-unknown"
+			config.Catalog.Services[0].Plans[1].ProjectVPCID = "vpc-ireland"
+
+			err := config.ValidateProjectVPCs(context.Background(), fakeAivenClient)
+
+			Expect(err).To(MatchError(
+				"Config error: project VPCs not found in Aiven: " +
+					"postgres/small (vpc-unknown in aws-eu-west-1), " +
+					"postgres/london (vpc-ireland in aws-eu-west-2)",
+			))
+		})
+
+		It("returns an error if the VPCs cannot be fetched", func() {
+			fakeAivenClient.ListProjectVPCsReturns(nil, errors.New("some-error"))
+
+			Expect(config.ValidateProjectVPCs(context.Background(), fakeAivenClient)).To(MatchError("some-error"))
+		})
+	})
 })
@@ -3,14 +3,43 @@ package provider
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
 type CommonCredentials struct {
 	URI      string `json:"uri"`
 	Hostname string `json:"hostname"`
-	Port     string `json:"port"`
+	Port     int    `json:"port"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// Scheme is the scheme of the URI, and TLS is always true as every
+	// service only accepts encrypted connections.
+	Scheme string `json:"scheme"`
+	TLS    bool   `json:"tls"`
+	// Version is the major version of the service's engine, if it has one.
+	Version string `json:"version,omitempty"`
+	// CACertificate is the PEM encoded CA certificate which clients can use
+	// to verify the service.
+	CACertificate string `json:"ca_certificate,omitempty"`
+	// The read endpoint is the service's read replica if it has one, and
+	// otherwise the same as the primary.
+	ReadURI      string `json:"read_uri,omitempty"`
+	ReadHostname string `json:"read_hostname,omitempty"`
+	ReadPort     int    `json:"read_port,omitempty"`
+	// PublicURI and PrivateURI are the service's public and private
+	// endpoints, when it is in a project VPC and has public access. URI is
+	// the public one.
+	PublicURI  string `json:"public_uri,omitempty"`
+	PrivateURI string `json:"private_uri,omitempty"`
+	// StaticIPs are the addresses the service connects out from, for
+	// instances provisioned with static_ips.
+	StaticIPs []string `json:"static_ips,omitempty"`
+	// ExpiresAt is when the user of a binding with a ttl is deleted.
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+type ElasticsearchCredentials struct {
+	KibanaURI string `json:"kibana_uri,omitempty"`
 }
 
 type InfluxDBPrometheusBasicAuthCredentials struct {
@@ -38,10 +67,78 @@ type InfluxDBCredentials struct {
 	InfluxDBDatabase   string                         `json:"database,omitempty"`
 }
 
+type KafkaCredentials struct {
+	KafkaHosts            []string `json:"hosts,omitempty"`
+	KafkaSASLMechanism    string   `json:"sasl_mechanism,omitempty"`
+	KafkaSecurityProtocol string   `json:"security_protocol,omitempty"`
+	// Clients which authenticate with a client certificate instead of SASL
+	// connect to the SSL hosts.
+	KafkaSSLHosts   []string `json:"ssl_hosts,omitempty"`
+	KafkaAccessCert string   `json:"access_cert,omitempty"`
+	KafkaAccessKey  string   `json:"access_key,omitempty"`
+}
+
+type OpenSearchCredentials struct {
+	OpenSearchDashboardsURI string `json:"dashboards_uri,omitempty"`
+}
+
+type PostgresCredentials struct {
+	PostgresDatabase string `json:"dbname,omitempty"`
+	PostgresSSLMode  string `json:"sslmode,omitempty"`
+	// DirectURI connects to the database rather than the connection pool of
+	// pooled bindings.
+	PostgresDirectURI string `json:"direct_uri,omitempty"`
+}
+
+type PrometheusCredentials struct {
+	PrometheusMetricsPath string `json:"metrics_path,omitempty"`
+}
+
+// DashboardsCredentials are returned to bindings with dashboards access,
+// which can only log in to Kibana or OpenSearch Dashboards, so they are not
+// given the URI of the API.
+type DashboardsCredentials struct {
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	KibanaURI     string `json:"kibana_uri,omitempty"`
+	DashboardsURI string `json:"dashboards_uri,omitempty"`
+	CACertificate string `json:"ca_certificate,omitempty"`
+	ExpiresAt     string `json:"expires_at,omitempty"`
+}
+
+func dashboardsCredentials(credentials Credentials) DashboardsCredentials {
+	return DashboardsCredentials{
+		Username:      credentials.Username,
+		Password:      credentials.Password,
+		KibanaURI:     credentials.KibanaURI,
+		DashboardsURI: credentials.OpenSearchDashboardsURI,
+		CACertificate: credentials.CACertificate,
+		ExpiresAt:     credentials.ExpiresAt,
+	}
+}
+
+// AivenMetadata describes the Aiven service a binding connects to, for
+// tooling which manages many bindings.
+type AivenMetadata struct {
+	Project     string `json:"project"`
+	ServiceName string `json:"service_name"`
+	ServiceType string `json:"service_type"`
+	Cloud       string `json:"cloud"`
+	Plan        string `json:"plan"`
+}
+
 type Credentials struct {
 	CommonCredentials
 
+	// Aiven is only included if the config turns it on.
+	Aiven *AivenMetadata `json:"aiven,omitempty"`
+
+	ElasticsearchCredentials
 	InfluxDBCredentials
+	KafkaCredentials
+	OpenSearchCredentials
+	PostgresCredentials
+	PrometheusCredentials
 }
 
 func BuildCredentials(
@@ -53,13 +150,16 @@ func BuildCredentials(
 ) (Credentials, error) {
 	credentials := Credentials{}
 
-	credentials.URI = (&url.URL{
-		Scheme: "https",
-		User:   url.UserPassword(username, password),
-		Host:   fmt.Sprintf("%s:%s", hostname, port),
-	}).String()
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("Invalid port %s", port)
+	}
+
+	credentials.URI = buildURI("https", username, password, hostname, port).String()
+	credentials.Scheme = "https"
+	credentials.TLS = true
 
-	credentials.Port = port
+	credentials.Port = portNumber
 	credentials.Hostname = hostname
 	credentials.Username = username
 	credentials.Password = password
@@ -68,6 +168,14 @@ func BuildCredentials(
 		// nothing to do
 	} else if serviceType == "influxdb" {
 		addInfluxDBCredentials(&credentials)
+	} else if serviceType == "kafka" {
+		addKafkaCredentials(&credentials)
+	} else if serviceType == "opensearch" {
+		// nothing to do
+	} else if serviceType == "pg" {
+		addPostgresCredentials(&credentials)
+	} else if serviceType == "redis" {
+		addRedisCredentials(&credentials)
 	} else {
 		return Credentials{}, fmt.Errorf("Unknown service type %s", serviceType)
 	}
@@ -75,13 +183,46 @@ func BuildCredentials(
 	return credentials, nil
 }
 
+func buildURI(scheme, username, password, hostname, port string) *url.URL {
+	return &url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(username, password),
+		Host:   fmt.Sprintf("%s:%s", hostname, port),
+	}
+}
+
+func addKibanaCredentials(credentials *Credentials, hostname, port string) {
+	credentials.KibanaURI = buildURI(
+		"https",
+		credentials.Username, credentials.Password,
+		hostname, port,
+	).String()
+}
+
+func addReadCredentials(credentials *Credentials, hostname, port string) error {
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("Invalid port %s", port)
+	}
+	uri, err := url.Parse(credentials.URI)
+	if err != nil {
+		return err
+	}
+	uri.Host = fmt.Sprintf("%s:%s", hostname, port)
+
+	credentials.ReadURI = uri.String()
+	credentials.ReadHostname = hostname
+	credentials.ReadPort = portNumber
+	return nil
+}
+
 func addInfluxDBCredentials(credentials *Credentials) {
 	remoteReadURL := fmt.Sprintf(
-		"https://%s:%s/api/v1/prom/read?db=defaultdb",
+		"https://%s:%d/api/v1/prom/read?db=defaultdb",
 		credentials.Hostname, credentials.Port,
 	)
 	remoteWriteURL := fmt.Sprintf(
-		"https://%s:%s/api/v1/prom/write?db=defaultdb",
+		"https://%s:%d/api/v1/prom/write?db=defaultdb",
 		credentials.Hostname, credentials.Port,
 	)
 
@@ -106,3 +247,90 @@ func addInfluxDBCredentials(credentials *Credentials) {
 	}
 	credentials.InfluxDBDatabase = "defaultdb"
 }
+
+func addKafkaCredentials(credentials *Credentials) {
+	credentials.URI = buildURI(
+		"kafka",
+		credentials.Username, credentials.Password,
+		credentials.Hostname, strconv.Itoa(credentials.Port),
+	).String()
+	credentials.Scheme = "kafka"
+	credentials.KafkaHosts = []string{
+		fmt.Sprintf("%s:%d", credentials.Hostname, credentials.Port),
+	}
+	credentials.KafkaSASLMechanism = "SCRAM-SHA-256"
+	credentials.KafkaSecurityProtocol = "SASL_SSL"
+}
+
+func addKafkaCertificateCredentials(credentials *Credentials, hostname, port, accessCert, accessKey string) {
+	credentials.KafkaSSLHosts = []string{fmt.Sprintf("%s:%s", hostname, port)}
+	credentials.KafkaAccessCert = accessCert
+	credentials.KafkaAccessKey = accessKey
+}
+
+func addOpenSearchDashboardsCredentials(credentials *Credentials, hostname, port string) {
+	credentials.OpenSearchDashboardsURI = buildURI(
+		"https",
+		credentials.Username, credentials.Password,
+		hostname, port,
+	).String()
+}
+
+func addPostgresCredentials(credentials *Credentials) {
+	credentials.PostgresDatabase = "defaultdb"
+	credentials.PostgresSSLMode = "require"
+	setPostgresURI(credentials)
+}
+
+// setPostgresDatabase points the credentials at another database than
+// defaultdb.
+func setPostgresDatabase(credentials *Credentials, database string) error {
+	credentials.PostgresDatabase = database
+	setPostgresURI(credentials)
+	if credentials.ReadURI == "" {
+		return nil
+	}
+	readURI, err := url.Parse(credentials.ReadURI)
+	if err != nil {
+		return err
+	}
+	readURI.Path = "/" + database
+	credentials.ReadURI = readURI.String()
+	return nil
+}
+
+// addPooledCredentials points the credentials at the binding's connection
+// pool, which clients connect to as if it were a database.
+func addPooledCredentials(credentials *Credentials, hostname, port, poolName string) error {
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("Invalid port %s", port)
+	}
+	credentials.PostgresDirectURI = credentials.URI
+	credentials.Hostname = hostname
+	credentials.Port = portNumber
+	credentials.PostgresDatabase = poolName
+	setPostgresURI(credentials)
+	return nil
+}
+
+func setPostgresURI(credentials *Credentials) {
+	uri := buildURI(
+		"postgres",
+		credentials.Username, credentials.Password,
+		credentials.Hostname, strconv.Itoa(credentials.Port),
+	)
+	uri.Path = "/" + credentials.PostgresDatabase
+	uri.RawQuery = url.Values{"sslmode": []string{credentials.PostgresSSLMode}}.Encode()
+	credentials.URI = uri.String()
+	credentials.Scheme = "postgres"
+}
+
+func addRedisCredentials(credentials *Credentials) {
+	credentials.URI = buildURI(
+		"rediss",
+		credentials.Username, credentials.Password,
+		credentials.Hostname, strconv.Itoa(credentials.Port),
+	).String()
+	credentials.Scheme = "rediss"
+}
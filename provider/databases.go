@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/alphagov/paas-aiven-broker/client/postgres"
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+const (
+	maxDatabaseNameLength = 63
+	sharedDatabase        = "defaultdb"
+
+	// Pools are the size Aiven defaults to unless bindings ask otherwise.
+	DefaultPoolSize = 10
+	MinPoolSize     = 1
+	MaxPoolSize     = 1000
+	poolMode        = "transaction"
+	poolNameSuffix  = "-pool"
+)
+
+var notDatabaseNameCharacters = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// bindingDatabaseName names the database of a binding with own_database
+// after its user, so that Unbind and GetBinding can find it from the user.
+func bindingDatabaseName(username string) string {
+	name := notDatabaseNameCharacters.ReplaceAllString(username, "_")
+	if len(name) > maxDatabaseNameLength {
+		name = name[:maxDatabaseNameLength]
+	}
+	return name
+}
+
+// bindingPoolName names the connection pool of a pooled binding after its
+// user, in the same way as its database.
+func bindingPoolName(username string) string {
+	name := notDatabaseNameCharacters.ReplaceAllString(username, "_")
+	if len(name)+len(poolNameSuffix) > maxDatabaseNameLength {
+		name = name[:maxDatabaseNameLength-len(poolNameSuffix)]
+	}
+	return name + poolNameSuffix
+}
+
+func hasBindingDatabase(service *aiven.Service, username string) bool {
+	return service.ServiceType == "pg" && contains(service.Databases, bindingDatabaseName(username))
+}
+
+func hasBindingPool(service *aiven.Service, username string) bool {
+	if service.ServiceType != "pg" {
+		return false
+	}
+	for _, pool := range service.ConnectionPools {
+		if pool.PoolName == bindingPoolName(username) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePoolSize reads the pool_size bind parameter, which only pooled
+// bindings can give.
+func parsePoolSize(parameters BindParameters) (int, error) {
+	if parameters.PoolSize == nil {
+		return DefaultPoolSize, nil
+	}
+	if !parameters.Pooled {
+		return 0, invalidParameters(errors.New("pool_size can only be given for pooled bindings"))
+	}
+	if *parameters.PoolSize < MinPoolSize || *parameters.PoolSize > MaxPoolSize {
+		return 0, invalidParameters(fmt.Errorf("Invalid pool_size: %d, must be between %d and %d", *parameters.PoolSize, MinPoolSize, MaxPoolSize))
+	}
+	return *parameters.PoolSize, nil
+}
+
+// setUpPostgresBinding creates the database and connection pool the binding
+// asked for, and adds them to the service so that its credentials use them.
+// Whatever it created is removed again if it fails.
+func (ap *AivenProvider) setUpPostgresBinding(ctx context.Context, project, serviceName string, service *aiven.Service, username string, parameters BindParameters, poolSize int) error {
+	database := sharedDatabase
+	databaseCreated := false
+	if parameters.OwnDatabase {
+		var err error
+		database = bindingDatabaseName(username)
+		databaseCreated, err = ap.createBindingDatabase(ctx, project, serviceName, service, database, username)
+		if err != nil {
+			return err
+		}
+		if !hasBindingDatabase(service, username) {
+			service.Databases = append(service.Databases, database)
+		}
+	}
+
+	if parameters.Pooled {
+		pool := aiven.ConnectionPool{
+			PoolName: bindingPoolName(username),
+			Database: database,
+			Username: username,
+			PoolSize: poolSize,
+			PoolMode: poolMode,
+		}
+		err := ap.Client.CreateConnectionPool(ctx, &aiven.CreateConnectionPoolInput{
+			Project:     project,
+			ServiceName: serviceName,
+			PoolName:    pool.PoolName,
+			Database:    pool.Database,
+			Username:    pool.Username,
+			PoolSize:    pool.PoolSize,
+			PoolMode:    pool.PoolMode,
+		})
+		if err != nil && err != aiven.ErrConnectionPoolAlreadyExists {
+			if databaseCreated {
+				ap.Client.DeleteServiceDatabase(ctx, &aiven.DeleteServiceDatabaseInput{
+					Project:     project,
+					ServiceName: serviceName,
+					Database:    database,
+				})
+			}
+			return err
+		}
+		if !hasBindingPool(service, username) {
+			service.ConnectionPools = append(service.ConnectionPools, pool)
+		}
+	}
+	return nil
+}
+
+// createBindingDatabase creates a database which only the binding's user,
+// and the service's admin user, can use, and reports whether it was created
+// rather than found. A database it created is removed again if it cannot be
+// locked down.
+func (ap *AivenProvider) createBindingDatabase(ctx context.Context, project, serviceName string, service *aiven.Service, database, username string) (bool, error) {
+	err := ap.Client.CreateServiceDatabase(ctx, &aiven.CreateServiceDatabaseInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Database:    database,
+	})
+	// A retried bind finds the database it created before, which is locked
+	// down again in case that is what failed.
+	created := err == nil
+	if err != nil && err != aiven.ErrServiceDatabaseAlreadyExists {
+		return false, err
+	}
+
+	if err := ap.grantBindingDatabase(ctx, project, service, database, username); err != nil {
+		if created {
+			ap.Client.DeleteServiceDatabase(ctx, &aiven.DeleteServiceDatabaseInput{
+				Project:     project,
+				ServiceName: serviceName,
+				Database:    database,
+			})
+		}
+		return false, err
+	}
+	return created, nil
+}
+
+// grantBindingDatabase gives the user full access to the database, and
+// takes away the access everyone has to new databases. Aiven's API has no
+// way to do this, so it is done as the service's admin user.
+func (ap *AivenProvider) grantBindingDatabase(ctx context.Context, project string, service *aiven.Service, database, username string) error {
+	if service.ServiceUriParams.User == "" || service.ServiceUriParams.Password == "" {
+		return errors.New("Error granting access to the binding's database: no admin credentials found in response JSON")
+	}
+	caCertificate, err := ap.projectCA(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	return ap.Postgres.Exec(ctx, postgres.Connection{
+		Host:          service.ServiceUriParams.Host,
+		Port:          service.ServiceUriParams.Port,
+		Username:      service.ServiceUriParams.User,
+		Password:      service.ServiceUriParams.Password,
+		Database:      database,
+		CACertificate: caCertificate,
+	},
+		"REVOKE ALL ON DATABASE "+postgres.QuoteIdentifier(database)+" FROM PUBLIC",
+		"GRANT ALL ON DATABASE "+postgres.QuoteIdentifier(database)+" TO "+postgres.QuoteIdentifier(username),
+		"GRANT ALL ON SCHEMA public TO "+postgres.QuoteIdentifier(username),
+	)
+}
+
+// deletePostgresBinding removes the connection pool and database of a
+// binding, if it has them. The pool goes first, as it holds connections to
+// the database.
+func (ap *AivenProvider) deletePostgresBinding(ctx context.Context, project, serviceName string, service *aiven.Service, username string) error {
+	if hasBindingPool(service, username) {
+		err := ap.Client.DeleteConnectionPool(ctx, &aiven.DeleteConnectionPoolInput{
+			Project:     project,
+			ServiceName: serviceName,
+			PoolName:    bindingPoolName(username),
+		})
+		if err != nil && err != aiven.ErrConnectionPoolDoesNotExist {
+			return err
+		}
+	}
+
+	if hasBindingDatabase(service, username) {
+		err := ap.Client.DeleteServiceDatabase(ctx, &aiven.DeleteServiceDatabaseInput{
+			Project:     project,
+			ServiceName: serviceName,
+			Database:    bindingDatabaseName(username),
+		})
+		if err != nil && err != aiven.ErrServiceDatabaseDoesNotExist {
+			return err
+		}
+	}
+	return nil
+}
+
+// poolerEndpoint finds the service's PgBouncer endpoint, preferring the
+// dynamic route which follows the primary if it moves.
+func poolerEndpoint(service *aiven.Service) (host, port string) {
+	for _, route := range []string{"dynamic", ""} {
+		for _, component := range service.Components {
+			if component.Component == "pgbouncer" && component.Usage != "replica" &&
+				(route == "" || component.Route == route) {
+				return component.Host, strconv.Itoa(component.Port)
+			}
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-cf/brokerapi"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// diskAutoscalerIntegrationType is both the type of the integration
+// endpoints which hold disk autoscaler settings, and of the integrations
+// which apply them to services.
+const diskAutoscalerIntegrationType = "autoscaler"
+
+// additionalDiskSpaceGB works out how much disk space, beyond the Aiven
+// plan's, instances of the plan get. Tenants can choose up to the plan's
+// maximum with the additional_disk_space_gb parameter, and otherwise get the
+// plan's default.
+func additionalDiskSpaceGB(plan *Plan, parameter *int) (int, error) {
+	if parameter == nil {
+		return plan.AdditionalDiskSpaceGB, nil
+	}
+	if plan.MaxAdditionalDiskSpaceGB == 0 {
+		return 0, invalidParameters(fmt.Errorf("additional_disk_space_gb is not supported by the %s plan", plan.Name))
+	}
+	if *parameter < 0 || *parameter > plan.MaxAdditionalDiskSpaceGB {
+		return 0, invalidParameters(fmt.Errorf(
+			"Invalid additional_disk_space_gb: %d, must be between 0 and %d", *parameter, plan.MaxAdditionalDiskSpaceGB,
+		))
+	}
+	return *parameter, nil
+}
+
+// diskSpaceMB is the total disk space to give a service on the plan with the
+// additional disk space. Aiven only takes the total, so the plan's own disk
+// space is looked up.
+func (ap *AivenProvider) diskSpaceMB(ctx context.Context, project string, plan *Plan, additionalGB int) (int, error) {
+	servicePlans, err := ap.Client.GetServicePlans(ctx, &aiven.GetServicePlansInput{
+		Project:     project,
+		ServiceType: aivenServiceType(plan.ServiceType),
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, servicePlan := range servicePlans {
+		if servicePlan.ServicePlan == plan.AivenPlan {
+			return servicePlan.DiskSpaceMB + additionalGB*1024, nil
+		}
+	}
+	return 0, fmt.Errorf("Error getting disk space: Aiven has no %s plan %s", plan.ServiceType, plan.AivenPlan)
+}
+
+func diskShrinkNotSupported(currentMB, requestedMB int) error {
+	return brokerapi.NewFailureResponse(
+		fmt.Errorf(
+			"Cannot shrink the instance's disk from %d GB to %d GB: Aiven can only grow disks",
+			currentMB/1024, requestedMB/1024,
+		),
+		http.StatusUnprocessableEntity,
+		"disk-shrink-not-supported",
+	)
+}
+
+// checkDataFits rejects moving the service to a smaller disk than its data
+// needs, which Aiven would only fail part way through the update. It is best
+// effort: if the disk usage or size cannot be found, the update goes ahead
+// and Aiven decides.
+func (ap *AivenProvider) checkDataFits(
+	ctx context.Context,
+	project, serviceName string,
+	service *aiven.Service,
+	targetDiskSpaceMB func() (int, error),
+) error {
+	if service.DiskSpaceMB == 0 {
+		return nil
+	}
+	targetMB, err := targetDiskSpaceMB()
+	if err != nil {
+		ap.logDiskUsageCheckError(project, serviceName, err)
+		return nil
+	}
+	if targetMB >= service.DiskSpaceMB {
+		return nil
+	}
+	usagePercent, err := ap.Client.GetServiceDiskUsage(ctx, &aiven.GetServiceDiskUsageInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		ap.logDiskUsageCheckError(project, serviceName, err)
+		return nil
+	}
+	usedMB := int(usagePercent / 100 * float64(service.DiskSpaceMB))
+	if usedMB <= targetMB {
+		return nil
+	}
+	return planChangeNotSupported(fmt.Errorf(
+		"Cannot change plan: current data (%dGB) exceeds target plan capacity (%dGB)",
+		gigabytesRoundedUp(usedMB), targetMB/1024,
+	))
+}
+
+func gigabytesRoundedUp(mb int) int {
+	return (mb + 1023) / 1024
+}
+
+func (ap *AivenProvider) logDiskUsageCheckError(project, serviceName string, err error) {
+	ap.Logger.Error("check-disk-usage", err, lager.Data{
+		"project": project,
+		"service": serviceName,
+	})
+}
+
+// diskUsageCacheTTL is how long disk usage is reused for warnings. Disks
+// fill slowly, and usage is only reported hourly, so polling need not
+// fetch it every time.
+const diskUsageCacheTTL = 10 * time.Minute
+
+// diskUsageCheckTimeout is how long the check for a disk usage warning can
+// take, so that a slow metrics endpoint does not hold up polling.
+const diskUsageCheckTimeout = 5 * time.Second
+
+type cachedDiskUsage struct {
+	percent   float64
+	known     bool
+	fetchedAt time.Time
+}
+
+// withDiskUsageWarning adds a warning to the description of a succeeded
+// operation if the service's disk is fuller than the config allows. It is
+// best effort: if the disk usage cannot be found, the description is left
+// as it is. Failures are cached like usage, so that they do not slow
+// polling either.
+func (ap *AivenProvider) withDiskUsageWarning(ctx context.Context, project, serviceName, description string) string {
+	threshold := ap.currentConfig().DiskUsageWarningPercent
+	if threshold == 0 {
+		return description
+	}
+
+	ap.diskUsageCacheLock.Lock()
+	usage, ok := ap.diskUsageCache[serviceName]
+	ap.diskUsageCacheLock.Unlock()
+	if !ok || !ap.now().Before(usage.fetchedAt.Add(diskUsageCacheTTL)) {
+		usage = cachedDiskUsage{fetchedAt: ap.now()}
+		checkCtx, cancel := context.WithTimeout(ctx, diskUsageCheckTimeout)
+		percent, err := ap.Client.GetServiceDiskUsage(checkCtx, &aiven.GetServiceDiskUsageInput{
+			Project:     project,
+			ServiceName: serviceName,
+		})
+		cancel()
+		if err != nil {
+			ap.logDiskUsageCheckError(project, serviceName, err)
+		} else {
+			usage.percent, usage.known = percent, true
+		}
+		ap.diskUsageCacheLock.Lock()
+		if ap.diskUsageCache == nil {
+			ap.diskUsageCache = map[string]cachedDiskUsage{}
+		}
+		ap.diskUsageCache[serviceName] = usage
+		ap.diskUsageCacheLock.Unlock()
+	}
+
+	if !usage.known || usage.percent < float64(threshold) {
+		return description
+	}
+	return fmt.Sprintf("%s. Warning: disk %d%% full — consider a larger plan", description, int(usage.percent))
+}
+
+// diskAutoscalerEndpointName names the project's integration endpoint for
+// the cap, which every service with the same cap shares.
+func diskAutoscalerEndpointName(capGB int) string {
+	return fmt.Sprintf("cf-disk-autoscaler-%dgb", capGB)
+}
+
+// enableDiskAutoscaler integrates the service with a disk autoscaler, which
+// grows its disk as it fills up to at most capGB. Services which already
+// have one are left alone.
+func (ap *AivenProvider) enableDiskAutoscaler(ctx context.Context, project, serviceName string, capGB int) error {
+	integrations, err := ap.Client.ListServiceIntegrations(ctx, &aiven.ListServiceIntegrationsInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return err
+	}
+	for _, integration := range integrations {
+		if integration.IntegrationType == diskAutoscalerIntegrationType {
+			return nil
+		}
+	}
+
+	endpointID, err := ap.diskAutoscalerEndpoint(ctx, project, capGB)
+	if err != nil {
+		return err
+	}
+	_, err = ap.Client.CreateServiceIntegration(ctx, &aiven.CreateServiceIntegrationInput{
+		Project:         project,
+		IntegrationType: diskAutoscalerIntegrationType,
+		SourceService:   serviceName,
+		DestEndpointID:  endpointID,
+	})
+	return err
+}
+
+func (ap *AivenProvider) diskAutoscalerEndpoint(ctx context.Context, project string, capGB int) (string, error) {
+	endpointName := diskAutoscalerEndpointName(capGB)
+	endpoints, err := ap.Client.ListIntegrationEndpoints(ctx, &aiven.ListIntegrationEndpointsInput{Project: project})
+	if err != nil {
+		return "", err
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.EndpointName == endpointName && endpoint.EndpointType == diskAutoscalerIntegrationType {
+			return endpoint.EndpointID, nil
+		}
+	}
+
+	endpointID, err := ap.Client.CreateIntegrationEndpoint(ctx, &aiven.CreateIntegrationEndpointInput{
+		Project:      project,
+		EndpointName: endpointName,
+		EndpointType: diskAutoscalerIntegrationType,
+		UserConfig: map[string]interface{}{
+			"autoscaling": []interface{}{
+				map[string]interface{}{"type": "autoscale_disk", "cap_gb": capGB},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return endpointID, nil
+}
+
+// logDiskAutoscalerError logs failures to enable the disk autoscaler, which
+// do not fail the request as the service works without it. Updating the
+// instance tries again.
+func (ap *AivenProvider) logDiskAutoscalerError(project, serviceName string, err error) {
+	ap.Logger.Error("enable-disk-autoscaler", err, lager.Data{
+		"project": project,
+		"service": serviceName,
+	})
+}
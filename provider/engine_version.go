@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// validEngineVersion matches versions such as "7" and "7.10".
+var validEngineVersion = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+
+// checkAllowedVersions rejects allowed versions for plans without an engine
+// version, and ones which are malformed or older than the plan's own.
+func checkAllowedVersions(plan *Plan) error {
+	if len(plan.AllowedVersions) == 0 {
+		return nil
+	}
+	version := plan.engineVersion()
+	if version == "" {
+		return fmt.Errorf("Config error: plan %s cannot have allowed_versions, as %s plans have no version", plan.Name, plan.ServiceType)
+	}
+	for _, allowed := range plan.AllowedVersions {
+		if !validEngineVersion.MatchString(allowed) {
+			return fmt.Errorf("Config error: plan %s has invalid allowed version %s", plan.Name, allowed)
+		}
+		if compareEngineVersions(allowed, version) < 0 {
+			return fmt.Errorf(
+				"Config error: plan %s allows version %s, which is older than its own version %s", plan.Name, allowed, version,
+			)
+		}
+	}
+	return nil
+}
+
+// compareEngineVersions compares versions component by component, treating
+// missing components as zero, so that "7.10" is newer than both "7" and
+// "7.9". It returns -1, 0 or 1 as a is older than, the same as or newer
+// than b.
+func compareEngineVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := versionComponent(aParts, i), versionComponent(bParts, i)
+		switch {
+		case aPart < bPart:
+			return -1
+		case aPart > bPart:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionComponent(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	// Versions are validated, and Aiven only reports numeric ones.
+	component, _ := strconv.Atoi(parts[i])
+	return component
+}
+
+// updateEngineVersion works out the engine version an update moves the
+// instance to: the version parameter, which must be one the plan allows, or
+// else the plan's. Aiven cannot downgrade services, so asking for an older
+// version than the service has is refused, and instances upgraded beyond
+// their plan's version are kept on the version they have.
+func updateEngineVersion(
+	plan *Plan,
+	parameter string,
+	getCurrentService func() (*aiven.Service, error),
+) (string, error) {
+	version := plan.engineVersion()
+	if parameter != "" {
+		if len(plan.AllowedVersions) == 0 {
+			return "", invalidParameters(fmt.Errorf("version is not supported by the %s plan", plan.Name))
+		}
+		if parameter != version && !contains(plan.AllowedVersions, parameter) {
+			return "", invalidParameters(fmt.Errorf(
+				"Invalid version: %s, must be one of %s", parameter, strings.Join(plan.allowedVersions(), ", "),
+			))
+		}
+		version = parameter
+	} else if len(plan.AllowedVersions) == 0 {
+		// Instances of plans without allowed versions cannot have been
+		// upgraded, so are not looked up.
+		return version, nil
+	}
+
+	service, err := getCurrentService()
+	if err != nil {
+		return "", err
+	}
+	currentVersion := service.UserConfig.EngineVersion(aivenServiceType(plan.ServiceType))
+	if currentVersion == "" || compareEngineVersions(version, currentVersion) >= 0 {
+		return version, nil
+	}
+	if parameter == "" {
+		return currentVersion, nil
+	}
+	return "", planChangeNotSupported(fmt.Errorf(
+		"Cannot downgrade %s from version %s to %s: Aiven cannot downgrade services",
+		plan.ServiceType, currentVersion, parameter,
+	))
+}
+
+// allowedVersions are the versions the version parameter accepts for
+// instances of the plan.
+func (p *Plan) allowedVersions() []string {
+	versions := []string{p.engineVersion()}
+	for _, version := range p.AllowedVersions {
+		if !contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// setEngineVersion overrides the plan's engine version in the user config.
+func setEngineVersion(userConfig *aiven.UserConfig, serviceType, version string) {
+	switch serviceType {
+	case "elasticsearch":
+		userConfig.ElasticsearchVersion = version
+	case "kafka":
+		userConfig.KafkaVersion = version
+	case "opensearch":
+		userConfig.OpenSearchVersion = version
+	case "postgres":
+		userConfig.PGVersion = version
+	}
+}
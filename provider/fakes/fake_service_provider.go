@@ -38,6 +38,36 @@ type FakeServiceProvider struct {
 		result1 string
 		result2 error
 	}
+	GetBindingStub        func(context.Context, provider.GetBindingData) (brokerapi.GetBindingSpec, error)
+	getBindingMutex       sync.RWMutex
+	getBindingArgsForCall []struct {
+		arg1 context.Context
+		arg2 provider.GetBindingData
+	}
+	getBindingReturns struct {
+		result1 brokerapi.GetBindingSpec
+		result2 error
+	}
+	getBindingReturnsOnCall map[int]struct {
+		result1 brokerapi.GetBindingSpec
+		result2 error
+	}
+	LastBindingOperationStub        func(context.Context, provider.LastBindingOperationData) (brokerapi.LastOperationState, string, error)
+	lastBindingOperationMutex       sync.RWMutex
+	lastBindingOperationArgsForCall []struct {
+		arg1 context.Context
+		arg2 provider.LastBindingOperationData
+	}
+	lastBindingOperationReturns struct {
+		result1 brokerapi.LastOperationState
+		result2 string
+		result3 error
+	}
+	lastBindingOperationReturnsOnCall map[int]struct {
+		result1 brokerapi.LastOperationState
+		result2 string
+		result3 error
+	}
 	LastOperationStub        func(context.Context, provider.LastOperationData) (brokerapi.LastOperationState, string, error)
 	lastOperationMutex       sync.RWMutex
 	lastOperationArgsForCall []struct {
@@ -107,15 +137,16 @@ func (fake *FakeServiceProvider) Bind(arg1 context.Context, arg2 provider.BindDa
 		arg1 context.Context
 		arg2 provider.BindData
 	}{arg1, arg2})
+	stub := fake.BindStub
+	fakeReturns := fake.bindReturns
 	fake.recordInvocation("Bind", []interface{}{arg1, arg2})
 	fake.bindMutex.Unlock()
-	if fake.BindStub != nil {
-		return fake.BindStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.bindReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -171,15 +202,16 @@ func (fake *FakeServiceProvider) Deprovision(arg1 context.Context, arg2 provider
 		arg1 context.Context
 		arg2 provider.DeprovisionData
 	}{arg1, arg2})
+	stub := fake.DeprovisionStub
+	fakeReturns := fake.deprovisionReturns
 	fake.recordInvocation("Deprovision", []interface{}{arg1, arg2})
 	fake.deprovisionMutex.Unlock()
-	if fake.DeprovisionStub != nil {
-		return fake.DeprovisionStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.deprovisionReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -228,6 +260,139 @@ func (fake *FakeServiceProvider) DeprovisionReturnsOnCall(i int, result1 string,
 	}{result1, result2}
 }
 
+func (fake *FakeServiceProvider) GetBinding(arg1 context.Context, arg2 provider.GetBindingData) (brokerapi.GetBindingSpec, error) {
+	fake.getBindingMutex.Lock()
+	ret, specificReturn := fake.getBindingReturnsOnCall[len(fake.getBindingArgsForCall)]
+	fake.getBindingArgsForCall = append(fake.getBindingArgsForCall, struct {
+		arg1 context.Context
+		arg2 provider.GetBindingData
+	}{arg1, arg2})
+	stub := fake.GetBindingStub
+	fakeReturns := fake.getBindingReturns
+	fake.recordInvocation("GetBinding", []interface{}{arg1, arg2})
+	fake.getBindingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceProvider) GetBindingCallCount() int {
+	fake.getBindingMutex.RLock()
+	defer fake.getBindingMutex.RUnlock()
+	return len(fake.getBindingArgsForCall)
+}
+
+func (fake *FakeServiceProvider) GetBindingCalls(stub func(context.Context, provider.GetBindingData) (brokerapi.GetBindingSpec, error)) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = stub
+}
+
+func (fake *FakeServiceProvider) GetBindingArgsForCall(i int) (context.Context, provider.GetBindingData) {
+	fake.getBindingMutex.RLock()
+	defer fake.getBindingMutex.RUnlock()
+	argsForCall := fake.getBindingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceProvider) GetBindingReturns(result1 brokerapi.GetBindingSpec, result2 error) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = nil
+	fake.getBindingReturns = struct {
+		result1 brokerapi.GetBindingSpec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) GetBindingReturnsOnCall(i int, result1 brokerapi.GetBindingSpec, result2 error) {
+	fake.getBindingMutex.Lock()
+	defer fake.getBindingMutex.Unlock()
+	fake.GetBindingStub = nil
+	if fake.getBindingReturnsOnCall == nil {
+		fake.getBindingReturnsOnCall = make(map[int]struct {
+			result1 brokerapi.GetBindingSpec
+			result2 error
+		})
+	}
+	fake.getBindingReturnsOnCall[i] = struct {
+		result1 brokerapi.GetBindingSpec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceProvider) LastBindingOperation(arg1 context.Context, arg2 provider.LastBindingOperationData) (brokerapi.LastOperationState, string, error) {
+	fake.lastBindingOperationMutex.Lock()
+	ret, specificReturn := fake.lastBindingOperationReturnsOnCall[len(fake.lastBindingOperationArgsForCall)]
+	fake.lastBindingOperationArgsForCall = append(fake.lastBindingOperationArgsForCall, struct {
+		arg1 context.Context
+		arg2 provider.LastBindingOperationData
+	}{arg1, arg2})
+	stub := fake.LastBindingOperationStub
+	fakeReturns := fake.lastBindingOperationReturns
+	fake.recordInvocation("LastBindingOperation", []interface{}{arg1, arg2})
+	fake.lastBindingOperationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeServiceProvider) LastBindingOperationCallCount() int {
+	fake.lastBindingOperationMutex.RLock()
+	defer fake.lastBindingOperationMutex.RUnlock()
+	return len(fake.lastBindingOperationArgsForCall)
+}
+
+func (fake *FakeServiceProvider) LastBindingOperationCalls(stub func(context.Context, provider.LastBindingOperationData) (brokerapi.LastOperationState, string, error)) {
+	fake.lastBindingOperationMutex.Lock()
+	defer fake.lastBindingOperationMutex.Unlock()
+	fake.LastBindingOperationStub = stub
+}
+
+func (fake *FakeServiceProvider) LastBindingOperationArgsForCall(i int) (context.Context, provider.LastBindingOperationData) {
+	fake.lastBindingOperationMutex.RLock()
+	defer fake.lastBindingOperationMutex.RUnlock()
+	argsForCall := fake.lastBindingOperationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceProvider) LastBindingOperationReturns(result1 brokerapi.LastOperationState, result2 string, result3 error) {
+	fake.lastBindingOperationMutex.Lock()
+	defer fake.lastBindingOperationMutex.Unlock()
+	fake.LastBindingOperationStub = nil
+	fake.lastBindingOperationReturns = struct {
+		result1 brokerapi.LastOperationState
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeServiceProvider) LastBindingOperationReturnsOnCall(i int, result1 brokerapi.LastOperationState, result2 string, result3 error) {
+	fake.lastBindingOperationMutex.Lock()
+	defer fake.lastBindingOperationMutex.Unlock()
+	fake.LastBindingOperationStub = nil
+	if fake.lastBindingOperationReturnsOnCall == nil {
+		fake.lastBindingOperationReturnsOnCall = make(map[int]struct {
+			result1 brokerapi.LastOperationState
+			result2 string
+			result3 error
+		})
+	}
+	fake.lastBindingOperationReturnsOnCall[i] = struct {
+		result1 brokerapi.LastOperationState
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeServiceProvider) LastOperation(arg1 context.Context, arg2 provider.LastOperationData) (brokerapi.LastOperationState, string, error) {
 	fake.lastOperationMutex.Lock()
 	ret, specificReturn := fake.lastOperationReturnsOnCall[len(fake.lastOperationArgsForCall)]
@@ -235,15 +400,16 @@ func (fake *FakeServiceProvider) LastOperation(arg1 context.Context, arg2 provid
 		arg1 context.Context
 		arg2 provider.LastOperationData
 	}{arg1, arg2})
+	stub := fake.LastOperationStub
+	fakeReturns := fake.lastOperationReturns
 	fake.recordInvocation("LastOperation", []interface{}{arg1, arg2})
 	fake.lastOperationMutex.Unlock()
-	if fake.LastOperationStub != nil {
-		return fake.LastOperationStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.lastOperationReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -302,15 +468,16 @@ func (fake *FakeServiceProvider) Provision(arg1 context.Context, arg2 provider.P
 		arg1 context.Context
 		arg2 provider.ProvisionData
 	}{arg1, arg2})
+	stub := fake.ProvisionStub
+	fakeReturns := fake.provisionReturns
 	fake.recordInvocation("Provision", []interface{}{arg1, arg2})
 	fake.provisionMutex.Unlock()
-	if fake.ProvisionStub != nil {
-		return fake.ProvisionStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
 	}
-	fakeReturns := fake.provisionReturns
 	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
@@ -369,15 +536,16 @@ func (fake *FakeServiceProvider) Unbind(arg1 context.Context, arg2 provider.Unbi
 		arg1 context.Context
 		arg2 provider.UnbindData
 	}{arg1, arg2})
+	stub := fake.UnbindStub
+	fakeReturns := fake.unbindReturns
 	fake.recordInvocation("Unbind", []interface{}{arg1, arg2})
 	fake.unbindMutex.Unlock()
-	if fake.UnbindStub != nil {
-		return fake.UnbindStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.unbindReturns
 	return fakeReturns.result1
 }
 
@@ -430,15 +598,16 @@ func (fake *FakeServiceProvider) Update(arg1 context.Context, arg2 provider.Upda
 		arg1 context.Context
 		arg2 provider.UpdateData
 	}{arg1, arg2})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
 	fake.recordInvocation("Update", []interface{}{arg1, arg2})
 	fake.updateMutex.Unlock()
-	if fake.UpdateStub != nil {
-		return fake.UpdateStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.updateReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -490,18 +659,6 @@ func (fake *FakeServiceProvider) UpdateReturnsOnCall(i int, result1 string, resu
 func (fake *FakeServiceProvider) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.bindMutex.RLock()
-	defer fake.bindMutex.RUnlock()
-	fake.deprovisionMutex.RLock()
-	defer fake.deprovisionMutex.RUnlock()
-	fake.lastOperationMutex.RLock()
-	defer fake.lastOperationMutex.RUnlock()
-	fake.provisionMutex.RLock()
-	defer fake.provisionMutex.RUnlock()
-	fake.unbindMutex.RLock()
-	defer fake.unbindMutex.RUnlock()
-	fake.updateMutex.RLock()
-	defer fake.updateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// planFeatures maps the features plans of each service type can turn on or
+// off onto the user config setting which controls them.
+var planFeatures = map[string]map[string]string{
+	"elasticsearch": {
+		"kibana_enabled": "kibana.enabled",
+	},
+	"opensearch": {
+		"opensearch_dashboards_enabled": "opensearch_dashboards.enabled",
+	},
+	"kafka": {
+		"schema_registry": "schema_registry",
+	},
+}
+
+// checkPlanFeatures rejects features the plan's service type does not have,
+// so that typos are found at startup rather than when provisioning.
+func checkPlanFeatures(plan *Plan) error {
+	for name := range plan.Features {
+		if _, ok := planFeatures[plan.ServiceType][name]; !ok {
+			known := []string{}
+			for name := range planFeatures[plan.ServiceType] {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			if len(known) == 0 {
+				return fmt.Errorf("Config error: plan %s has unknown feature %s, %s plans have none", plan.Name, name, plan.ServiceType)
+			}
+			return fmt.Errorf(
+				"Config error: plan %s has unknown feature %s, %s plans have: %s",
+				plan.Name, name, plan.ServiceType, strings.Join(known, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// featureUserConfig is the user config which turns the plan's features on or
+// off. Features which are turned off are sent as such, so that updating
+// instances to a plan without a feature turns it off.
+func featureUserConfig(plan *Plan) map[string]interface{} {
+	userConfig := map[string]interface{}{}
+	for name, enabled := range plan.Features {
+		path := strings.Split(planFeatures[plan.ServiceType][name], ".")
+		object := userConfig
+		for _, key := range path[:len(path)-1] {
+			nested, ok := object[key].(map[string]interface{})
+			if !ok {
+				nested = map[string]interface{}{}
+				object[key] = nested
+			}
+			object = nested
+		}
+		object[path[len(path)-1]] = enabled
+	}
+	return userConfig
+}
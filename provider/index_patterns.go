@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// indexPatternServiceTypes are the service types with index retention rules.
+var indexPatternServiceTypes = []string{"elasticsearch", "opensearch"}
+
+// indexPatterns validates the index_patterns parameter. It returns nil when
+// the parameter is omitted.
+func indexPatterns(serviceType string, parameter []IndexPatternParameter) (*[]aiven.IndexPattern, error) {
+	if parameter == nil {
+		return nil, nil
+	}
+	if !contains(indexPatternServiceTypes, serviceType) {
+		return nil, invalidParameters(fmt.Errorf("index_patterns is not supported by %s services", serviceType))
+	}
+	patterns := []aiven.IndexPattern{}
+	for _, pattern := range parameter {
+		if pattern.Pattern == "" {
+			return nil, invalidParameters(fmt.Errorf("Invalid index_patterns: every rule must have a pattern"))
+		}
+		if pattern.MaxIndexCount < 1 {
+			return nil, invalidParameters(fmt.Errorf(
+				"Invalid index_patterns: max_index_count of %s must be at least 1", pattern.Pattern,
+			))
+		}
+		patterns = append(patterns, aiven.IndexPattern{
+			Pattern:       pattern.Pattern,
+			MaxIndexCount: pattern.MaxIndexCount,
+		})
+	}
+	return &patterns, nil
+}
+
+// updatedIndexPatterns are the index retention rules to send when updating
+// an instance. Omitting the parameter clears the rules, unless the plan's
+// user config sets rules of its own.
+func updatedIndexPatterns(plan *Plan, parameter []IndexPatternParameter) (*[]aiven.IndexPattern, error) {
+	patterns, err := indexPatterns(plan.ServiceType, parameter)
+	if err != nil || patterns != nil || !contains(indexPatternServiceTypes, plan.ServiceType) {
+		return patterns, err
+	}
+	if _, ok := plan.UserConfig["index_patterns"]; ok {
+		return nil, nil
+	}
+	return &[]aiven.IndexPattern{}, nil
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// InstanceReport describes one of the broker's services, with the tags which
+// trace it back to its owner.
+type InstanceReport struct {
+	Project     string            `json:"project"`
+	ServiceName string            `json:"service_name"`
+	ServiceType string            `json:"service_type"`
+	Plan        string            `json:"plan"`
+	State       string            `json:"state"`
+	Tags        map[string]string `json:"tags"`
+}
+
+// ListInstances reports the broker's services in every project it uses, so
+// that operators can see who owns them.
+func (ap *AivenProvider) ListInstances(ctx context.Context) ([]InstanceReport, error) {
+	config := ap.currentConfig()
+	// The projects may be shared with other brokers, so only our own
+	// services are reported.
+	servicePrefix := buildServiceName(config.ServiceNamePrefix, "")
+
+	reports := []InstanceReport{}
+	for _, project := range config.projects() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		services, err := ap.Client.ListServices(ctx, &aiven.ListServicesInput{Project: project})
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range services {
+			if !strings.HasPrefix(service.ServiceName, servicePrefix) {
+				continue
+			}
+			reports = append(reports, InstanceReport{
+				Project:     project,
+				ServiceName: service.ServiceName,
+				ServiceType: service.ServiceType,
+				Plan:        service.Plan,
+				State:       string(service.State),
+				Tags:        service.Tags,
+			})
+		}
+	}
+	return reports, nil
+}
@@ -11,6 +11,8 @@ type ServiceProvider interface {
 	Deprovision(context.Context, DeprovisionData) (operationData string, err error)
 	Bind(context.Context, BindData) (binding brokerapi.Binding, err error)
 	Unbind(context.Context, UnbindData) (err error)
+	GetBinding(context.Context, GetBindingData) (spec brokerapi.GetBindingSpec, err error)
+	LastBindingOperation(context.Context, LastBindingOperationData) (state brokerapi.LastOperationState, description string, err error)
 	Update(context.Context, UpdateData) (operationData string, err error)
 	LastOperation(context.Context, LastOperationData) (state brokerapi.LastOperationState, description string, err error)
 }
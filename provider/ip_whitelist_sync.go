@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// Results of syncing an instance's IP filter with the platform whitelist.
+const (
+	IPWhitelistSyncChanged   = "changed"
+	IPWhitelistSyncUnchanged = "unchanged"
+	// IPWhitelistSyncDrifted is reported by dry runs for filters which would
+	// have been changed.
+	IPWhitelistSyncDrifted = "drifted"
+	IPWhitelistSyncError   = "error"
+)
+
+// DefaultIPWhitelistSyncConcurrency is how many services SyncIPWhitelist
+// updates at once by default.
+const DefaultIPWhitelistSyncConcurrency = 4
+
+// IPWhitelistSyncReport is the result of syncing one of the broker's
+// services.
+type IPWhitelistSyncReport struct {
+	Project     string `json:"project"`
+	ServiceName string `json:"service_name"`
+	Result      string `json:"result"`
+	// IPFilter is the filter the service should have.
+	IPFilter []string `json:"ip_filter,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// SyncIPWhitelist brings the IP filters of all the broker's services up to
+// date with the platform whitelist, keeping the entries tenants added but
+// dropping those of the retired whitelist. Only services whose filter
+// differs are updated, at most concurrency at a time. Dry runs only report
+// which filters differ.
+func (ap *AivenProvider) SyncIPWhitelist(ctx context.Context, dryRun bool, concurrency int) ([]IPWhitelistSyncReport, error) {
+	config := ap.currentConfig()
+	if len(config.IPWhitelist) == 0 {
+		return nil, errors.New("Cannot sync the IP whitelist: the platform has none")
+	}
+	if concurrency < 1 {
+		concurrency = DefaultIPWhitelistSyncConcurrency
+	}
+	// The projects may be shared with other brokers, so only our own
+	// services are synced.
+	servicePrefix := buildServiceName(config.ServiceNamePrefix, "")
+
+	reports := []IPWhitelistSyncReport{}
+	services := []aiven.Service{}
+	for _, project := range config.projects() {
+		projectServices, err := ap.Client.ListServices(ctx, &aiven.ListServicesInput{Project: project})
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range projectServices {
+			if strings.HasPrefix(service.ServiceName, servicePrefix) {
+				reports = append(reports, IPWhitelistSyncReport{Project: project, ServiceName: service.ServiceName})
+				services = append(services, service)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for i := range services {
+		wg.Add(1)
+		go func(report *IPWhitelistSyncReport, service aiven.Service) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			ap.syncServiceIPFilter(ctx, config, report, service, dryRun)
+		}(&reports[i], services[i])
+	}
+	wg.Wait()
+	return reports, nil
+}
+
+func (ap *AivenProvider) syncServiceIPFilter(
+	ctx context.Context,
+	config *Config,
+	report *IPWhitelistSyncReport,
+	service aiven.Service,
+	dryRun bool,
+) {
+	ipFilter := config.ipFilterFor(config.IPWhitelist, config.withoutRetiredIPs(service.UserConfig.IPFilter))
+	report.IPFilter = ipFilter
+	if sameIPFilter(service.UserConfig.IPFilter, ipFilter) {
+		report.Result = IPWhitelistSyncUnchanged
+		return
+	}
+	if dryRun {
+		report.Result = IPWhitelistSyncDrifted
+		return
+	}
+	if ctx.Err() != nil {
+		report.Result, report.Error = IPWhitelistSyncError, ctx.Err().Error()
+		return
+	}
+
+	// Only the IP filter is sent, so the rest of the service's user config
+	// is left as it is.
+	_, err := ap.Client.UpdateService(ctx, &aiven.UpdateServiceInput{
+		Project:     report.Project,
+		ServiceName: service.ServiceName,
+		UserConfig:  aiven.UserConfig{CommonUserConfig: aiven.CommonUserConfig{IPFilter: ipFilter}},
+	})
+	if err != nil {
+		ap.Logger.Error("sync-ip-whitelist", err, lager.Data{
+			"project": report.Project,
+			"service": service.ServiceName,
+		})
+		report.Result, report.Error = IPWhitelistSyncError, err.Error()
+		return
+	}
+	ap.Logger.Info("sync-ip-whitelist", lager.Data{
+		"project":   report.Project,
+		"service":   service.ServiceName,
+		"ip-filter": ipFilter,
+	})
+	report.Result = IPWhitelistSyncChanged
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-cf/brokerapi"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// applyMaintenance starts the maintenance updates pending for the instance's
+// service, rather than leaving them for its maintenance window. The
+// operation data records how many there were, so that an update with none
+// succeeds straight away.
+func (ap *AivenProvider) applyMaintenance(ctx context.Context, config *Config, plan *Plan, updateData UpdateData) (string, error) {
+	if err := checkOnlyChange(updateData, "apply_maintenance"); err != nil {
+		return "", err
+	}
+
+	project := config.ProjectForPlan(plan)
+	serviceName := buildServiceName(config.ServiceNamePrefix, updateData.InstanceID)
+	updates, err := ap.Client.ListMaintenanceUpdates(ctx, &aiven.ListMaintenanceUpdatesInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	operationData := ap.newOperationData(OperationUpdate, plan)
+	operationData.ApplyMaintenance = true
+	operationData.MaintenanceUpdates = len(updates)
+	if len(updates) == 0 {
+		return operationData.encode(), nil
+	}
+	err = ap.Client.StartMaintenance(ctx, &aiven.StartMaintenanceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return "", err
+	}
+	ap.Logger.Info("start-maintenance", lager.Data{
+		"project": project,
+		"service": serviceName,
+		"updates": len(updates),
+	})
+	return operationData.encode(), nil
+}
+
+// maintenanceState reports the maintenance as in progress until the service
+// is running without pending updates. Aiven keeps reporting it as running
+// for a little while after maintenance is started, and powers the service
+// off while applying some updates, so being powered off is not a failure
+// unless the update times out.
+func maintenanceState(operationData OperationData, service *aiven.Service) (brokerapi.LastOperationState, string) {
+	if operationData.MaintenanceUpdates == 0 {
+		return brokerapi.Succeeded, "No maintenance updates were pending"
+	}
+	state, description := providerStatesMapping(service.State)
+	switch {
+	case service.State == aiven.PowerOff:
+		return brokerapi.InProgress, "Applying maintenance updates: the service is powered off"
+	case state == brokerapi.Succeeded && len(service.Maintenance.Updates) > 0:
+		return brokerapi.InProgress, "Preparing to apply maintenance updates"
+	case state == brokerapi.InProgress:
+		return state, progressDescription(fmt.Sprintf("Applying maintenance updates: %s", description), service)
+	}
+	return state, description
+}
@@ -19,9 +19,10 @@ type DeprovisionData struct {
 }
 
 type BindData struct {
-	InstanceID string
-	BindingID  string
-	Details    brokerapi.BindDetails
+	InstanceID   string
+	BindingID    string
+	Details      brokerapi.BindDetails
+	AsyncAllowed bool
 }
 
 type UnbindData struct {
@@ -30,6 +31,19 @@ type UnbindData struct {
 	Details    brokerapi.UnbindDetails
 }
 
+type GetBindingData struct {
+	InstanceID string
+	BindingID  string
+}
+
+type LastBindingOperationData struct {
+	InstanceID    string
+	BindingID     string
+	ServiceID     string
+	PlanID        string
+	OperationData string
+}
+
 type UpdateData struct {
 	InstanceID string
 	Details    brokerapi.UpdateDetails
@@ -39,5 +53,7 @@ type UpdateData struct {
 
 type LastOperationData struct {
 	InstanceID    string
+	ServiceID     string
+	PlanID        string
 	OperationData string
 }
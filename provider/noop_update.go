@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"encoding/json"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// withoutParameters reports whether the raw parameters are absent, or an
+// empty object.
+func withoutParameters(rawParameters json.RawMessage) bool {
+	var parameters map[string]json.RawMessage
+	if err := json.Unmarshal(rawParameters, &parameters); err != nil {
+		return len(rawParameters) == 0
+	}
+	return len(parameters) == 0
+}
+
+// updateChangesService reports whether the update would change the plan,
+// engine version, cloud, VPC, disk or IP filter of the service. Platforms
+// send updates which change nothing, such as when cf update-service is run
+// without changes, and writing them makes Aiven briefly report the service
+// as being updated.
+func updateChangesService(service *aiven.Service, input *aiven.UpdateServiceInput, plan *Plan, engineVersion string) bool {
+	if input.ServiceType != "" || service.Plan != input.Plan || service.CloudName != input.Cloud {
+		return true
+	}
+	if input.ProjectVPCID != "" && service.ProjectVPCID != input.ProjectVPCID {
+		return true
+	}
+	if input.DiskSpaceMB != 0 && service.DiskSpaceMB != input.DiskSpaceMB {
+		return true
+	}
+	if engineVersion != "" && service.UserConfig.EngineVersion(aivenServiceType(plan.ServiceType)) != engineVersion {
+		return true
+	}
+	return !sameIPFilter(service.UserConfig.IPFilter, input.UserConfig.IPFilter)
+}
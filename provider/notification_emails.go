@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"fmt"
+	"net/mail"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// maxNotificationEmails is the most notification contacts Aiven allows a
+// service.
+const maxNotificationEmails = 10
+
+// notificationEmails validates the notification_emails parameter, the
+// addresses Aiven sends the instance's notifications, such as its disk
+// filling up or maintenance being scheduled, to.
+func notificationEmails(emails []string) ([]aiven.TechEmail, error) {
+	if len(emails) > maxNotificationEmails {
+		return nil, invalidParameters(fmt.Errorf(
+			"Invalid notification_emails: at most %d can be given", maxNotificationEmails,
+		))
+	}
+	var techEmails []aiven.TechEmail
+	for _, email := range emails {
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Address != email {
+			return nil, invalidParameters(fmt.Errorf("Invalid notification_emails: %s is not an email address", email))
+		}
+		techEmails = append(techEmails, aiven.TechEmail{Email: email})
+	}
+	return techEmails, nil
+}
+
+// updatedNotificationEmails are the notification contacts to send when
+// updating an instance. They are left as they are when the parameter is
+// omitted, and an empty list removes them.
+func updatedNotificationEmails(parameter *[]string) (*[]aiven.TechEmail, error) {
+	if parameter == nil {
+		return nil, nil
+	}
+	techEmails, err := notificationEmails(*parameter)
+	if err != nil {
+		return nil, err
+	}
+	if techEmails == nil {
+		techEmails = []aiven.TechEmail{}
+	}
+	return &techEmails, nil
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// Operations which instance operation data can describe.
+const (
+	OperationProvision   = "provision"
+	OperationUpdate      = "update"
+	OperationDeprovision = "deprovision"
+)
+
+// OperationData is returned, as JSON, as the operation data of instance
+// operations, so that LastOperation knows what it is polling for. Instances
+// created before it was added have empty operation data, which decodes to an
+// OperationData without an Operation.
+type OperationData struct {
+	Operation string    `json:"operation"`
+	StartedAt time.Time `json:"started_at"`
+	// PlanID and Version are the plan and engine version the instance is
+	// being moved to, if the operation changes them.
+	PlanID  string `json:"plan_id,omitempty"`
+	Version string `json:"version,omitempty"`
+	// AivenPlan is the Aiven plan of PlanID, which updates wait for the
+	// service to report.
+	AivenPlan string `json:"aiven_plan,omitempty"`
+	// Cloud is the cloud an update is migrating the service to.
+	Cloud string `json:"cloud,omitempty"`
+	// Restart is set for updates which restart the service.
+	Restart bool `json:"restart,omitempty"`
+	// ApplyMaintenance is set for updates which apply the service's pending
+	// maintenance updates, and MaintenanceUpdates is how many there were.
+	ApplyMaintenance   bool `json:"apply_maintenance,omitempty"`
+	MaintenanceUpdates int  `json:"maintenance_updates,omitempty"`
+	// RestoreFromInstance is the instance whose backup a provision is
+	// restoring.
+	RestoreFromInstance string `json:"restore_from_instance,omitempty"`
+	// CloneFrom is the instance whose data a provision is cloning.
+	CloneFrom string `json:"clone_from,omitempty"`
+	// Recovery is how a provision dealt with a service which already had
+	// the instance's name, if it had to.
+	Recovery string `json:"recovery,omitempty"`
+	// BillingGroupID is the billing group the operation failed to bill the
+	// instance's project to, which is tried again until it works.
+	BillingGroupID string `json:"billing_group_id,omitempty"`
+	// TimeoutSeconds is how long after StartedAt the operation is failed if
+	// it has not finished. Zero means it never times out.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+func (ap *AivenProvider) newOperationData(operation string, plan *Plan) OperationData {
+	operationData := OperationData{
+		Operation:      operation,
+		StartedAt:      ap.now().UTC(),
+		TimeoutSeconds: ap.currentConfig().operationTimeoutSeconds(operation),
+	}
+	if plan != nil {
+		operationData.PlanID = plan.ID
+		operationData.Version = plan.engineVersion()
+		operationData.AivenPlan = plan.AivenPlan
+	}
+	return operationData
+}
+
+// appliedTo reports whether the service has been moved to the operation's
+// Aiven plan, engine version and cloud. Aiven keeps reporting the old ones, as well
+// as RUNNING, for a little while after accepting an update. Versions are only
+// compared when both are known.
+func (o OperationData) appliedTo(service *aiven.Service) bool {
+	if o.AivenPlan != "" && service.Plan != o.AivenPlan {
+		return false
+	}
+	if o.Cloud != "" && service.CloudName != o.Cloud {
+		return false
+	}
+	version := service.UserConfig.EngineVersion(service.ServiceType)
+	return o.Version == "" || version == "" || version == o.Version
+}
+
+// updateTarget is the operation data of an update, with the Aiven plan it
+// moves the instance to. Updates from before the Aiven plan was recorded
+// have the ID of their plan, and operations from before operation data was
+// recorded are polled with the ID of the instance's plan, which is looked
+// up. Plans which are no longer in the config have no target plan.
+func (ap *AivenProvider) updateTarget(operationData OperationData, lastOperationData LastOperationData) OperationData {
+	if operationData.AivenPlan != "" {
+		return operationData
+	}
+	planID := operationData.PlanID
+	if planID == "" {
+		planID = lastOperationData.PlanID
+	}
+	plan, err := ap.currentConfig().FindPlan(lastOperationData.ServiceID, planID)
+	if err == nil {
+		operationData.AivenPlan = plan.AivenPlan
+	}
+	return operationData
+}
+
+func (o OperationData) timeout() time.Duration {
+	return time.Duration(o.TimeoutSeconds) * time.Second
+}
+
+func (o OperationData) timedOut(now time.Time) bool {
+	return o.TimeoutSeconds > 0 && now.After(o.StartedAt.Add(o.timeout()))
+}
+
+func (o OperationData) encode() string {
+	// The struct always marshals.
+	data, _ := json.Marshal(o)
+	return string(data)
+}
+
+// DecodeOperationData parses operation data returned by an instance
+// operation.
+func DecodeOperationData(data string) (OperationData, error) {
+	var operationData OperationData
+	if data == "" {
+		return operationData, nil
+	}
+	if err := json.Unmarshal([]byte(data), &operationData); err != nil {
+		return OperationData{}, fmt.Errorf("Invalid operation data: %s", data)
+	}
+	return operationData, nil
+}
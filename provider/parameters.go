@@ -0,0 +1,315 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+	"github.com/pivotal-cf/brokerapi"
+)
+
+// DefaultIPFilterMaxEntries is the size limit Aiven puts on ip_filter at the
+// time of writing.
+const DefaultIPFilterMaxEntries = 1024
+
+// AllowAllIPFilterEntry is the ip_filter entry which lets any IP address
+// connect.
+const AllowAllIPFilterEntry = "0.0.0.0/0"
+
+// decodeParameters decodes the parameters of a request. Requests without
+// parameters leave params untouched.
+func decodeParameters(rawParameters json.RawMessage, params interface{}) error {
+	if len(rawParameters) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(rawParameters, params); err != nil {
+		return invalidParameters(fmt.Errorf("Invalid parameters: %s", err))
+	}
+	return nil
+}
+
+// decodeStrictParameters decodes the parameters of a request like
+// decodeParameters, but reports every parameter which has the wrong type,
+// rather than only the first, and unless allowUnknown every parameter which
+// params does not have, so that typos are not silently ignored.
+func decodeStrictParameters(rawParameters json.RawMessage, params interface{}, allowUnknown bool) error {
+	if len(rawParameters) == 0 {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rawParameters, &fields); err != nil {
+		return invalidParameters(errors.New("Invalid parameters: must be a JSON object"))
+	}
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	paramsValue := reflect.ValueOf(params).Elem()
+	problems := []string{}
+	for _, key := range keys {
+		field, ok := parameterField(paramsValue, key)
+		if !ok {
+			if !allowUnknown {
+				problems = append(problems, fmt.Sprintf("%s is not a known parameter", key))
+			}
+			continue
+		}
+		if err := json.Unmarshal(fields[key], field.Addr().Interface()); err != nil {
+			problems = append(problems, parameterTypeProblem(key, field.Type(), err))
+		}
+	}
+	if len(problems) > 0 {
+		return invalidParameters(fmt.Errorf("Invalid parameters: %s", strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+// parameterField finds the field of the parameters struct which the key
+// decodes into, matching names as encoding/json does.
+func parameterField(params reflect.Value, key string) (reflect.Value, bool) {
+	var caseInsensitiveMatch reflect.Value
+	found := false
+	for i := 0; i < params.NumField(); i++ {
+		field := params.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key {
+			return params.Field(i), true
+		}
+		if !found && strings.EqualFold(name, key) {
+			caseInsensitiveMatch, found = params.Field(i), true
+		}
+	}
+	return caseInsensitiveMatch, found
+}
+
+// parameterTypeProblem describes a parameter which could not be decoded.
+func parameterTypeProblem(key string, fieldType reflect.Type, err error) string {
+	var typeError *json.UnmarshalTypeError
+	if !errors.As(err, &typeError) {
+		return fmt.Sprintf("%s is invalid: %s", key, err)
+	}
+	if typeError.Field == "" {
+		return fmt.Sprintf("%s must be %s", key, describeParameterType(fieldType))
+	}
+	return fmt.Sprintf("%s.%s must be %s", key, typeError.Field, describeParameterType(typeError.Type))
+}
+
+func describeParameterType(t reflect.Type) string {
+	name := parameterTypeName(t)
+	if strings.IndexAny(name[:1], "aeiou") == 0 {
+		return "an " + name
+	}
+	return "a " + name
+}
+
+// parameterTypeName names types as JSON schemas do.
+func parameterTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch name := typeSchema(t, "")["type"]; name {
+	case nil:
+		return "value"
+	case "array":
+		return "array of " + parameterTypeName(t.Elem()) + "s"
+	default:
+		return name.(string)
+	}
+}
+
+func invalidParameters(err error) error {
+	return brokerapi.NewFailureResponse(err, http.StatusBadRequest, "invalid-parameters")
+}
+
+// parseIPFilterParameter validates the ip_filter parameter with the same
+// rules as the platform whitelist.
+func parseIPFilterParameter(entries []string) ([]string, error) {
+	ipFilter := []string{}
+	for _, entry := range entries {
+		prefix, err := parseIPFilterEntry(entry)
+		if err != nil {
+			return nil, invalidParameters(fmt.Errorf("Invalid ip_filter entry: %s", entry))
+		}
+		ipFilter = append(ipFilter, prefix.String())
+	}
+	return ipFilter, nil
+}
+
+// tenantIPFilter works out the entries a tenant asked for with the ip_filter
+// and ip_filter_groups parameters.
+func (c *Config) tenantIPFilter(entries, groups []string) ([]string, error) {
+	ipFilter, err := parseIPFilterParameter(entries)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		groupIPFilter, ok := c.WhitelistGroups[group]
+		if !ok {
+			return nil, invalidParameters(fmt.Errorf(
+				"Unknown ip_filter_groups entry: %s, valid groups are: %s", group, c.whitelistGroupNames(),
+			))
+		}
+		ipFilter = append(ipFilter, groupIPFilter...)
+	}
+	return ipFilter, nil
+}
+
+func (c *Config) whitelistGroupNames() string {
+	names := []string{}
+	for name := range c.WhitelistGroups {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// checkCloudAllowed validates the cloud parameter, so that unknown clouds are
+// rejected before Aiven is asked for them.
+func (c *Config) checkCloudAllowed(cloud string) error {
+	if cloud == "" || contains(c.AllowedClouds, cloud) {
+		return nil
+	}
+	allowedClouds := "none"
+	if len(c.AllowedClouds) > 0 {
+		allowedClouds = strings.Join(c.AllowedClouds, ", ")
+	}
+	return invalidParameters(fmt.Errorf("Invalid cloud: %s, valid clouds are: %s", cloud, allowedClouds))
+}
+
+// checkCloudForProjectVPC rejects the cloud parameter for plans in a project
+// VPC, as the VPC is only in the plan's cloud.
+func (c *Config) checkCloudForProjectVPC(plan *Plan, cloud string) error {
+	if cloud == "" || c.ProjectVPCIDForPlan(plan) == "" || cloud == c.CloudForPlan(plan) {
+		return nil
+	}
+	return invalidParameters(fmt.Errorf(
+		"Invalid cloud: %s, instances of the %s plan are in a private network in %s, so cannot be in another cloud",
+		cloud, plan.Name, c.CloudForPlan(plan),
+	))
+}
+
+// checkOnlyChange rejects updates which change anything but the parameter,
+// for parameters which act on the instance rather than change it.
+func checkOnlyChange(updateData UpdateData, parameter string) error {
+	var parameters map[string]json.RawMessage
+	// The parameters have already been decoded into an object.
+	_ = json.Unmarshal(updateData.Details.RawParameters, &parameters)
+	if len(parameters) > 1 || updateData.Details.PlanID != updateData.Details.PreviousValues.PlanID {
+		return invalidParameters(fmt.Errorf("%s cannot be combined with other changes", parameter))
+	}
+	return nil
+}
+
+var maintenanceDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// maintenanceWindow validates the maintenance_dow and maintenance_time
+// parameters. It returns nil when neither is given, so that Aiven's window is
+// left alone.
+func maintenanceWindow(dow, timeOfDay string) (*aiven.Maintenance, error) {
+	if dow == "" && timeOfDay == "" {
+		return nil, nil
+	}
+	if dow != "" && !contains(maintenanceDays, dow) {
+		return nil, invalidParameters(fmt.Errorf(
+			"Invalid maintenance_dow: %s, must be one of %s", dow, strings.Join(maintenanceDays, ", "),
+		))
+	}
+	if timeOfDay != "" {
+		if _, err := time.Parse("15:04:05", timeOfDay); err != nil {
+			return nil, invalidParameters(fmt.Errorf(
+				"Invalid maintenance_time: %s, must be a time of day such as 03:00:00", timeOfDay,
+			))
+		}
+	}
+	return &aiven.Maintenance{DOW: dow, Time: timeOfDay}, nil
+}
+
+// mergeIPFilters returns the union of the filters. It is sorted so that
+// repeated updates send Aiven the same list, and other entries are dropped if
+// any filter allows all IP addresses. Entries for the same addresses, such as
+// 10.0.0.1 and 10.0.0.1/32, are only kept once, as they are first given.
+func mergeIPFilters(ipFilters ...[]string) []string {
+	merged := []string{}
+	seen := map[string]bool{}
+	for _, ipFilter := range ipFilters {
+		for _, entry := range ipFilter {
+			if key := ipFilterKey(entry); !seen[key] {
+				seen[key] = true
+				merged = append(merged, entry)
+			}
+		}
+	}
+	if seen[AllowAllIPFilterEntry] {
+		return []string{AllowAllIPFilterEntry}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+func allowsAllIPs(ipFilter []string) bool {
+	return contains(ipFilter, AllowAllIPFilterEntry)
+}
+
+// withoutRetiredIPs drops the entries of the retired platform whitelist
+// from an instance's current IP filter.
+func (c *Config) withoutRetiredIPs(ipFilter []string) []string {
+	retired := map[string]bool{}
+	for _, entry := range c.RetiredIPWhitelist {
+		retired[ipFilterKey(entry)] = true
+	}
+	kept := []string{}
+	for _, entry := range ipFilter {
+		if !retired[ipFilterKey(entry)] {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// ipFilterFor works out an instance's ip_filter from the platform whitelist
+// and the entries the tenant asked for.
+func (c *Config) ipFilterFor(whitelist, tenantIPFilter []string) []string {
+	if c.IPWhitelistRemovable && len(tenantIPFilter) > 0 {
+		return mergeIPFilters(tenantIPFilter)
+	}
+	return mergeIPFilters(whitelist, tenantIPFilter)
+}
+
+// checkIPFilterSize rejects filters Aiven would refuse for being too long, with
+// an error the user can act on.
+func (c *Config) checkIPFilterSize(ipFilter []string) error {
+	maxEntries := c.IPFilterMaxEntries
+	if maxEntries == 0 {
+		maxEntries = DefaultIPFilterMaxEntries
+	}
+	if len(ipFilter) > maxEntries {
+		return invalidParameters(fmt.Errorf("ip_filter supports at most %d entries, got %d", maxEntries, len(ipFilter)))
+	}
+	return nil
+}
+
+// checkIPFilterAllowed stops instances being opened to all IP addresses where
+// the config forbids it.
+func (c *Config) checkIPFilterAllowed(ipFilter []string) error {
+	if c.ForbidAllowAllIPs && allowsAllIPs(ipFilter) {
+		return invalidParameters(fmt.Errorf("ip_filter cannot allow all IP addresses (%s) on this platform", AllowAllIPFilterEntry))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// maxDescriptionLength keeps last operation descriptions short enough for
+// the CLI to show them on one line.
+const maxDescriptionLength = 200
+
+// progressDescription adds how far the service has got, from the states of
+// its nodes, to the description of an operation in progress, such as
+// "Rebalancing: 2/3 nodes ready, basebackup 45%". Aiven only sometimes
+// reports node states and progress, so the description is left as it is
+// without them.
+func progressDescription(description string, service *aiven.Service) string {
+	progress := []string{}
+	if total := len(service.NodeStates); total > 0 {
+		ready := 0
+		for _, node := range service.NodeStates {
+			if node.State == aiven.NodeRunning {
+				ready++
+			}
+		}
+		if ready < total {
+			progress = append(progress, fmt.Sprintf("%d/%d nodes ready", ready, total))
+		}
+	}
+	if phase, percent, ok := phaseProgress(service.NodeStates); ok {
+		progress = append(progress, fmt.Sprintf("%s %d%%", phase, percent))
+	}
+	if len(progress) > 0 {
+		description = fmt.Sprintf("%s: %s", description, strings.Join(progress, ", "))
+	}
+	if len(description) > maxDescriptionLength {
+		description = description[:maxDescriptionLength-len("...")] + "..."
+	}
+	return description
+}
+
+// phaseProgress is the first measurable phase any node is part way through,
+// and how far through it the node is.
+func phaseProgress(nodes []aiven.NodeState) (string, int, bool) {
+	for _, node := range nodes {
+		for _, update := range node.ProgressUpdates {
+			if update.Completed || update.Current == nil || update.Min == nil || update.Max == nil {
+				continue
+			}
+			span := *update.Max - *update.Min
+			if span <= 0 {
+				continue
+			}
+			done := *update.Current - *update.Min
+			if done < 0 {
+				done = 0
+			}
+			if done > span {
+				done = span
+			}
+			return update.Phase, int(done * 100 / span), true
+		}
+	}
+	return "", 0, false
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+	"github.com/pivotal-cf/brokerapi"
+)
+
+// CredentialTypePrometheus bindings get the details of the service's
+// Prometheus metrics endpoint rather than a service user.
+const CredentialTypePrometheus = "prometheus"
+
+const (
+	// prometheusPort is where Aiven serves metrics when the service does not
+	// report a prometheus component.
+	prometheusPort        = "9273"
+	prometheusMetricsPath = "/metrics"
+)
+
+var notPrometheusUsernameCharacters = regexp.MustCompile(`[^a-z0-9]`)
+
+// prometheusEndpointName names the integration endpoint of a binding, so that
+// Unbind can find it again.
+func prometheusEndpointName(bindingID string) string {
+	return "cf-binding-" + bindingID
+}
+
+// prometheusUsername derives a username from the binding ID which fits
+// Aiven's rules for basic auth usernames.
+func prometheusUsername(bindingID string) string {
+	username := "cf" + notPrometheusUsernameCharacters.ReplaceAllString(strings.ToLower(bindingID), "")
+	if len(username) > 32 {
+		username = username[:32]
+	}
+	return username
+}
+
+func generatePassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// bindPrometheus creates a Prometheus integration endpoint for the binding,
+// with its own basic auth credentials, and integrates the service with it.
+func (ap *AivenProvider) bindPrometheus(ctx context.Context, project, serviceName, bindingID string) (brokerapi.Binding, error) {
+	username := prometheusUsername(bindingID)
+	password, err := generatePassword()
+	if err != nil {
+		return brokerapi.Binding{}, err
+	}
+
+	endpointID, err := ap.Client.CreateIntegrationEndpoint(ctx, &aiven.CreateIntegrationEndpointInput{
+		Project:      project,
+		EndpointName: prometheusEndpointName(bindingID),
+		EndpointType: "prometheus",
+		UserConfig: map[string]interface{}{
+			"basic_auth_username": username,
+			"basic_auth_password": password,
+		},
+	})
+	if err != nil {
+		return brokerapi.Binding{}, err
+	}
+
+	_, err = ap.Client.CreateServiceIntegration(ctx, &aiven.CreateServiceIntegrationInput{
+		Project:         project,
+		IntegrationType: "prometheus",
+		SourceService:   serviceName,
+		DestEndpointID:  endpointID,
+	})
+	if err != nil {
+		ap.Client.DeleteIntegrationEndpoint(ctx, &aiven.DeleteIntegrationEndpointInput{
+			Project:    project,
+			EndpointID: endpointID,
+		})
+		return brokerapi.Binding{}, err
+	}
+
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		ap.unbindPrometheus(ctx, project, serviceName, bindingID)
+		return brokerapi.Binding{}, err
+	}
+
+	host, port := serviceComponentEndpoint(service, "prometheus")
+	if host == "" || port == "" {
+		host, port = service.ServiceUriParams.Host, prometheusPort
+	}
+	if host == "" {
+		ap.unbindPrometheus(ctx, project, serviceName, bindingID)
+		return brokerapi.Binding{}, errors.New(
+			"Error getting service connection details: no connection details found in response JSON",
+		)
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		ap.unbindPrometheus(ctx, project, serviceName, bindingID)
+		return brokerapi.Binding{}, fmt.Errorf("Error getting service connection details: invalid port %s", port)
+	}
+
+	credentials := Credentials{}
+	uri := buildURI("https", username, password, host, port)
+	uri.Path = prometheusMetricsPath
+	credentials.URI = uri.String()
+	credentials.Hostname = host
+	credentials.Port = portNumber
+	credentials.Scheme = "https"
+	credentials.TLS = true
+	credentials.Username = username
+	credentials.Password = password
+	credentials.PrometheusMetricsPath = prometheusMetricsPath
+
+	return brokerapi.Binding{Credentials: credentials}, nil
+}
+
+// unbindPrometheus removes the binding's integration endpoint, if it has one,
+// and reports whether it did.
+func (ap *AivenProvider) unbindPrometheus(ctx context.Context, project, serviceName, bindingID string) (bool, error) {
+	endpoints, err := ap.Client.ListIntegrationEndpoints(ctx, &aiven.ListIntegrationEndpointsInput{
+		Project: project,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	endpointID := ""
+	for _, endpoint := range endpoints {
+		if endpoint.EndpointName == prometheusEndpointName(bindingID) {
+			endpointID = endpoint.EndpointID
+		}
+	}
+	if endpointID == "" {
+		return false, nil
+	}
+
+	integrations, err := ap.Client.ListServiceIntegrations(ctx, &aiven.ListServiceIntegrationsInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, integration := range integrations {
+		if integration.DestEndpointID != endpointID {
+			continue
+		}
+		err := ap.Client.DeleteServiceIntegration(ctx, &aiven.DeleteServiceIntegrationInput{
+			Project:              project,
+			ServiceIntegrationID: integration.ServiceIntegrationID,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = ap.Client.DeleteIntegrationEndpoint(ctx, &aiven.DeleteIntegrationEndpointInput{
+		Project:    project,
+		EndpointID: endpointID,
+	})
+	return err == nil, err
+}
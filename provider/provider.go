@@ -2,265 +2,2051 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"code.cloudfoundry.org/lager"
+	"github.com/alphagov/paas-aiven-broker/client/credhub"
 	"github.com/alphagov/paas-aiven-broker/client/elastic"
 	"github.com/alphagov/paas-aiven-broker/client/influxdb"
+	"github.com/alphagov/paas-aiven-broker/client/postgres"
 	"github.com/alphagov/paas-aiven-broker/provider/aiven"
 	"github.com/pivotal-cf/brokerapi"
 )
 
 const AIVEN_BASE_URL string = "https://api.aiven.io"
 
+// Services are tagged with the organization and plan they belong to, so
+// that per-organization plan quotas can be counted from Aiven itself.
+const (
+	organizationGUIDTag = "cf_organization_guid"
+	planIDTag           = "cf_plan_id"
+)
+
+// Services are also tagged with the platform instance they belong to, and
+// who owns it, so that they can be traced back without a lookup table.
+const (
+	spaceGUIDTag    = "cf_space_guid"
+	instanceGUIDTag = "cf_instance_guid"
+	serviceNameTag  = "cf_service_name"
+	planNameTag     = "cf_plan_name"
+)
+
+// Services created in a cloud chosen with the cloud parameter are tagged with
+// it, so that updates keep them there rather than moving them to the plan's.
+const cloudTag = "cf_cloud"
+
 type AivenProvider struct {
 	Client aiven.Client
-	Config *Config
+	// CredHub is only set when binding credentials are stored in CredHub.
+	CredHub credhub.Client
+	// Postgres locks down the databases of bindings with own_database.
+	Postgres postgres.Client
+	Logger   lager.Logger
+	// Config should only be replaced with SetConfig once requests are being
+	// served.
+	Config     *Config
+	configLock sync.RWMutex
+
+	// Clock returns the current time, which bindings with a ttl expire
+	// after. It defaults to time.Now.
+	Clock func() time.Time
+
+	// caCertificates caches the CA certificate of each project, which does
+	// not change.
+	caCertificates map[string]string
+	caLock         sync.Mutex
+
+	// serviceCache holds services fetched by LastOperation, by name.
+	// serviceCacheGeneration counts invalidations, so that services fetched
+	// while an operation changes them are not cached.
+	serviceCache           map[string]cachedService
+	serviceCacheGeneration uint64
+	serviceCacheLock       sync.Mutex
+
+	// diskUsageCache holds the disk usage of services, by name, for disk
+	// usage warnings.
+	diskUsageCache     map[string]cachedDiskUsage
+	diskUsageCacheLock sync.Mutex
 }
 
-func New(configJSON []byte) (*AivenProvider, error) {
+func New(configJSON []byte, logger lager.Logger) (*AivenProvider, error) {
 	config, err := DecodeConfig(configJSON)
 	if err != nil {
 		return nil, err
 	}
 	client := aiven.NewHttpClient(AIVEN_BASE_URL, config.APIToken, config.Project)
-	return &AivenProvider{
-		Client: client,
-		Config: config,
-	}, nil
+	client.HTTPClient = config.aivenTransport().HTTPClient()
+	client.Retry = config.aivenRetryPolicy()
+	client.RateLimiter = config.aivenRateLimiter()
+	client.Logger = logger.Session("aiven-client")
+	provider := &AivenProvider{
+		Client:   client,
+		Postgres: postgres.New(),
+		Logger:   logger,
+		Config:   config,
+	}
+	if config.CredHub != nil {
+		provider.CredHub, err = credhub.New(
+			config.CredHub.URL,
+			config.CredHub.UAAURL,
+			config.CredHub.UAAClientName,
+			config.CredHub.UAAClientSecret,
+			config.CredHub.CACert,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return provider, nil
+}
+
+// SetConfig replaces the config, for example when it is reloaded. Requests
+// which have already started carry on with the config they started with.
+func (ap *AivenProvider) SetConfig(config *Config) {
+	ap.configLock.Lock()
+	defer ap.configLock.Unlock()
+	// The CredHub client is only created on startup, so its settings are
+	// kept until a restart.
+	config.CredHub = ap.Config.CredHub
+	ap.Config = config
+}
+
+func (ap *AivenProvider) currentConfig() *Config {
+	ap.configLock.RLock()
+	defer ap.configLock.RUnlock()
+	return ap.Config
 }
 
 func (ap *AivenProvider) Provision(ctx context.Context, provisionData ProvisionData) (dashboardURL, operationData string, err error) {
-	plan, err := ap.Config.FindPlan(provisionData.Service.ID, provisionData.Plan.ID)
+	defer func() { err = abandonedRequest(err) }()
+	config := ap.currentConfig()
+	defer ap.invalidateCachedService(buildServiceName(config.ServiceNamePrefix, provisionData.InstanceID))
+	plan, err := config.FindPlan(provisionData.Service.ID, provisionData.Plan.ID)
 	if err != nil {
+		return "", "", planNotFound(err)
+	}
+	organizationGUID := provisionOrganizationGUID(provisionData.Details)
+	if err := config.checkOrganizationAllowed(organizationGUID); err != nil {
+		return "", "", err
+	}
+	var parameters ProvisionParameters
+	if err := decodeStrictParameters(provisionData.Details.RawParameters, &parameters, config.LenientParameters); err != nil {
 		return "", "", err
 	}
-	ipFilter, err := ParseIPWhitelist(os.Getenv("IP_WHITELIST"))
+	tenantIPFilter, err := config.tenantIPFilter(parameters.IPFilter, parameters.IPFilterGroups)
 	if err != nil {
 		return "", "", err
 	}
+	ipFilter := config.ipFilterFor(config.IPWhitelist, tenantIPFilter)
+	if err := config.checkIPFilterAllowed(ipFilter); err != nil {
+		return "", "", err
+	}
+	if err := config.checkIPFilterSize(ipFilter); err != nil {
+		return "", "", err
+	}
+	ap.warnIfAllowsAllIPs(provisionData.InstanceID, ipFilter)
 
-	userConfig := aiven.UserConfig{}
-	userConfig.IPFilter = ipFilter
+	tenantUserConfig, err := config.tenantUserConfig(plan.ServiceType, parameters.UserConfig)
+	if err != nil {
+		return "", "", err
+	}
+	userConfig, err := buildUserConfig(plan.ServiceType, plan, ipFilter)
+	if err != nil {
+		return "", "", err
+	}
+	// Tenants' settings take precedence over the plan's, and the settings
+	// the broker manages over both.
+	userConfig.Defaults = mergeUserConfig(userConfig.Defaults, tenantUserConfig)
 
-	if provisionData.Service.Name == "elasticsearch" {
-		userConfig.ElasticsearchVersion = plan.ElasticsearchVersion
-	} else if provisionData.Service.Name == "influxdb" {
-		// Nothing to do
-	} else {
-		return "", "", fmt.Errorf(
-			"Cannot provision service for unknown service %s",
-			provisionData.Service.Name,
-		)
+	if err := config.checkCloudAllowed(parameters.Cloud); err != nil {
+		return "", "", err
+	}
+	if err := config.checkCloudForProjectVPC(plan, parameters.Cloud); err != nil {
+		return "", "", err
+	}
+	maintenance, err := maintenanceWindow(parameters.MaintenanceDOW, parameters.MaintenanceTime)
+	if err != nil {
+		return "", "", err
+	}
+	additionalDiskGB, err := additionalDiskSpaceGB(plan, parameters.AdditionalDiskSpaceGB)
+	if err != nil {
+		return "", "", err
+	}
+	userConfig.IndexPatterns, err = indexPatterns(plan.ServiceType, parameters.IndexPatterns)
+	if err != nil {
+		return "", "", err
+	}
+	techEmails, err := notificationEmails(parameters.NotificationEmails)
+	if err != nil {
+		return "", "", err
+	}
+	userConfig.PublicAccess, err = config.publicAccess(plan, parameters.PublicAccess)
+	if err != nil {
+		return "", "", err
+	}
+
+	serviceName := buildServiceName(config.ServiceNamePrefix, provisionData.InstanceID)
+	if err := ap.checkOrganizationQuota(ctx, organizationGUID, plan, serviceName); err != nil {
+		return "", "", err
+	}
+	if err := ap.checkProjectCapacity(ctx, config, config.ProjectForPlan(plan), serviceName); err != nil {
+		return "", "", err
 	}
 
 	createServiceInput := &aiven.CreateServiceInput{
-		Cloud:       ap.Config.Cloud,
-		Plan:        plan.AivenPlan,
-		ServiceName: buildServiceName(ap.Config.ServiceNamePrefix, provisionData.InstanceID),
-		ServiceType: provisionData.Service.Name,
-		UserConfig:  userConfig,
+		Project:               config.ProjectForPlan(plan),
+		Cloud:                 config.CloudForPlan(plan),
+		ProjectVPCID:          config.ProjectVPCIDForPlan(plan),
+		Plan:                  plan.AivenPlan,
+		ServiceName:           serviceName,
+		ServiceType:           aivenServiceType(plan.ServiceType),
+		Tags:                  serviceTags(organizationGUID, plan.ID),
+		Maintenance:           maintenance,
+		TechEmails:            techEmails,
+		TerminationProtection: parameters.TerminationProtection,
+		UserConfig:            userConfig,
+	}
+	if parameters.Cloud != "" {
+		createServiceInput.Cloud = parameters.Cloud
+		createServiceInput.Tags[cloudTag] = parameters.Cloud
+	}
+	if parameters.CloneFrom != "" && (parameters.RestoreFromInstance != "" || parameters.BackupName != "") {
+		return "", "", invalidParameters(errors.New("clone_from cannot be given with restore_from_instance or backup_name"))
+	}
+	if parameters.RestoreFromInstance != "" || parameters.BackupName != "" {
+		fork, err := ap.forkFrom(ctx, config, plan, parameters.RestoreFromInstance, parameters.BackupName)
+		if err != nil {
+			return "", "", err
+		}
+		createServiceInput.UserConfig.ServiceToForkFrom = fork.ServiceToForkFrom
+		createServiceInput.UserConfig.RecoveryBasebackupName = fork.RecoveryBasebackupName
 	}
-	_, err = ap.Client.CreateService(createServiceInput)
-	return dashboardURL, operationData, err
+	if parameters.CloneFrom != "" {
+		fork, err := ap.cloneFrom(ctx, config, plan, parameters.CloneFrom, organizationGUID, provisionData.Details.SpaceGUID)
+		if err != nil {
+			return "", "", err
+		}
+		createServiceInput.UserConfig.ServiceToForkFrom = fork.ServiceToForkFrom
+	}
+	if additionalDiskGB > 0 {
+		createServiceInput.DiskSpaceMB, err = ap.diskSpaceMB(ctx, createServiceInput.Project, plan, additionalDiskGB)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if parameters.StaticIPs {
+		staticIPIDs, err := ap.allocateStaticIPs(ctx, plan, createServiceInput.Project, createServiceInput.Cloud)
+		if err != nil {
+			return "", "", err
+		}
+		staticIPs := true
+		createServiceInput.StaticIPs = staticIPIDs
+		createServiceInput.UserConfig.StaticIPs = &staticIPs
+	}
+	created, recovery, err := ap.createService(ctx, config, createServiceInput, plan)
+	if !created {
+		// The addresses were only for a service this request created. One
+		// which already exists has its own from the earlier attempt.
+		ap.releaseStaticIPs(ctx, createServiceInput.Project, createServiceInput.StaticIPs)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	ap.tagOwner(ctx, createServiceInput, ownerTags(
+		provisionData.InstanceID, provisionData.Details.SpaceGUID, provisionData.Service.Name, plan.Name,
+	))
+	if plan.DiskAutoscalerCapGB > 0 {
+		err := ap.enableDiskAutoscaler(ctx, createServiceInput.Project, createServiceInput.ServiceName, plan.DiskAutoscalerCapGB)
+		if err != nil {
+			ap.logDiskAutoscalerError(createServiceInput.Project, createServiceInput.ServiceName, err)
+		}
+	}
+	dashboardURL = config.dashboardURL(createServiceInput.Project, createServiceInput.ServiceName)
+	provisionOperationData := ap.newOperationData(OperationProvision, plan)
+	if billingGroupID := config.BillingGroupIDForPlan(plan); billingGroupID != "" {
+		// The service is created either way, so the provision does not
+		// fail. Polling its last operation tries again.
+		if err := ap.assignBillingGroup(ctx, createServiceInput.Project, billingGroupID); err != nil {
+			provisionOperationData.BillingGroupID = billingGroupID
+		}
+	}
+	provisionOperationData.RestoreFromInstance = parameters.RestoreFromInstance
+	provisionOperationData.CloneFrom = parameters.CloneFrom
+	provisionOperationData.Recovery = recovery
+	return dashboardURL, provisionOperationData.encode(), nil
 }
 
-func (ap *AivenProvider) Deprovision(ctx context.Context, deprovisionData DeprovisionData) (operationData string, err error) {
-	err = ap.Client.DeleteService(&aiven.DeleteServiceInput{
-		ServiceName: buildServiceName(ap.Config.ServiceNamePrefix, deprovisionData.InstanceID),
-	})
+// checkProjectCapacity reports a project without room for another service
+// as a platform problem, rather than letting Aiven reject the service. The
+// check fails open, as Aiven still enforces the limit itself.
+func (ap *AivenProvider) checkProjectCapacity(ctx context.Context, config *Config, project, serviceName string) error {
+	if config.ProjectServiceLimit == 0 {
+		return nil
+	}
 
+	// Every service counts against the limit, including those of other
+	// brokers sharing the project, except the instance's own, which a
+	// retried provision has already created.
+	services, err := ap.Client.ListServices(ctx, &aiven.ListServicesInput{Project: project})
 	if err != nil {
-		if err == aiven.ErrInstanceDoesNotExist {
-			return "", brokerapi.ErrInstanceDoesNotExist
+		ap.Logger.Error("check-project-capacity", err, lager.Data{"project": project})
+		return nil
+	}
+	count := 0
+	for _, service := range services {
+		if service.ServiceName != serviceName {
+			count++
 		}
 	}
 
-	return "", err
+	if count >= config.ProjectServiceLimit {
+		return brokerapi.NewFailureResponseBuilder(
+			fmt.Errorf(
+				"Aiven project %s has reached its service limit of %d. This is a problem with the platform's capacity, not the request: contact your platform operators.",
+				project, config.ProjectServiceLimit,
+			),
+			http.StatusServiceUnavailable,
+			"project-service-limit-reached",
+		).WithErrorKey("ProjectServiceLimitReached").Build()
+	}
+	return nil
 }
 
-func (ap *AivenProvider) Bind(ctx context.Context, bindData BindData) (binding brokerapi.Binding, err error) {
-	serviceName := buildServiceName(ap.Config.ServiceNamePrefix, bindData.InstanceID)
-	user := bindData.BindingID
+// checkExistingService returns ErrInstanceAlreadyExists unless the service
+// which already has the instance's name is the one the request would create.
+func checkExistingService(service *aiven.Service, createServiceInput *aiven.CreateServiceInput, plan *Plan) error {
+	if service.ServiceType != createServiceInput.ServiceType ||
+		service.Plan != createServiceInput.Plan ||
+		service.Tags[planIDTag] != plan.ID {
+		return brokerapi.ErrInstanceAlreadyExists
+	}
+	if version := service.UserConfig.EngineVersion(service.ServiceType); version != "" && version != plan.engineVersion() {
+		return brokerapi.ErrInstanceAlreadyExists
+	}
+	if !sameIPFilter(service.UserConfig.IPFilter, createServiceInput.UserConfig.IPFilter) {
+		return brokerapi.ErrInstanceAlreadyExists
+	}
+	return nil
+}
 
-	password, err := ap.Client.CreateServiceUser(&aiven.CreateServiceUserInput{
-		ServiceName: serviceName,
-		Username:    user,
-	})
-	if err != nil {
-		return brokerapi.Binding{}, err
+func sameIPFilter(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := ipFilterKeys(a)
+	sortedB := ipFilterKeys(b)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func serviceTags(organizationGUID, planID string) map[string]string {
+	tags := map[string]string{planIDTag: planID}
+	if organizationGUID != "" {
+		tags[organizationGUIDTag] = organizationGUID
 	}
+	return tags
+}
 
-	service, err := ap.Client.GetService(&aiven.GetServiceInput{
-		ServiceName: serviceName,
+func ownerTags(instanceID, spaceGUID, serviceName, planName string) map[string]string {
+	tags := map[string]string{}
+	for key, value := range map[string]string{
+		instanceGUIDTag: instanceID,
+		spaceGUIDTag:    spaceGUID,
+		serviceNameTag:  serviceName,
+		planNameTag:     planName,
+	} {
+		if value != "" {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// tagOwner adds the owner tags to a new service. They are only there to help
+// operators, so they are added after the service is created, and failing to
+// add them does not fail the provision.
+func (ap *AivenProvider) tagOwner(ctx context.Context, createServiceInput *aiven.CreateServiceInput, owner map[string]string) {
+	tags := map[string]string{}
+	for key, value := range createServiceInput.Tags {
+		tags[key] = value
+	}
+	for key, value := range owner {
+		tags[key] = value
+	}
+
+	err := ap.Client.UpdateServiceTags(ctx, &aiven.UpdateServiceTagsInput{
+		Project:     createServiceInput.Project,
+		ServiceName: createServiceInput.ServiceName,
+		Tags:        tags,
 	})
 	if err != nil {
-		return brokerapi.Binding{}, err
+		ap.Logger.Error("tag-owner", err, lager.Data{
+			"service": createServiceInput.ServiceName,
+			"tags":    owner,
+		})
 	}
+}
 
-	host := service.ServiceUriParams.Host
-	port := service.ServiceUriParams.Port
-	serviceType := service.ServiceType
+// provisionOrganizationGUID is the organization the instance is being
+// created in. Platforms which only send it in the context, as the OSB API
+// now prefers, are supported too.
+func provisionOrganizationGUID(details brokerapi.ProvisionDetails) string {
+	if details.OrganizationGUID != "" {
+		return details.OrganizationGUID
+	}
+	var platformContext struct {
+		OrganizationGUID string `json:"organization_guid"`
+	}
+	if len(details.RawContext) > 0 {
+		_ = json.Unmarshal(details.RawContext, &platformContext)
+	}
+	return platformContext.OrganizationGUID
+}
 
-	if host == "" || port == "" {
-		return brokerapi.Binding{}, errors.New(
-			"Error getting service connection details: no connection details found in response JSON",
-		)
+// checkOrganizationAllowed stops organizations which are not on the allowed
+// list creating instances. Existing instances are not affected.
+func (c *Config) checkOrganizationAllowed(organizationGUID string) error {
+	if len(c.AllowedOrgGUIDs) == 0 || contains(c.AllowedOrgGUIDs, organizationGUID) {
+		return nil
 	}
+	return brokerapi.NewFailureResponseBuilder(
+		fmt.Errorf("Organization %s is not allowed to create instances of this service", organizationGUID),
+		http.StatusForbidden,
+		"organization-not-allowed",
+	).WithErrorKey("OrganizationNotAllowed").Build()
+}
 
-	credentials, err := BuildCredentials(serviceType, user, password, host, port)
+func (ap *AivenProvider) checkOrganizationQuota(ctx context.Context, organizationGUID string, plan *Plan, serviceName string) error {
+	config := ap.currentConfig()
+	if plan.OrganizationQuota == 0 {
+		return nil
+	}
+
+	services, err := ap.Client.ListServices(ctx, &aiven.ListServicesInput{
+		Project: config.ProjectForPlan(plan),
+	})
 	if err != nil {
-		return brokerapi.Binding{}, err
+		return err
 	}
 
-	if err = ensureUserAvailability(ctx, serviceType, credentials); err != nil {
-		// Polling is only a best-effort attempt to work around Aiven API delays.
-		// We therefore continue anyway if it times out.
-		if err != context.DeadlineExceeded {
-			return brokerapi.Binding{}, err
+	// The project may be shared with other brokers, so only count our own
+	// services. The instance's own service is not counted, so that retried
+	// requests for it succeed.
+	servicePrefix := buildServiceName(config.ServiceNamePrefix, "")
+	instances := 0
+	for _, service := range services {
+		if strings.HasPrefix(service.ServiceName, servicePrefix) &&
+			service.ServiceName != serviceName &&
+			service.Tags[organizationGUIDTag] == organizationGUID &&
+			service.Tags[planIDTag] == plan.ID {
+			instances++
 		}
 	}
 
-	return brokerapi.Binding{
-		Credentials: credentials,
-	}, nil
+	if instances >= plan.OrganizationQuota {
+		return brokerapi.NewFailureResponseBuilder(
+			fmt.Errorf(
+				"The %s plan is limited to %d instance(s) per organization. Delete an existing instance of it before creating another.",
+				plan.Name, plan.OrganizationQuota,
+			),
+			http.StatusUnprocessableEntity,
+			"organization-quota-exceeded",
+		).WithErrorKey("OrganizationQuotaExceeded").Build()
+	}
+	return nil
 }
 
-func ensureUserAvailability(
-	ctx context.Context,
-	serviceType string,
-	credentials Credentials,
-) error {
-	if serviceType == "elasticsearch" {
-		return tryAvailability(ctx, func() error {
-			client := elastic.New(credentials.URI, nil)
-			_, err := client.Version()
-			return err
-		})
-	} else if serviceType == "influxdb" {
-		return tryAvailability(ctx, func() error {
-			client := influxdb.New(credentials.URI, nil)
-			_, err := client.Ping()
-			return err
+// warnIfAllowsAllIPs logs instances being opened to all IP addresses, which
+// should only happen in development environments.
+func (ap *AivenProvider) warnIfAllowsAllIPs(instanceID string, ipFilter []string) {
+	if allowsAllIPs(ipFilter) {
+		ap.Logger.Info("ip-filter-allows-all", lager.Data{
+			"instance-id": instanceID,
+			"ip-filter":   ipFilter,
 		})
-	} else {
-		return fmt.Errorf(
-			"Cannot ensure availability for unknown service %s", serviceType,
-		)
 	}
 }
 
-func tryAvailability(
-	ctx context.Context,
-	availabilityCheck func() error,
-) error {
-	if availabilityCheck() == nil {
-		return nil
-	}
+func buildUserConfig(serviceType string, plan *Plan, ipFilter []string) (aiven.UserConfig, error) {
+	userConfig := aiven.UserConfig{}
+	userConfig.IPFilter = ipFilter
+	userConfig.Defaults = mergeUserConfig(plan.UserConfig, featureUserConfig(plan))
+	userConfig.BackupHour = plan.BackupHour
+	userConfig.BackupMinute = plan.BackupMinute
 
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
-	for {
-		select {
-		case <-ticker.C:
-			if availabilityCheck() == nil {
-				return nil
-			}
-		case <-ctx.Done():
-			return ctx.Err()
+	switch serviceType {
+	case "elasticsearch":
+		userConfig.ElasticsearchVersion = plan.ElasticsearchVersion
+	case "influxdb":
+		// Nothing to do
+	case "kafka":
+		userConfig.KafkaVersion = plan.KafkaVersion
+		userConfig.KafkaRest = &plan.KafkaRest
+		userConfig.KafkaConnect = &plan.KafkaConnect
+		// Bindings hand out SASL credentials, so the SASL listener must be
+		// enabled alongside Aiven's default client certificate listener.
+		userConfig.KafkaAuthenticationMethods = &aiven.KafkaAuthenticationMethods{
+			Certificate: true,
+			SASL:        true,
 		}
+	case "opensearch":
+		userConfig.OpenSearchVersion = plan.OpenSearchVersion
+	case "postgres":
+		userConfig.PGVersion = plan.PGVersion
+	case "redis":
+		userConfig.RedisMaxmemoryPolicy = plan.RedisMaxmemoryPolicy
+	default:
+		return aiven.UserConfig{}, fmt.Errorf("Cannot provision service for unknown service %s", serviceType)
 	}
+
+	return userConfig, nil
 }
 
-func (ap *AivenProvider) Unbind(ctx context.Context, unbindData UnbindData) (err error) {
-	_, err = ap.Client.DeleteServiceUser(&aiven.DeleteServiceUserInput{
-		ServiceName: buildServiceName(ap.Config.ServiceNamePrefix, unbindData.InstanceID),
-		Username:    unbindData.BindingID,
-	})
-	return err
+// aivenServiceType maps the service types used in the catalog onto the
+// names Aiven uses for them, where the two differ.
+func aivenServiceType(serviceType string) string {
+	if serviceType == "postgres" {
+		return "pg"
+	}
+	return serviceType
 }
 
-func (ap *AivenProvider) Update(ctx context.Context, updateData UpdateData) (operationData string, err error) {
-	plan, err := ap.Config.FindPlan(updateData.Details.ServiceID, updateData.Details.PlanID)
+// projectForInstance returns the project instances of the plan are created
+// in. Plans which are no longer in the config fall back to the default.
+func (ap *AivenProvider) projectForInstance(serviceID, planID string) string {
+	config := ap.currentConfig()
+	plan, err := config.FindPlan(serviceID, planID)
 	if err != nil {
-		return "", err
+		return config.Project
 	}
+	return config.ProjectForPlan(plan)
+}
 
-	ipFilter, err := ParseIPWhitelist(os.Getenv("IP_WHITELIST"))
+func (ap *AivenProvider) Deprovision(ctx context.Context, deprovisionData DeprovisionData) (operationData string, err error) {
+	defer func() { err = abandonedRequest(err) }()
+	project := ap.projectForInstance(deprovisionData.Service.ID, deprovisionData.Plan.ID)
+	serviceName := buildServiceName(ap.currentConfig().ServiceNamePrefix, deprovisionData.InstanceID)
+	defer ap.invalidateCachedService(serviceName)
+
+	// Static IPs outlive their service, and are billed until they are
+	// released, so they are found while the service still names them.
+	staticIPs, err := ap.serviceStaticIPs(ctx, project, serviceName)
 	if err != nil {
 		return "", err
 	}
 
-	userConfig := aiven.UserConfig{}
-	userConfig.IPFilter = ipFilter
-	userConfig.ElasticsearchVersion = plan.ElasticsearchVersion // Pass empty version through if not InfluxDB
-
-	_, err = ap.Client.UpdateService(&aiven.UpdateServiceInput{
-		ServiceName: buildServiceName(ap.Config.ServiceNamePrefix, updateData.InstanceID),
-		Plan:        plan.AivenPlan,
-		UserConfig:  userConfig,
+	err = ap.Client.DeleteService(ctx, &aiven.DeleteServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
 	})
 
-	switch err := err.(type) {
-	case aiven.ErrInvalidUpdate:
-		return "", brokerapi.NewFailureResponseBuilder(
-			err,
-			http.StatusUnprocessableEntity,
-			"plan-change-not-supported",
-		).WithErrorKey("PlanChangeNotSupported").Build()
-	default:
+	if err != nil {
+		if err == aiven.ErrInstanceDoesNotExist {
+			return "", brokerapi.ErrInstanceDoesNotExist
+		}
+		if err == aiven.ErrTerminationProtectionEnabled {
+			return "", brokerapi.NewFailureResponse(
+				errors.New(
+					"Service instance has termination protection enabled, so it cannot be deleted. "+
+						`Disable it first with: cf update-service SERVICE_INSTANCE -c '{"termination_protection": false}'`,
+				),
+				http.StatusUnprocessableEntity,
+				"termination-protection-enabled",
+			)
+		}
 		return "", err
 	}
+
+	ap.releaseStaticIPs(ctx, project, staticIPIDs(staticIPs))
+
+	return ap.newOperationData(OperationDeprovision, nil).encode(), nil
 }
 
-func (ap *AivenProvider) LastOperation(
-	ctx context.Context,
-	lastOperationData LastOperationData,
-) (state brokerapi.LastOperationState, description string, err error) {
-	serviceName := buildServiceName(
-		ap.Config.ServiceNamePrefix,
-		lastOperationData.InstanceID,
-	)
+func (ap *AivenProvider) Bind(ctx context.Context, bindData BindData) (binding brokerapi.Binding, err error) {
+	defer func() { err = abandonedRequest(err) }()
+	binding, err = ap.bind(ctx, bindData)
+	if err != nil || ap.CredHub == nil {
+		return binding, err
+	}
 
-	service, err := ap.Client.GetService(&aiven.GetServiceInput{
-		ServiceName: serviceName,
-	})
+	credhubRef, err := ap.storeCredentials(bindData, binding.Credentials)
+	if err != nil {
+		// Nobody would know the password of the binding's user, so it is
+		// removed rather than left behind.
+		ap.Unbind(ctx, UnbindData{
+			InstanceID: bindData.InstanceID,
+			BindingID:  bindData.BindingID,
+			Details: brokerapi.UnbindDetails{
+				ServiceID: bindData.Details.ServiceID,
+				PlanID:    bindData.Details.PlanID,
+			},
+		})
+		return brokerapi.Binding{}, err
+	}
+	binding.Credentials = credhubRef
+	return binding, nil
+}
+
+func (ap *AivenProvider) bind(ctx context.Context, bindData BindData) (binding brokerapi.Binding, err error) {
+	config := ap.currentConfig()
+	serviceName := buildServiceName(config.ServiceNamePrefix, bindData.InstanceID)
+	project := ap.projectForInstance(bindData.Details.ServiceID, bindData.Details.PlanID)
+	user := serviceUsername(bindData.BindingID)
+
+	var parameters BindParameters
+	if err := decodeParameters(bindData.Details.RawParameters, &parameters); err != nil {
+		return brokerapi.Binding{}, err
+	}
+	switch parameters.CredentialType {
+	case "":
+	case CredentialTypePrometheus:
+		if parameters.Permission != "" || parameters.IndexPrefix != "" || parameters.Username != "" || parameters.TTL != "" ||
+			parameters.OwnDatabase || parameters.Pooled || parameters.PoolSize != nil || parameters.Access != "" {
+			return brokerapi.Binding{}, invalidParameters(errors.New("credential_type prometheus cannot be combined with other parameters"))
+		}
+		return ap.bindPrometheus(ctx, project, serviceName, bindData.BindingID)
+	default:
+		return brokerapi.Binding{}, invalidParameters(fmt.Errorf("Invalid credential_type: %s, must be %s", parameters.CredentialType, CredentialTypePrometheus))
+	}
 
+	aclRules, err := config.bindingACLRules(bindData.Details.ServiceID, bindData.Details.PlanID, parameters)
 	if err != nil {
-		return "", "", err
+		return brokerapi.Binding{}, err
 	}
 
-	status := service.State
-	updateTime := service.UpdateTime
+	if parameters.Username != "" {
+		if err := config.validateCustomUsername(parameters.Username); err != nil {
+			return brokerapi.Binding{}, err
+		}
+		user = customServiceUsername(parameters.Username, bindData.BindingID)
+	}
 
-	if updateTime.After(time.Now().Add(-1 * 60 * time.Second)) {
-		return brokerapi.InProgress, "Preparing to apply update", nil
+	ttl, err := parseTTL(parameters.TTL)
+	if err != nil {
+		return brokerapi.Binding{}, err
 	}
 
-	lastOperationState, description := providerStatesMapping(status)
-	return lastOperationState, description, nil
-}
+	poolSize, err := parsePoolSize(parameters)
+	if err != nil {
+		return brokerapi.Binding{}, err
+	}
 
-func ParseIPWhitelist(ips string) ([]string, error) {
-	if ips == "" {
-		return []string{}, nil
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return brokerapi.Binding{}, err
 	}
-	outIPs := []string{}
-	for _, ip := range strings.Split(ips, ",") {
-		if len(strings.Split(ip, ".")) != 4 {
-			return []string{}, fmt.Errorf("malformed whitelist IP: %v", ip)
+	service, err = ap.waitForService(ctx, project, serviceName, service)
+	if err != nil {
+		return brokerapi.Binding{}, err
+	}
+
+	if parameters.OwnDatabase && service.ServiceType != "pg" {
+		return brokerapi.Binding{}, invalidParameters(fmt.Errorf("own_database is not supported by %s services", service.ServiceType))
+	}
+	if parameters.Pooled && service.ServiceType != "pg" {
+		return brokerapi.Binding{}, invalidParameters(fmt.Errorf("pooled is not supported by %s services", service.ServiceType))
+	}
+	if parameters.Access == AccessDashboards {
+		component, name := dashboardsComponent(service.ServiceType)
+		if host, _ := serviceComponentEndpoint(service, component); host == "" {
+			return brokerapi.Binding{}, invalidParameters(fmt.Errorf("access dashboards is not supported by plans without %s", name))
 		}
-		outIPs = append(outIPs, ip)
 	}
-	return outIPs, nil
-}
 
-func buildServiceName(prefix, guid string) string {
-	return strings.ToLower(prefix + "-" + guid)
+	if parameters.Username != "" {
+		if err := checkUsernameConflict(service, parameters.Username, bindData.BindingID); err != nil {
+			return brokerapi.Binding{}, err
+		}
+	}
+
+	if existingUser, found := findBindingUser(service, bindData.BindingID); found {
+		// A retried bind keeps the user it created before, and with it the
+		// expiry of bindings with a ttl.
+		user = existingUser
+	} else {
+		if err := ap.checkMaxBindings(ctx, config, bindData.Details.ServiceID, bindData.Details.PlanID, project, serviceName); err != nil {
+			return brokerapi.Binding{}, err
+		}
+		if ttl > 0 {
+			user = expiringServiceUsername(parameters.Username, bindData.BindingID, ap.now().Add(ttl))
+		}
+	}
+
+	password, createUserErr := ap.Client.CreateServiceUser(ctx, &aiven.CreateServiceUserInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Username:    user,
+	})
+	// Users are named after their binding, so one which already exists is
+	// usually from a bind request the platform retried.
+	alreadyExists := createUserErr == aiven.ErrServiceUserAlreadyExists
+	if alreadyExists {
+		var existingUser *aiven.User
+		existingUser, createUserErr = ap.Client.GetServiceUser(ctx, &aiven.GetServiceUserInput{
+			Project:     project,
+			ServiceName: serviceName,
+			Username:    user,
+		})
+		if createUserErr == nil {
+			password = existingUser.Password
+		}
+	}
+	if createUserErr != nil && createUserErr != aiven.ErrServiceUserNotSupported {
+		return brokerapi.Binding{}, createUserErr
+	}
+
+	if createUserErr == aiven.ErrServiceUserNotSupported {
+		// Some Redis plans only have the built-in default user, so every
+		// binding shares it. Unbind still tries to delete a user named after
+		// the binding, which the platform is told no longer exists.
+		if service.ServiceType != "redis" {
+			return brokerapi.Binding{}, createUserErr
+		}
+		if ttl > 0 {
+			return brokerapi.Binding{}, invalidParameters(errors.New("ttl is not supported by plans whose bindings share the default user"))
+		}
+		user = service.ServiceUriParams.User
+		password = service.ServiceUriParams.Password
+	}
+
+	if supportsACLs(service.ServiceType) {
+		if err := ap.grantACL(ctx, project, serviceName, service, user, aclRules); err != nil {
+			// The user would otherwise be left with more access than the
+			// binding asked for. A user an earlier attempt at the bind
+			// created is kept, as the platform may already have its
+			// credentials.
+			if !alreadyExists {
+				ap.Client.DeleteServiceUser(ctx, &aiven.DeleteServiceUserInput{
+					Project:     project,
+					ServiceName: serviceName,
+					Username:    user,
+				})
+			}
+			return brokerapi.Binding{}, err
+		}
+	}
+
+	if parameters.OwnDatabase || parameters.Pooled {
+		if err := ap.setUpPostgresBinding(ctx, project, serviceName, service, user, parameters, poolSize); err != nil {
+			// The user is removed too, rather than leaving a binding which
+			// only half works.
+			if !alreadyExists {
+				ap.Client.DeleteServiceUser(ctx, &aiven.DeleteServiceUserInput{
+					Project:     project,
+					ServiceName: serviceName,
+					Username:    user,
+				})
+			}
+			return brokerapi.Binding{}, err
+		}
+	}
+
+	if createUserErr == nil {
+		ap.tagAppGUID(ctx, project, serviceName, service, user, bindAppGUID(bindData.Details))
+	}
+
+	if bindData.AsyncAllowed && createUserErr == nil && !alreadyExists && ap.CredHub == nil {
+		// New users can take a while to become available on busy services,
+		// so the platform polls LastBindingOperation for them instead, and
+		// then fetches the credentials with GetBinding. Credentials stored
+		// in CredHub need the app GUID of the bind request, so those
+		// bindings are not asynchronous.
+		return brokerapi.Binding{
+			IsAsync:       true,
+			OperationData: bindOperation,
+		}, nil
+	}
+
+	serviceUser := &aiven.User{Username: user, Password: password}
+	if service.ServiceType == "kafka" && createUserErr == nil {
+		// The user's client certificate is only returned when it is
+		// fetched.
+		serviceUser, err = ap.Client.GetServiceUser(ctx, &aiven.GetServiceUserInput{
+			Project:     project,
+			ServiceName: serviceName,
+			Username:    user,
+		})
+		if err != nil {
+			return brokerapi.Binding{}, err
+		}
+	}
+
+	credentials, err := ap.bindingCredentials(ctx, project, service, serviceUser)
+	if err != nil {
+		return brokerapi.Binding{}, err
+	}
+
+	if !contains(config.SkipBindAvailabilityCheck, service.ServiceType) {
+		availabilityCtx, cancel := context.WithTimeout(ctx, config.bindAvailabilityTimeout())
+		defer cancel()
+		if err = ensureUserAvailability(availabilityCtx, service.ServiceType, credentials); err != nil {
+			// Polling is only a best-effort attempt to work around Aiven API delays.
+			// We therefore continue anyway if it times out.
+			if err != context.DeadlineExceeded {
+				return brokerapi.Binding{}, err
+			}
+			ap.Logger.Info("bind-availability-timeout", lager.Data{
+				"instance-id": bindData.InstanceID,
+				"binding-id":  bindData.BindingID,
+			})
+		}
+	}
+
+	if parameters.Access == AccessDashboards {
+		return brokerapi.Binding{
+			Credentials:   dashboardsCredentials(credentials),
+			AlreadyExists: alreadyExists,
+		}, nil
+	}
+	return brokerapi.Binding{
+		Credentials:   credentials,
+		AlreadyExists: alreadyExists,
+	}, nil
+}
+
+// checkMaxBindings refuses new bindings of instances which already have as
+// many binding users as their plan allows.
+func (ap *AivenProvider) checkMaxBindings(ctx context.Context, config *Config, serviceID, planID, project, serviceName string) error {
+	plan, err := config.FindPlan(serviceID, planID)
+	if err != nil || plan.MaxBindings == 0 {
+		return nil
+	}
+
+	users, err := ap.Client.ListServiceUsers(ctx, &aiven.ListServiceUsersInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return err
+	}
+	bindings := 0
+	for _, user := range users {
+		// Aiven's built-in users, such as avnadmin and Redis' default user,
+		// do not belong to bindings.
+		if user.Type == "primary" {
+			continue
+		}
+		bindings++
+	}
+
+	if bindings >= plan.MaxBindings {
+		return brokerapi.NewFailureResponseBuilder(
+			fmt.Errorf(
+				"The %s plan is limited to %d binding(s) per instance. Unbind an existing binding before creating another.",
+				plan.Name, plan.MaxBindings,
+			),
+			http.StatusUnprocessableEntity,
+			"max-bindings-exceeded",
+		).WithErrorKey("MaxBindingsExceeded").Build()
+	}
+	return nil
+}
+
+// credhubName is where the binding's credentials are stored in CredHub.
+func (ap *AivenProvider) credhubName(instanceID, bindingID string) string {
+	return fmt.Sprintf("/c/%s/%s/%s/credentials", ap.currentConfig().CredHub.UAAClientName, instanceID, bindingID)
+}
+
+func credhubRef(name string) map[string]string {
+	return map[string]string{"credhub-ref": name}
+}
+
+// storeCredentials writes the credentials to CredHub, where the bound app can
+// read them, and returns the reference which replaces them in the binding.
+func (ap *AivenProvider) storeCredentials(bindData BindData, credentials interface{}) (map[string]string, error) {
+	name := ap.credhubName(bindData.InstanceID, bindData.BindingID)
+	if err := ap.CredHub.SetJSON(name, credentials); err != nil {
+		return nil, err
+	}
+	// Service keys have no app, so only the platform can read them.
+	if bindData.Details.AppGUID != "" {
+		if err := ap.CredHub.AddReadPermission(name, "mtls-app:"+bindData.Details.AppGUID); err != nil {
+			return nil, err
+		}
+	}
+	return credhubRef(name), nil
+}
+
+// bindOperation is the operation data of asynchronous bindings.
+const bindOperation = "bind"
+
+// bindingCredentials builds the credentials a user has for the service.
+func (ap *AivenProvider) bindingCredentials(ctx context.Context, project string, service *aiven.Service, serviceUser *aiven.User) (Credentials, error) {
+	user, password := serviceUser.Username, serviceUser.Password
+	host := service.ServiceUriParams.Host
+	port := service.ServiceUriParams.Port
+	serviceType := service.ServiceType
+
+	if serviceType == "kafka" {
+		host, port = kafkaSASLEndpoint(service)
+	}
+
+	if host == "" || port == "" {
+		return Credentials{}, errNoConnectionDetails
+	}
+
+	privateHost, privatePort := host, port
+	publicHost, publicPort := publicEndpoint(service)
+	if publicHost != "" && publicPort != "" {
+		host, port = publicHost, publicPort
+	}
+
+	credentials, err := BuildCredentials(serviceType, user, password, host, port)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	if host != privateHost || port != privatePort {
+		privateCredentials, err := BuildCredentials(serviceType, user, password, privateHost, privatePort)
+		if err != nil {
+			return Credentials{}, err
+		}
+		credentials.PublicURI = credentials.URI
+		credentials.PrivateURI = privateCredentials.URI
+	}
+
+	// Kafka clients are given every broker in hosts instead.
+	if serviceType != "kafka" {
+		readHost, readPort := replicaEndpoint(service)
+		if readHost == "" || readPort == "" {
+			readHost, readPort = host, port
+		}
+		if err := addReadCredentials(&credentials, readHost, readPort); err != nil {
+			return Credentials{}, err
+		}
+	}
+
+	if serviceType == "kafka" && serviceUser.AccessCert != "" {
+		sslHost, sslPort := kafkaCertificateEndpoint(service)
+		if sslHost != "" && sslPort != "" {
+			addKafkaCertificateCredentials(&credentials, sslHost, sslPort, serviceUser.AccessCert, serviceUser.AccessKey)
+		}
+	}
+
+	caCertificate, err := ap.projectCA(ctx, project)
+	if err != nil {
+		// Most clients trust Aiven's CA already, so the binding is still
+		// usable without it.
+		ap.Logger.Error("get-project-ca", err, lager.Data{"project": project})
+	} else {
+		credentials.CACertificate = caCertificate
+	}
+
+	if service.UserConfig.StaticIPs {
+		staticIPs, err := ap.serviceStaticIPs(ctx, project, service.ServiceName)
+		if err != nil {
+			// The service is reachable without knowing its addresses.
+			ap.Logger.Error("list-static-ips", err, lager.Data{"project": project})
+		} else {
+			for _, staticIP := range staticIPs {
+				credentials.StaticIPs = append(credentials.StaticIPs, staticIP.IPAddress)
+			}
+		}
+	}
+
+	// Dashboards are left out for plans which do not have them.
+	if serviceType == "elasticsearch" {
+		kibanaHost, kibanaPort := serviceComponentEndpoint(service, "kibana")
+		if kibanaHost != "" && kibanaPort != "" {
+			addKibanaCredentials(&credentials, kibanaHost, kibanaPort)
+		}
+	}
+	if serviceType == "opensearch" {
+		dashboardsHost, dashboardsPort := serviceComponentEndpoint(service, "opensearch_dashboards")
+		if dashboardsHost != "" && dashboardsPort != "" {
+			addOpenSearchDashboardsCredentials(&credentials, dashboardsHost, dashboardsPort)
+		}
+	}
+
+	if hasBindingDatabase(service, user) {
+		if err := setPostgresDatabase(&credentials, bindingDatabaseName(user)); err != nil {
+			return Credentials{}, err
+		}
+	}
+	if hasBindingPool(service, user) {
+		poolerHost, poolerPort := poolerEndpoint(service)
+		if poolerHost == "" || poolerPort == "" {
+			return Credentials{}, errors.New("Error getting connection pool details: no pgbouncer component found in response JSON")
+		}
+		if err := addPooledCredentials(&credentials, poolerHost, poolerPort, bindingPoolName(user)); err != nil {
+			return Credentials{}, err
+		}
+	}
+
+	credentials.Version = service.UserConfig.EngineVersion(serviceType)
+
+	if ap.currentConfig().IncludeAivenMetadata {
+		credentials.Aiven = &AivenMetadata{
+			Project:     project,
+			ServiceName: service.ServiceName,
+			ServiceType: service.ServiceType,
+			Cloud:       service.CloudName,
+			Plan:        service.Plan,
+		}
+	}
+
+	if expiry, ok := usernameExpiry(user); ok {
+		credentials.ExpiresAt = expiry.UTC().Format(time.RFC3339)
+	}
+
+	return credentials, nil
+}
+
+var errNoConnectionDetails = errors.New(
+	"Error getting service connection details: no connection details found in response JSON",
+)
+
+// userCredentials fetches the binding's user, and builds its credentials.
+// Redis services which only have the default user share it between bindings.
+func (ap *AivenProvider) userCredentials(ctx context.Context, project, serviceName string, service *aiven.Service, username string) (Credentials, bool, error) {
+	user, err := ap.Client.GetServiceUser(ctx, &aiven.GetServiceUserInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Username:    username,
+	})
+	if err == aiven.ErrServiceUserDoesNotExist {
+		if service.ServiceType == "redis" && len(service.Users) <= 1 && service.ServiceUriParams.User != "" {
+			credentials, err := ap.bindingCredentials(ctx, project, service, &aiven.User{
+				Username: service.ServiceUriParams.User,
+				Password: service.ServiceUriParams.Password,
+			})
+			return credentials, true, err
+		}
+		return Credentials{}, false, nil
+	}
+	if err != nil {
+		return Credentials{}, false, err
+	}
+
+	credentials, err := ap.bindingCredentials(ctx, project, service, user)
+	return credentials, true, err
+}
+
+func (ap *AivenProvider) projectCA(ctx context.Context, project string) (string, error) {
+	ap.caLock.Lock()
+	defer ap.caLock.Unlock()
+
+	if caCertificate, ok := ap.caCertificates[project]; ok {
+		return caCertificate, nil
+	}
+	caCertificate, err := ap.Client.GetProjectCA(ctx, &aiven.GetProjectCAInput{Project: project})
+	if err != nil {
+		return "", err
+	}
+	if ap.caCertificates == nil {
+		ap.caCertificates = map[string]string{}
+	}
+	ap.caCertificates[project] = caCertificate
+	return caCertificate, nil
+}
+
+func kafkaSASLEndpoint(service *aiven.Service) (host, port string) {
+	for _, component := range service.Components {
+		if component.Component == "kafka" && component.KafkaAuthenticationMethod == "sasl" && component.Route != "public" {
+			return component.Host, strconv.Itoa(component.Port)
+		}
+	}
+	return "", ""
+}
+
+func kafkaCertificateEndpoint(service *aiven.Service) (host, port string) {
+	for _, component := range service.Components {
+		if component.Component == "kafka" && component.KafkaAuthenticationMethod == "certificate" {
+			return component.Host, strconv.Itoa(component.Port)
+		}
+	}
+	return "", ""
+}
+
+// replicaEndpoint finds the service's read replica endpoint, preferring the
+// dynamic route which follows the replica if it moves.
+func replicaEndpoint(service *aiven.Service) (host, port string) {
+	for _, route := range []string{"dynamic", ""} {
+		for _, component := range service.Components {
+			if component.Component == service.ServiceType && component.Usage == "replica" &&
+				(route == "" || component.Route == route) {
+				return component.Host, strconv.Itoa(component.Port)
+			}
+		}
+	}
+	return "", ""
+}
+
+func serviceComponentEndpoint(service *aiven.Service, name string) (host, port string) {
+	for _, component := range service.Components {
+		if component.Component == name {
+			return component.Host, strconv.Itoa(component.Port)
+		}
+	}
+	return "", ""
+}
+
+func ensureUserAvailability(
+	ctx context.Context,
+	serviceType string,
+	credentials Credentials,
+) error {
+	availabilityCheck, err := userAvailabilityCheck(serviceType, credentials)
+	if err != nil || availabilityCheck == nil {
+		return err
+	}
+	return tryAvailability(ctx, availabilityCheck)
+}
+
+// userAvailabilityCheck returns a check which succeeds once the user can
+// connect to the service, or nil if the service cannot be checked.
+func userAvailabilityCheck(serviceType string, credentials Credentials) (func() error, error) {
+	if serviceType == "elasticsearch" || serviceType == "opensearch" {
+		// OpenSearch still reports its version in the Elasticsearch format
+		return func() error {
+			client := elastic.New(credentials.URI, nil)
+			_, err := client.Version()
+			return err
+		}, nil
+	} else if serviceType == "influxdb" {
+		return func() error {
+			client := influxdb.New(credentials.URI, nil)
+			_, err := client.Ping()
+			return err
+		}, nil
+	} else if serviceType == "kafka" || serviceType == "pg" || serviceType == "redis" {
+		// There is no lightweight HTTP endpoint to poll for these services,
+		// so we rely on Aiven having propagated the user by the time it is used.
+		return nil, nil
+	} else {
+		return nil, fmt.Errorf(
+			"Cannot ensure availability for unknown service %s", serviceType,
+		)
+	}
+}
+
+// The availability check backs off between these intervals, so that users
+// which are ready quickly are found quickly without hammering the service.
+const (
+	minAvailabilityInterval = 250 * time.Millisecond
+	maxAvailabilityInterval = 4 * time.Second
+)
+
+func tryAvailability(
+	ctx context.Context,
+	availabilityCheck func() error,
+) error {
+	if availabilityCheck() == nil {
+		return nil
+	}
+
+	interval := minAvailabilityInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if availabilityCheck() == nil {
+				return nil
+			}
+			interval *= 2
+			if interval > maxAvailabilityInterval {
+				interval = maxAvailabilityInterval
+			}
+			timer.Reset(interval)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// serviceBuildTimeout is how long Bind waits for a service which is still
+// being built, so that binding straight after creating an instance works.
+const serviceBuildTimeout = 10 * time.Second
+
+var errServiceRebuilding = errors.New("service is rebuilding")
+
+// waitForService waits briefly for a service which is still being built to
+// start running, and fails the bindings of services which cannot be used.
+// Services in other states are returned as they are.
+func (ap *AivenProvider) waitForService(ctx context.Context, project, serviceName string, service *aiven.Service) (*aiven.Service, error) {
+	if service.State == aiven.Rebuilding {
+		waitCtx, cancel := context.WithTimeout(ctx, serviceBuildTimeout)
+		defer cancel()
+		err := tryAvailability(waitCtx, func() error {
+			latest, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+				Project:     project,
+				ServiceName: serviceName,
+			})
+			if err != nil {
+				return err
+			}
+			service = latest
+			if service.State == aiven.Rebuilding {
+				return errServiceRebuilding
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, brokerapi.NewFailureResponseBuilder(
+				errors.New("Service instance is still being created or updated, try again shortly"),
+				http.StatusUnprocessableEntity,
+				"service-rebuilding",
+			).WithErrorKey("ConcurrencyError").Build()
+		}
+	}
+
+	if service.State == aiven.PowerOff {
+		return nil, brokerapi.NewFailureResponse(
+			errors.New("Service instance is powered off, so it cannot be bound"),
+			http.StatusUnprocessableEntity,
+			"service-powered-off",
+		)
+	}
+	return service, nil
+}
+
+func (ap *AivenProvider) Unbind(ctx context.Context, unbindData UnbindData) (err error) {
+	defer func() { err = abandonedRequest(err) }()
+	config := ap.currentConfig()
+	project := ap.projectForInstance(unbindData.Details.ServiceID, unbindData.Details.PlanID)
+	serviceName := buildServiceName(config.ServiceNamePrefix, unbindData.InstanceID)
+
+	if ap.CredHub != nil {
+		err := ap.CredHub.Delete(ap.credhubName(unbindData.InstanceID, unbindData.BindingID))
+		if err != nil {
+			return err
+		}
+	}
+
+	unboundPrometheus, err := ap.unbindPrometheus(ctx, project, serviceName, unbindData.BindingID)
+	if err != nil || unboundPrometheus {
+		return err
+	}
+
+	user, service := ap.bindingUser(ctx, project, serviceName, unbindData.BindingID)
+
+	// The pool and database go first, as the user cannot be deleted while
+	// they use it.
+	if service != nil {
+		if err := ap.deletePostgresBinding(ctx, project, serviceName, service, user); err != nil {
+			return unbindError(err)
+		}
+	}
+
+	plan, err := config.FindPlan(unbindData.Details.ServiceID, unbindData.Details.PlanID)
+	if err == nil && supportsACLs(plan.ServiceType) {
+		err := ap.revokeACL(ctx, project, serviceName, plan.ServiceType, user)
+		if err != nil {
+			return unbindError(err)
+		}
+	}
+
+	_, err = ap.Client.DeleteServiceUser(ctx, &aiven.DeleteServiceUserInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Username:    user,
+	})
+	if service != nil && (err == nil || err == aiven.ErrServiceUserDoesNotExist) {
+		ap.untagAppGUID(ctx, project, serviceName, service, user)
+	}
+	return unbindError(err)
+}
+
+// unbindError tells the platform a binding whose user or service has already
+// gone no longer exists, so that it can remove the binding rather than
+// retrying forever.
+func unbindError(err error) error {
+	if err == aiven.ErrServiceUserDoesNotExist || err == aiven.ErrInstanceDoesNotExist || err == aiven.ErrServiceDoesNotExist {
+		return brokerapi.ErrBindingDoesNotExist
+	}
+	return err
+}
+
+// GetBinding returns the credentials of a binding, which is how the platform
+// fetches them after an asynchronous bind.
+func (ap *AivenProvider) GetBinding(ctx context.Context, getBindingData GetBindingData) (spec brokerapi.GetBindingSpec, err error) {
+	defer func() { err = abandonedRequest(err) }()
+	config := ap.currentConfig()
+	serviceName := buildServiceName(config.ServiceNamePrefix, getBindingData.InstanceID)
+
+	// The platform does not say which plan the instance has, so each project
+	// it could be in is tried.
+	for _, project := range config.projects() {
+		var service *aiven.Service
+		service, err = ap.Client.GetService(ctx, &aiven.GetServiceInput{
+			Project:     project,
+			ServiceName: serviceName,
+		})
+		if err != nil {
+			continue
+		}
+
+		credentials, found, err := ap.userCredentials(ctx, project, serviceName, service, usernameForBinding(service, getBindingData.BindingID))
+		if err != nil {
+			return brokerapi.GetBindingSpec{}, err
+		}
+		if !found {
+			return brokerapi.GetBindingSpec{}, brokerapi.ErrBindingNotFound
+		}
+		if ap.CredHub != nil {
+			return brokerapi.GetBindingSpec{
+				Credentials: credhubRef(ap.credhubName(getBindingData.InstanceID, getBindingData.BindingID)),
+			}, nil
+		}
+		if supportsACLs(service.ServiceType) {
+			// Bindings with dashboards access are told apart by their ACL
+			// rules.
+			aclConfig, err := ap.Client.GetACLConfig(ctx, &aiven.GetACLConfigInput{
+				Project:     project,
+				ServiceName: serviceName,
+				ServiceType: service.ServiceType,
+			})
+			if err != nil {
+				return brokerapi.GetBindingSpec{}, err
+			}
+			if hasDashboardsAccess(aclConfig, service.ServiceType, credentials.Username) {
+				return brokerapi.GetBindingSpec{Credentials: dashboardsCredentials(credentials)}, nil
+			}
+		}
+		return brokerapi.GetBindingSpec{Credentials: credentials}, nil
+	}
+	return brokerapi.GetBindingSpec{}, err
+}
+
+// LastBindingOperation reports whether the user of an asynchronous binding
+// is ready to use.
+func (ap *AivenProvider) LastBindingOperation(
+	ctx context.Context,
+	lastBindingOperationData LastBindingOperationData,
+) (state brokerapi.LastOperationState, description string, err error) {
+	defer func() { err = abandonedRequest(err) }()
+	project := ap.projectForInstance(lastBindingOperationData.ServiceID, lastBindingOperationData.PlanID)
+	serviceName := buildServiceName(ap.currentConfig().ServiceNamePrefix, lastBindingOperationData.InstanceID)
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	credentials, found, err := ap.userCredentials(ctx, project, serviceName, service, usernameForBinding(service, lastBindingOperationData.BindingID))
+	if err == errNoConnectionDetails {
+		return brokerapi.InProgress, "Waiting for the service's connection details", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if !found {
+		return brokerapi.InProgress, "Waiting for the service user to be created", nil
+	}
+
+	availabilityCheck, err := userAvailabilityCheck(service.ServiceType, credentials)
+	if err != nil {
+		return "", "", err
+	}
+	if availabilityCheck != nil && availabilityCheck() != nil {
+		return brokerapi.InProgress, "Waiting for the service user to become available", nil
+	}
+
+	return brokerapi.Succeeded, "Binding succeeded", nil
+}
+
+func (ap *AivenProvider) Update(ctx context.Context, updateData UpdateData) (operationData string, err error) {
+	defer func() { err = abandonedRequest(err) }()
+	config := ap.currentConfig()
+	defer ap.invalidateCachedService(buildServiceName(config.ServiceNamePrefix, updateData.InstanceID))
+	plan, err := config.FindPlan(updateData.Details.ServiceID, updateData.Details.PlanID)
+	if err != nil {
+		return "", planNotFound(err)
+	}
+
+	var parameters UpdateParameters
+	if err := decodeStrictParameters(updateData.Details.RawParameters, &parameters, config.LenientParameters); err != nil {
+		return "", err
+	}
+	if parameters.Restart {
+		return ap.restart(ctx, config, plan, updateData)
+	}
+	if parameters.ApplyMaintenance {
+		return ap.applyMaintenance(ctx, config, plan, updateData)
+	}
+	tenantIPFilter, err := config.tenantIPFilter(parameters.IPFilter, parameters.IPFilterGroups)
+	if err != nil {
+		return "", err
+	}
+	tenantUserConfig, err := config.tenantUserConfig(plan.ServiceType, parameters.UserConfig)
+	if err != nil {
+		return "", err
+	}
+	additionalDiskGB, err := additionalDiskSpaceGB(plan, parameters.AdditionalDiskSpaceGB)
+	if err != nil {
+		return "", err
+	}
+	indexPatterns, err := updatedIndexPatterns(plan, parameters.IndexPatterns)
+	if err != nil {
+		return "", err
+	}
+	techEmails, err := updatedNotificationEmails(parameters.NotificationEmails)
+	if err != nil {
+		return "", err
+	}
+	publicAccess, err := config.publicAccess(plan, parameters.PublicAccess)
+	if err != nil {
+		return "", err
+	}
+	if err := config.checkCloudAllowed(parameters.Cloud); err != nil {
+		return "", err
+	}
+	if err := config.checkCloudForProjectVPC(plan, parameters.Cloud); err != nil {
+		return "", err
+	}
+	maintenance, err := maintenanceWindow(parameters.MaintenanceDOW, parameters.MaintenanceTime)
+	if err != nil {
+		return "", err
+	}
+
+	// Changing cloud makes Aiven migrate the service, during which it is
+	// reported as rebuilding.
+	updateServiceInput := &aiven.UpdateServiceInput{
+		Project:     config.ProjectForPlan(plan),
+		ServiceName: buildServiceName(config.ServiceNamePrefix, updateData.InstanceID),
+		Cloud:       config.CloudForPlan(plan),
+		// Instances created before their plan was put in a project VPC are
+		// moved into it.
+		ProjectVPCID: config.ProjectVPCIDForPlan(plan),
+		Plan:         plan.AivenPlan,
+		Maintenance:  maintenance,
+		TechEmails:   techEmails,
+		// Termination protection is left as it is unless the parameter is
+		// given.
+		TerminationProtection: parameters.TerminationProtection,
+	}
+
+	// The service is only fetched if it is needed, and then only once.
+	var currentService *aiven.Service
+	getCurrentService := func() (*aiven.Service, error) {
+		if currentService == nil {
+			service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+				Project:     updateServiceInput.Project,
+				ServiceName: updateServiceInput.ServiceName,
+			})
+			if err != nil {
+				return nil, err
+			}
+			currentService = service
+		}
+		return currentService, nil
+	}
+
+	previousPlan, err := config.FindPlan(updateData.Details.ServiceID, updateData.Details.PreviousValues.PlanID)
+	if err != nil {
+		previousPlan = nil
+	}
+	if previousPlan != nil {
+		if previousProject := config.ProjectForPlan(previousPlan); previousProject != updateServiceInput.Project {
+			return "", planChangeNotSupported(fmt.Errorf(
+				"Cannot move service from project %s to %s", previousProject, updateServiceInput.Project,
+			))
+		}
+		if previousPlan.ServiceType != plan.ServiceType {
+			// Aiven can convert elasticsearch to opensearch in place, but
+			// there is no way back, so only plans which opt in are allowed.
+			if previousPlan.ServiceType != "elasticsearch" || plan.ServiceType != "opensearch" || !plan.UpgradeFromElasticsearch {
+				return "", planChangeNotSupported(fmt.Errorf(
+					"Cannot change service type from %s to %s", previousPlan.ServiceType, plan.ServiceType,
+				))
+			}
+			updateServiceInput.ServiceType = aivenServiceType(plan.ServiceType)
+		}
+	}
+
+	// Only services which could have been created in a cloud chosen by the
+	// tenant need to be checked for one.
+	cloudChanged := false
+	if len(config.AllowedClouds) > 0 {
+		service, err := getCurrentService()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case parameters.Cloud != "":
+			updateServiceInput.Cloud = parameters.Cloud
+			cloudChanged = parameters.Cloud != service.Tags[cloudTag]
+		case service.Tags[cloudTag] != "":
+			updateServiceInput.Cloud = service.Tags[cloudTag]
+		}
+	}
+	// Services which were not given a cloud of their own follow their plan,
+	// so moving to a plan in another cloud migrates them.
+	if !cloudChanged && updateServiceInput.Cloud == config.CloudForPlan(plan) && previousPlan != nil {
+		cloudChanged = config.CloudForPlan(previousPlan) != updateServiceInput.Cloud
+	}
+
+	planChanged := updateData.Details.PlanID != updateData.Details.PreviousValues.PlanID
+	organizationGUID := updateData.Details.PreviousValues.OrgID
+	if planChanged {
+		err := ap.checkPlanTransition(config, updateData.Details.ServiceID, getCurrentService, previousPlan, plan)
+		if err != nil {
+			return "", err
+		}
+		if err := ap.checkOrganizationQuota(ctx, organizationGUID, plan, updateServiceInput.ServiceName); err != nil {
+			return "", err
+		}
+	}
+
+	if parameters.IPFilter == nil && parameters.IPFilterGroups == nil {
+		// Without ip_filter parameters the entries tenants added before are
+		// kept.
+		service, err := getCurrentService()
+		if err != nil {
+			return "", err
+		}
+		tenantIPFilter = config.withoutRetiredIPs(service.UserConfig.IPFilter)
+	}
+	ipFilter := config.ipFilterFor(config.IPWhitelist, tenantIPFilter)
+	if err := config.checkIPFilterAllowed(ipFilter); err != nil {
+		return "", err
+	}
+	if err := config.checkIPFilterSize(ipFilter); err != nil {
+		return "", err
+	}
+	ap.warnIfAllowsAllIPs(updateData.InstanceID, ipFilter)
+	updateServiceInput.UserConfig, err = buildUserConfig(plan.ServiceType, plan, ipFilter)
+	if err != nil {
+		return "", err
+	}
+	engineVersion, err := updateEngineVersion(plan, parameters.Version, getCurrentService)
+	if err != nil {
+		return "", err
+	}
+	setEngineVersion(&updateServiceInput.UserConfig, plan.ServiceType, engineVersion)
+	updateServiceInput.UserConfig.Defaults = mergeUserConfig(updateServiceInput.UserConfig.Defaults, tenantUserConfig)
+	updateServiceInput.UserConfig.IndexPatterns = indexPatterns
+	updateServiceInput.UserConfig.PublicAccess = publicAccess
+
+	if additionalDiskGB > 0 || parameters.AdditionalDiskSpaceGB != nil {
+		diskSpaceMB, err := ap.diskSpaceMB(ctx, updateServiceInput.Project, plan, additionalDiskGB)
+		if err != nil {
+			return "", err
+		}
+		service, err := getCurrentService()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case diskSpaceMB >= service.DiskSpaceMB:
+			updateServiceInput.DiskSpaceMB = diskSpaceMB
+		case parameters.AdditionalDiskSpaceGB != nil:
+			return "", diskShrinkNotSupported(service.DiskSpaceMB, diskSpaceMB)
+		}
+		// Otherwise the disk has been grown beyond the plan's default, and
+		// is kept.
+	}
+	if planChanged {
+		service, err := getCurrentService()
+		if err != nil {
+			return "", err
+		}
+		err = ap.checkDataFits(ctx, updateServiceInput.Project, updateServiceInput.ServiceName, service, func() (int, error) {
+			if updateServiceInput.DiskSpaceMB != 0 {
+				return updateServiceInput.DiskSpaceMB, nil
+			}
+			return ap.diskSpaceMB(ctx, updateServiceInput.Project, plan, additionalDiskGB)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	noOp := false
+	if !config.AlwaysUpdateServices && !planChanged && withoutParameters(updateData.Details.RawParameters) {
+		service, err := getCurrentService()
+		if err != nil {
+			return "", err
+		}
+		noOp = !updateChangesService(service, updateServiceInput, plan, engineVersion)
+	}
+	if noOp {
+		ap.Logger.Info("skip-unchanged-update", lager.Data{
+			"project": updateServiceInput.Project,
+			"service": updateServiceInput.ServiceName,
+		})
+	} else if _, err := ap.Client.UpdateService(ctx, updateServiceInput); err != nil {
+		// Aiven refusing the update, which the client may have wrapped, is
+		// reported as the plan change not being supported.
+		var invalidUpdate aiven.ErrInvalidUpdate
+		if errors.As(err, &invalidUpdate) {
+			return "", planChangeNotSupported(err)
+		}
+		return "", err
+	}
+
+	if planChanged || cloudChanged {
+		// Keep the plan tag current so the instance counts against the
+		// quota of the plan it is now on, the cloud tag current so later
+		// updates keep it in the cloud it was moved to, and the owner tags
+		// current. Other tags, such as those of its bindings' apps, are
+		// kept.
+		service, err := getCurrentService()
+		if err != nil {
+			return "", err
+		}
+		tags := map[string]string{}
+		for key, value := range service.Tags {
+			tags[key] = value
+		}
+		if planChanged {
+			for key, value := range serviceTags(organizationGUID, plan.ID) {
+				tags[key] = value
+			}
+		}
+		if parameters.Cloud != "" {
+			tags[cloudTag] = parameters.Cloud
+		}
+		owner := ownerTags(
+			updateData.InstanceID, updateData.Details.PreviousValues.SpaceID, updateData.Service.Name, plan.Name,
+		)
+		for key, value := range owner {
+			tags[key] = value
+		}
+		err = ap.Client.UpdateServiceTags(ctx, &aiven.UpdateServiceTagsInput{
+			Project:     updateServiceInput.Project,
+			ServiceName: updateServiceInput.ServiceName,
+			Tags:        tags,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if plan.DiskAutoscalerCapGB > 0 {
+		err := ap.enableDiskAutoscaler(ctx, updateServiceInput.Project, updateServiceInput.ServiceName, plan.DiskAutoscalerCapGB)
+		if err != nil {
+			ap.logDiskAutoscalerError(updateServiceInput.Project, updateServiceInput.ServiceName, err)
+		}
+	}
+	updateOperationData := ap.newOperationData(OperationUpdate, plan)
+	updateOperationData.Version = engineVersion
+	if cloudChanged {
+		updateOperationData.Cloud = updateServiceInput.Cloud
+	}
+	billingGroupID := config.BillingGroupIDForPlan(plan)
+	if planChanged && billingGroupID != "" && (previousPlan == nil || config.BillingGroupIDForPlan(previousPlan) != billingGroupID) {
+		if err := ap.assignBillingGroup(ctx, updateServiceInput.Project, billingGroupID); err != nil {
+			updateOperationData.BillingGroupID = billingGroupID
+		}
+	}
+	return updateOperationData.encode(), nil
+}
+
+// checkPlanTransition enforces the plans' allowed_updates_to lists. When the
+// platform does not say which plan the instance is on, it is worked out from
+// the Aiven plan of the service.
+func (ap *AivenProvider) checkPlanTransition(
+	config *Config,
+	serviceID string,
+	getCurrentService func() (*aiven.Service, error),
+	previousPlan, plan *Plan,
+) error {
+	if !config.hasPlanTransitions(serviceID) {
+		return nil
+	}
+
+	currentPlans := []Plan{}
+	if previousPlan != nil {
+		currentPlans = append(currentPlans, *previousPlan)
+	} else {
+		service, err := getCurrentService()
+		if err != nil {
+			return err
+		}
+		currentPlans = config.plansWithAivenPlan(serviceID, service.Plan)
+	}
+
+	if len(currentPlans) == 0 {
+		return planChangeNotSupported(fmt.Errorf(
+			"Cannot change plan to %s as the current plan of the service is not known", plan.Name,
+		))
+	}
+	for _, currentPlan := range currentPlans {
+		if currentPlan.allowsUpdateTo(plan.ID) {
+			return nil
+		}
+	}
+	return planChangeNotSupported(fmt.Errorf("Cannot change plan from %s to %s", currentPlans[0].Name, plan.Name))
+}
+
+func planChangeNotSupported(err error) error {
+	return brokerapi.NewFailureResponseBuilder(
+		err,
+		http.StatusUnprocessableEntity,
+		"plan-change-not-supported",
+	).WithErrorKey("PlanChangeNotSupported").Build()
+}
+
+// abandonedRequest reports Aiven requests abandoned because the platform's
+// request was cancelled, or ran out of time, as a temporary failure, so
+// that the platform tries again.
+func abandonedRequest(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return brokerapi.NewFailureResponse(
+			fmt.Errorf("Aiven did not respond in time, try again shortly: %s", err),
+			http.StatusServiceUnavailable,
+			"aiven-request-abandoned",
+		)
+	}
+	return err
+}
+
+// planNotFound reports plans missing from the config as a bad request, as
+// the platform has asked for something which is not in the catalog.
+func planNotFound(err error) error {
+	var notFound *PlanNotFoundError
+	if errors.As(err, &notFound) {
+		return brokerapi.NewFailureResponse(err, http.StatusBadRequest, "find-plan")
+	}
+	return err
+}
+
+func (ap *AivenProvider) LastOperation(
+	ctx context.Context,
+	lastOperationData LastOperationData,
+) (state brokerapi.LastOperationState, description string, err error) {
+	defer func() { err = abandonedRequest(err) }()
+	serviceName := buildServiceName(
+		ap.currentConfig().ServiceNamePrefix,
+		lastOperationData.InstanceID,
+	)
+
+	operationData, err := DecodeOperationData(lastOperationData.OperationData)
+	if err != nil {
+		return "", "", brokerapi.NewFailureResponse(err, http.StatusBadRequest, "invalid-operation-data")
+	}
+
+	state, description, err = ap.operationState(ctx, lastOperationData, serviceName, operationData)
+	if err != nil {
+		return "", "", err
+	}
+	if state == brokerapi.Succeeded && operationData.Operation != OperationDeprovision {
+		project := ap.projectForInstance(lastOperationData.ServiceID, lastOperationData.PlanID)
+		description = ap.withDiskUsageWarning(ctx, project, serviceName, description)
+	}
+	if state != brokerapi.InProgress {
+		return state, description, nil
+	}
+	// Provisions which time out are dealt with as stuck provisions.
+	if operationData.Operation != OperationProvision && operationData.timedOut(ap.now()) {
+		state, description = ap.timedOutOperation(serviceName, operationData, description)
+	}
+	return state, description, nil
+}
+
+// operationState is the state of the operation, from the state of the
+// instance's service.
+func (ap *AivenProvider) operationState(
+	ctx context.Context,
+	lastOperationData LastOperationData,
+	serviceName string,
+	operationData OperationData,
+) (brokerapi.LastOperationState, string, error) {
+	project := ap.projectForInstance(lastOperationData.ServiceID, lastOperationData.PlanID)
+	service, err := ap.pollService(ctx, project, serviceName)
+
+	// Aiven deletes services asynchronously, so deprovisions are in progress
+	// until the service is gone.
+	if operationData.Operation == OperationDeprovision {
+		if err == aiven.ErrServiceDoesNotExist {
+			return brokerapi.Succeeded, "Service deleted", nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+		return brokerapi.InProgress, "Deleting the service", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	status := service.State
+	if !status.Known() {
+		// The state is reported as in progress, so the operation times out
+		// if it stays this way.
+		ap.Logger.Error("unknown-service-state", fmt.Errorf("unknown service state: %s", status), lager.Data{
+			"project": project,
+			"service": serviceName,
+			"state":   status,
+		})
+	}
+
+	if operationData.Restart {
+		return ap.restartState(ctx, project, serviceName, service)
+	}
+	if operationData.ApplyMaintenance {
+		state, description := maintenanceState(operationData, service)
+		return state, description, nil
+	}
+
+	// Only updates leave the service running on its old plan for a while,
+	// so they are in progress until the service reports what they moved it
+	// to. Operations from before then, including those from before
+	// operation data was typed, which could be updates, are in progress
+	// until the service reports their plan's Aiven plan.
+	if operationData.Operation == OperationUpdate || operationData.Operation == "" {
+		target := ap.updateTarget(operationData, lastOperationData)
+		if status == aiven.Running && !target.appliedTo(service) {
+			if target.Cloud != "" {
+				return brokerapi.InProgress, fmt.Sprintf("Preparing to migrate the service to %s", target.Cloud), nil
+			}
+			return brokerapi.InProgress, "Preparing to apply update", nil
+		}
+	}
+
+	lastOperationState, description := providerStatesMapping(status)
+	if operationData.Operation == OperationProvision && status != aiven.Running && operationData.timedOut(ap.now()) {
+		return ap.stuckProvision(ctx, lastOperationData, serviceName, operationData)
+	}
+	if lastOperationState == brokerapi.InProgress && operationData.RestoreFromInstance != "" {
+		description = fmt.Sprintf("Restoring from a backup of instance %s", operationData.RestoreFromInstance)
+	} else if lastOperationState == brokerapi.InProgress && operationData.CloneFrom != "" {
+		description = fmt.Sprintf("Restoring data from instance %s", operationData.CloneFrom)
+	} else if lastOperationState == brokerapi.InProgress && operationData.Cloud != "" {
+		// Migrations rebuild, and often rebalance, the service for a long
+		// time.
+		description = fmt.Sprintf("Migrating the service to %s: %s", operationData.Cloud, description)
+	} else if lastOperationState == brokerapi.InProgress && operationData.Recovery == RecoveryRecreated {
+		description = "Creating the service again, in place of a powered off one an earlier instance left"
+	}
+	if lastOperationState == brokerapi.InProgress {
+		description = progressDescription(description, service)
+	}
+	if operationData.BillingGroupID != "" {
+		lastOperationState, description = ap.retryBillingGroup(ctx, project, operationData.BillingGroupID, lastOperationState, description)
+	}
+	return lastOperationState, description, nil
+}
+
+// ParseIPWhitelist parses a comma separated list of IPv4 and IPv6 addresses
+// and CIDR blocks. Single addresses are returned as /32 or /128 blocks.
+func ParseIPWhitelist(ips string) ([]string, error) {
+	if strings.TrimSpace(ips) == "" {
+		return []string{}, nil
+	}
+	return parseIPWhitelistEntries(strings.Split(ips, ","))
+}
+
+func parseIPWhitelistEntries(entries []string) ([]string, error) {
+	outIPs := []string{}
+	for _, entry := range entries {
+		prefix, err := parseIPFilterEntry(strings.TrimSpace(entry))
+		if err != nil {
+			return []string{}, fmt.Errorf("malformed whitelist IP: %v", entry)
+		}
+		outIPs = append(outIPs, prefix.String())
+	}
+	return outIPs, nil
+}
+
+func parseIPFilterEntry(entry string) (netip.Prefix, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("IPv6 zones are not supported: %s", entry)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ipFilterKey is the normalized form of an IP filter entry, so that entries
+// for the same addresses, such as 10.0.0.1 and 10.0.0.1/32, compare equal.
+// Entries which do not parse are left as they are.
+func ipFilterKey(entry string) string {
+	prefix, err := parseIPFilterEntry(strings.TrimSpace(entry))
+	if err != nil {
+		return entry
+	}
+	return prefix.String()
+}
+
+func ipFilterKeys(ipFilter []string) []string {
+	keys := make([]string, len(ipFilter))
+	for i, entry := range ipFilter {
+		keys[i] = ipFilterKey(entry)
+	}
+	return keys
+}
+
+// maxServiceNameLength is the longest service name Aiven accepts.
+const maxServiceNameLength = 64
+
+// maxServiceNamePrefixLength leaves room in service names for the GUIDs Cloud
+// Foundry uses as instance IDs.
+const maxServiceNamePrefixLength = maxServiceNameLength - len("-") - len("09e1993e-62e2-4040-adf2-4d3ec741efe6")
+
+// buildServiceName names the service of an instance. If the prefix is too
+// long to fit alongside the instance ID it is shortened, keeping the whole
+// ID so that names stay distinct, and so that every request about the
+// instance works out the same name.
+func buildServiceName(prefix, guid string) string {
+	if room := maxServiceNameLength - len("-") - len(guid); room > 0 && len(prefix) > room {
+		prefix = prefix[:room]
+	}
+	return strings.ToLower(prefix + "-" + guid)
+}
+
+// maxServiceUsernameLength is the longest username Aiven accepts.
+const maxServiceUsernameLength = 64
+
+var (
+	validServiceUsername         = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	notServiceUsernameCharacters = regexp.MustCompile(`[^a-z0-9._-]`)
+)
+
+// serviceUsername names the user of a binding. Binding IDs which are already
+// valid usernames, such as the GUIDs Cloud Foundry uses, are used as they
+// are, so existing bindings keep their users. Other IDs are cleaned up and,
+// if they are still too long, shortened with a hash of the whole ID so that
+// they stay distinct.
+func serviceUsername(bindingID string) string {
+	if len(bindingID) <= maxServiceUsernameLength && validServiceUsername.MatchString(bindingID) {
+		return bindingID
+	}
+
+	username := notServiceUsernameCharacters.ReplaceAllString(strings.ToLower(bindingID), "")
+	username = strings.TrimLeft(username, "._-")
+
+	hash := sha256.Sum256([]byte(bindingID))
+	suffix := "-" + hex.EncodeToString(hash[:])[:12]
+	if len(username)+len(suffix) > maxServiceUsernameLength {
+		username = username[:maxServiceUsernameLength-len(suffix)]
+	}
+	return username + suffix
+}
+
+// customUsernameHashLength is how much of the hash of the binding ID is
+// added to usernames which bindings ask for, so that Unbind, which is only
+// given the binding ID, can find the user again.
+const customUsernameHashLength = 8
+
+var maxCustomUsernameLength = maxServiceUsernameLength - customUsernameHashLength - 1
+
+// reservedCustomUsername matches usernames which would be mistaken for the
+// users of bindings with a ttl.
+var reservedCustomUsername = regexp.MustCompile(`-exp[0-9]+$`)
+
+func customUsernameSuffix(bindingID string) string {
+	hash := sha256.Sum256([]byte(bindingID))
+	return "-" + hex.EncodeToString(hash[:])[:customUsernameHashLength]
+}
+
+// customServiceUsername names the user of a binding which asked for a
+// username of its own.
+func customServiceUsername(username, bindingID string) string {
+	return username + customUsernameSuffix(bindingID)
+}
+
+// validateCustomUsername checks a username parameter against Aiven's rules
+// and the prefixes the config allows.
+func (c *Config) validateCustomUsername(username string) error {
+	if len(username) > maxCustomUsernameLength || !validServiceUsername.MatchString(username) {
+		return invalidParameters(fmt.Errorf(
+			"Invalid username: %s, must be at most %d letters, digits, '.', '_' or '-' and start with a letter or digit",
+			username, maxCustomUsernameLength,
+		))
+	}
+	if reservedCustomUsername.MatchString(username) {
+		return invalidParameters(fmt.Errorf("Invalid username: %s, must not end with -exp and a number", username))
+	}
+	if len(c.CustomUsernamePrefixes) == 0 {
+		return nil
+	}
+	for _, prefix := range c.CustomUsernamePrefixes {
+		if strings.HasPrefix(username, prefix) {
+			return nil
+		}
+	}
+	return invalidParameters(fmt.Errorf(
+		"Invalid username: %s, must start with one of %s",
+		username, strings.Join(c.CustomUsernamePrefixes, ", "),
+	))
+}
+
+var customUsernameSuffixPattern = regexp.MustCompile(fmt.Sprintf(`(-exp[0-9]+)?-[0-9a-f]{%d}$`, customUsernameHashLength))
+
+// checkUsernameConflict fails if another binding of the service already has
+// the username.
+func checkUsernameConflict(service *aiven.Service, username, bindingID string) error {
+	for _, existing := range service.Users {
+		if strings.HasSuffix(existing.Username, customUsernameSuffix(bindingID)) {
+			continue
+		}
+		if customUsernameSuffixPattern.ReplaceAllString(existing.Username, "") == username {
+			return brokerapi.NewFailureResponse(
+				fmt.Errorf("Username %s is already used by another binding of this instance", username),
+				http.StatusConflict, "username-conflict",
+			)
+		}
+	}
+	return nil
+}
+
+// findBindingUser finds the user of a binding among the service's users.
+func findBindingUser(service *aiven.Service, bindingID string) (string, bool) {
+	suffix := customUsernameSuffix(bindingID)
+	for _, user := range service.Users {
+		if user.Username == serviceUsername(bindingID) || strings.HasSuffix(user.Username, suffix) {
+			return user.Username, true
+		}
+	}
+	return "", false
+}
+
+// usernameForBinding finds the user of a binding, which is assumed to be
+// named after the binding ID if the service does not have it.
+func usernameForBinding(service *aiven.Service, bindingID string) string {
+	if username, found := findBindingUser(service, bindingID); found {
+		return username
+	}
+	return serviceUsername(bindingID)
+}
+
+// bindingUser looks up the user of a binding, and returns the service it
+// was found in. If the service cannot be fetched, the user is assumed to be
+// named after the binding ID.
+func (ap *AivenProvider) bindingUser(ctx context.Context, project, serviceName, bindingID string) (string, *aiven.Service) {
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return serviceUsername(bindingID), nil
+	}
+	return usernameForBinding(service, bindingID), service
 }
 
 func providerStatesMapping(status aiven.ServiceStatus) (brokerapi.LastOperationState, string) {
@@ -273,6 +2059,8 @@ func providerStatesMapping(status aiven.ServiceStatus) (brokerapi.LastOperationS
 		return brokerapi.InProgress, "Rebalancing"
 	case aiven.PowerOff:
 		return brokerapi.Failed, "Last operation failed: service is powered off"
+	case aiven.Terminating:
+		return brokerapi.Failed, "Last operation failed: service is being deleted"
 	default:
 		return brokerapi.InProgress, fmt.Sprintf("Unknown state: %s", status)
 	}
@@ -1,6 +1,10 @@
 package provider
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/alphagov/paas-aiven-broker/provider/aiven"
 	"github.com/pivotal-cf/brokerapi"
 
@@ -19,6 +23,147 @@ var _ = Describe("Provider internals", func() {
 		Entry("downcases everything", "Env", "09E1993E-62E2-4040-ADF2-4D3EC741EFE6", "env-09e1993e-62e2-4040-adf2-4d3ec741efe6"),
 	)
 
+	Describe("buildServiceName", func() {
+		It("names services as they always have been for prefixes which fit", func() {
+			Expect(buildServiceName("env", "09E1993E-62E2-4040-ADF2-4D3EC741EFE6")).To(Equal("env-09e1993e-62e2-4040-adf2-4d3ec741efe6"))
+			Expect(buildServiceName("Prod-Env", "09e1993e-62e2-4040-adf2-4d3ec741efe6")).To(Equal("prod-env-09e1993e-62e2-4040-adf2-4d3ec741efe6"))
+			Expect(buildServiceName("abcdefghijklmnopqrstuvwxyz0", "09e1993e-62e2-4040-adf2-4d3ec741efe6")).To(Equal(
+				"abcdefghijklmnopqrstuvwxyz0-09e1993e-62e2-4040-adf2-4d3ec741efe6",
+			))
+		})
+
+		It("shortens the prefix, keeping the whole instance ID, when the name would be too long", func() {
+			name := buildServiceName("env", "09e1993e-62e2-4040-adf2-4d3ec741efe6-and-a-longer-instance-id")
+
+			Expect(name).To(Equal("en-09e1993e-62e2-4040-adf2-4d3ec741efe6-and-a-longer-instance-id"))
+			Expect(name).To(HaveLen(64))
+			Expect(buildServiceName("env", "09e1993e-62e2-4040-adf2-4d3ec741efe6-and-a-longer-instance-id")).To(Equal(name))
+		})
+	})
+
+	Describe("serviceUsername", func() {
+		It("uses binding IDs which are valid usernames as they are", func() {
+			Expect(serviceUsername("d26ea3fb-aa78-451c-9ed0-233935ed388f")).To(Equal("d26ea3fb-aa78-451c-9ed0-233935ed388f"))
+			Expect(serviceUsername("D26EA3FB-AA78-451C-9ED0-233935ED388F")).To(Equal("D26EA3FB-AA78-451C-9ED0-233935ED388F"))
+		})
+
+		It("removes invalid characters and adds a hash of the binding ID", func() {
+			Expect(serviceUsername("Service Key/d26ea3fb")).To(MatchRegexp(`^servicekeyd26ea3fb-[0-9a-f]{12}$`))
+			Expect(serviceUsername("-d26ea3fb")).To(MatchRegexp(`^d26ea3fb-[0-9a-f]{12}$`))
+		})
+
+		It("shortens long binding IDs to the length Aiven allows", func() {
+			bindingID := "platform-prefix-d26ea3fb-aa78-451c-9ed0-233935ed388f-e6f1c8a4-0c2c-4f7b-a6a4-5d0b0b0a9b1c"
+			username := serviceUsername(bindingID)
+			Expect(username).To(HaveLen(64))
+			Expect(username).To(HavePrefix("platform-prefix-d26ea3fb-aa78-451c-9ed0-233935ed388-"))
+		})
+
+		It("always gives the same binding ID the same username", func() {
+			bindingID := strings.Repeat("binding/", 10) + "d26ea3fb-aa78-451c-9ed0-233935ed388f"
+			Expect(serviceUsername(bindingID)).To(Equal(serviceUsername(bindingID)))
+		})
+
+		It("gives binding IDs which only differ after the cut off different usernames", func() {
+			usernames := map[string]string{}
+			for i := 0; i < 1000; i++ {
+				bindingID := fmt.Sprintf("platform-prefix-d26ea3fb-aa78-451c-9ed0-233935ed388f-%08x-0c2c-4f7b-a6a4-5d0b0b0a9b1c", i)
+				username := serviceUsername(bindingID)
+				Expect(len(username)).To(BeNumerically("<=", 64))
+				Expect(usernames).ToNot(HaveKey(username))
+				usernames[username] = bindingID
+			}
+		})
+
+		It("gives binding IDs which only differ by invalid characters different usernames", func() {
+			Expect(serviceUsername("a/b")).ToNot(Equal(serviceUsername("a:b")))
+		})
+	})
+
+	Describe("expiringServiceUsername", func() {
+		It("keeps the expiry of long binding IDs within the length Aiven allows", func() {
+			expiry := time.Unix(1614614400, 0)
+			bindingID := "platform-prefix-d26ea3fb-aa78-451c-9ed0-233935ed388f-e6f1c8a4-0c2c-4f7b-a6a4-5d0b0b0a9b1c"
+			username := expiringServiceUsername("", bindingID, expiry)
+			Expect(len(username)).To(BeNumerically("<=", 64))
+			actualExpiry, ok := usernameExpiry(username)
+			Expect(ok).To(BeTrue())
+			Expect(actualExpiry).To(Equal(expiry))
+			Expect(strings.HasSuffix(username, customUsernameSuffix(bindingID))).To(BeTrue())
+		})
+
+		It("is not mistaken for a username without a ttl", func() {
+			_, ok := usernameExpiry("d26ea3fb-aa78-451c-9ed0-233935ed388f")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	DescribeTable("mergeIPFilters",
+		func(ipFilters [][]string, expected []string) {
+			Expect(mergeIPFilters(ipFilters...)).To(Equal(expected))
+		},
+		Entry("returns an empty list for no filters", [][]string{}, []string{}),
+		Entry("sorts the entries",
+			[][]string{{"5.6.7.8/32", "1.2.3.4/32"}},
+			[]string{"1.2.3.4/32", "5.6.7.8/32"},
+		),
+		Entry("removes entries which appear in more than one filter",
+			[][]string{{"10.0.0.0/8", "1.2.3.4/32"}, {"1.2.3.4/32", "10.0.0.0/8", "2001:db8::/32"}},
+			[]string{"1.2.3.4/32", "10.0.0.0/8", "2001:db8::/32"},
+		),
+		Entry("removes entries repeated within a filter",
+			[][]string{{"1.2.3.4/32", "1.2.3.4/32"}},
+			[]string{"1.2.3.4/32"},
+		),
+		Entry("keeps one of the entries for the same addresses written differently",
+			[][]string{{"1.2.3.4/32"}, {"1.2.3.4", "2001:db8::1"}, {"2001:db8::1/128"}},
+			[]string{"1.2.3.4/32", "2001:db8::1"},
+		),
+		Entry("keeps overlapping blocks which are written differently",
+			[][]string{{"10.0.0.0/8"}, {"10.1.0.0/16"}},
+			[]string{"10.0.0.0/8", "10.1.0.0/16"},
+		),
+		Entry("collapses to allowing all addresses when any filter does",
+			[][]string{{"1.2.3.4/32"}, {"10.0.0.0/8", "0.0.0.0/0"}},
+			[]string{"0.0.0.0/0"},
+		),
+	)
+
+	DescribeTable("withoutRetiredIPs",
+		func(ipFilter, expected []string) {
+			config := &Config{RetiredIPWhitelist: []string{"9.8.7.6/32", "203.0.113.0/24"}}
+			Expect(config.withoutRetiredIPs(ipFilter)).To(Equal(expected))
+		},
+		Entry("drops retired entries", []string{"9.8.7.6/32", "10.0.0.0/8", "203.0.113.0/24"}, []string{"10.0.0.0/8"}),
+		Entry("drops retired addresses written without a prefix length", []string{"9.8.7.6", "10.0.0.1"}, []string{"10.0.0.1"}),
+		Entry("keeps addresses within a retired block", []string{"203.0.113.1/32"}, []string{"203.0.113.1/32"}),
+	)
+
+	DescribeTable("sameIPFilter",
+		func(a, b []string, expected bool) {
+			Expect(sameIPFilter(a, b)).To(Equal(expected))
+		},
+		Entry("ignores order", []string{"1.2.3.4/32", "10.0.0.0/8"}, []string{"10.0.0.0/8", "1.2.3.4/32"}, true),
+		Entry("matches addresses with and without a prefix length", []string{"1.2.3.4"}, []string{"1.2.3.4/32"}, true),
+		Entry("tells different addresses apart", []string{"1.2.3.4/32"}, []string{"1.2.3.5/32"}, false),
+	)
+
+	DescribeTable("ipFilterFor",
+		func(removable bool, tenantIPFilter, expected []string) {
+			config := &Config{IPWhitelistRemovable: removable}
+			Expect(config.ipFilterFor([]string{"1.2.3.4/32"}, tenantIPFilter)).To(Equal(expected))
+		},
+		Entry("keeps the platform whitelist alongside the tenant's entries",
+			false, []string{"10.0.0.0/8"}, []string{"1.2.3.4/32", "10.0.0.0/8"},
+		),
+		Entry("lets the tenant replace the platform whitelist when it is removable",
+			true, []string{"10.0.0.0/8"}, []string{"10.0.0.0/8"},
+		),
+		Entry("uses the platform whitelist when a removable one is not replaced",
+			true, []string{}, []string{"1.2.3.4/32"},
+		),
+	)
+
 	DescribeTable("providerStatesMapping",
 		func(inputState aiven.ServiceStatus, expectedState brokerapi.LastOperationState, expectedDescription string) {
 			state, description := providerStatesMapping(inputState)
@@ -29,6 +174,274 @@ var _ = Describe("Provider internals", func() {
 		Entry("returns 'in progress' when REBUILDING", aiven.Rebuilding, brokerapi.InProgress, "Rebuilding"),
 		Entry("returns 'in progress' when REBALANCING", aiven.Rebalancing, brokerapi.InProgress, "Rebalancing"),
 		Entry("returns 'failed' when POWEROFF", aiven.PowerOff, brokerapi.Failed, "Last operation failed: service is powered off"),
+		Entry("returns 'failed' when TERMINATING", aiven.Terminating, brokerapi.Failed, "Last operation failed: service is being deleted"),
 		Entry("returns 'in progress' by default", aiven.ServiceStatus("foo"), brokerapi.InProgress, "Unknown state: foo"),
 	)
+
+	It("maps every state Aiven is known to report explicitly", func() {
+		for _, status := range aiven.ServiceStatuses {
+			_, description := providerStatesMapping(status)
+			Expect(description).ToNot(HavePrefix("Unknown state"), string(status))
+		}
+	})
+
+	Describe("progressDescription", func() {
+		progress := func(phase string, current, max int64, completed bool) aiven.NodeProgress {
+			min := int64(0)
+			return aiven.NodeProgress{Phase: phase, Current: &current, Min: &min, Max: &max, Completed: completed}
+		}
+
+		DescribeTable("adds the progress Aiven reports",
+			func(nodes []aiven.NodeState, expected string) {
+				Expect(progressDescription("Rebalancing", &aiven.Service{NodeStates: nodes})).To(Equal(expected))
+			},
+			Entry("without node states", nil, "Rebalancing"),
+			Entry("when every node is ready",
+				[]aiven.NodeState{{State: "running"}, {State: "running"}},
+				"Rebalancing",
+			),
+			Entry("when some nodes are not ready",
+				[]aiven.NodeState{{State: "running"}, {State: "running"}, {State: "setting_up_vm"}},
+				"Rebalancing: 2/3 nodes ready",
+			),
+			Entry("with the progress of a phase",
+				[]aiven.NodeState{
+					{State: "running", ProgressUpdates: []aiven.NodeProgress{progress("prepare", 1, 1, true)}},
+					{State: "syncing_data", ProgressUpdates: []aiven.NodeProgress{progress("basebackup", 45, 100, false)}},
+				},
+				"Rebalancing: 1/2 nodes ready, basebackup 45%",
+			),
+			Entry("ignoring phases without measurable progress",
+				[]aiven.NodeState{{State: "running", ProgressUpdates: []aiven.NodeProgress{{Phase: "stream"}}}},
+				"Rebalancing",
+			),
+		)
+
+		It("shortens long descriptions", func() {
+			description := progressDescription(strings.Repeat("a", 300), &aiven.Service{})
+			Expect(description).To(HaveLen(200))
+			Expect(description).To(HaveSuffix("..."))
+		})
+	})
+
+	Describe("parametersSchema", func() {
+		type nested struct {
+			Enabled bool `json:"enabled"`
+		}
+		type parameters struct {
+			Version    string            `json:"version" description:"Engine version" enum:"1,2"`
+			IPFilter   []string          `json:"ip_filter,omitempty"`
+			Count      int               `json:"count"`
+			Ratio      float64           `json:"ratio"`
+			Labels     map[string]string `json:"labels"`
+			Nested     *nested           `json:"nested"`
+			KafkaOnly  bool              `json:"kafka_only" service_types:"kafka"`
+			SearchOnly bool              `json:"search_only" service_types:"elasticsearch,opensearch"`
+			Ignored    string            `json:"-"`
+			unexported string
+		}
+
+		It("describes the fields of the parameters as a draft-04 JSON schema", func() {
+			schema := parametersSchema(parameters{}, "opensearch")
+
+			Expect(schema).To(Equal(map[string]interface{}{
+				"$schema":              "http://json-schema.org/draft-04/schema#",
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"version": map[string]interface{}{
+						"type":        "string",
+						"description": "Engine version",
+						"enum":        []interface{}{"1", "2"},
+					},
+					"ip_filter": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"type": "string"},
+					},
+					"count": map[string]interface{}{"type": "integer"},
+					"ratio": map[string]interface{}{"type": "number"},
+					"labels": map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": map[string]interface{}{"type": "string"},
+					},
+					"nested": map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": false,
+						"properties": map[string]interface{}{
+							"enabled": map[string]interface{}{"type": "boolean"},
+						},
+					},
+					"search_only": map[string]interface{}{"type": "boolean"},
+				},
+			}))
+		})
+
+		It("only includes fields which apply to the service type", func() {
+			properties := parametersSchema(parameters{}, "kafka")["properties"].(map[string]interface{})
+
+			Expect(properties).To(HaveKey("kafka_only"))
+			Expect(properties).NotTo(HaveKey("search_only"))
+		})
+	})
+
+	Describe("mergeUserConfig", func() {
+		It("merges objects key by key, without changing the base", func() {
+			base := map[string]interface{}{
+				"max_index_count": float64(10),
+				"elasticsearch":   map[string]interface{}{"a": "plan", "b": "plan"},
+			}
+
+			merged := mergeUserConfig(base, map[string]interface{}{
+				"elasticsearch": map[string]interface{}{"b": "tenant", "c": "tenant"},
+			})
+
+			Expect(merged).To(Equal(map[string]interface{}{
+				"max_index_count": float64(10),
+				"elasticsearch":   map[string]interface{}{"a": "plan", "b": "tenant", "c": "tenant"},
+			}))
+			Expect(base["elasticsearch"]).To(Equal(map[string]interface{}{"a": "plan", "b": "plan"}))
+		})
+
+		It("replaces values which are not objects in both", func() {
+			merged := mergeUserConfig(
+				map[string]interface{}{"pg": map[string]interface{}{"a": "plan"}},
+				map[string]interface{}{"pg": "tenant"},
+			)
+
+			Expect(merged).To(Equal(map[string]interface{}{"pg": "tenant"}))
+		})
+	})
+
+	Describe("compareEngineVersions", func() {
+		DescribeTable("orders versions component by component",
+			func(a, b string, expected int) {
+				Expect(compareEngineVersions(a, b)).To(Equal(expected))
+			},
+			Entry("same", "7.10", "7.10", 0),
+			Entry("missing components are zero", "7", "7.0", 0),
+			Entry("older major", "6.8", "7", -1),
+			Entry("newer minor", "7.10", "7.9", 1),
+			Entry("newer than the major alone", "7.10", "7", 1),
+		)
+	})
+
+	Describe("PlanSchemas", func() {
+		It("emits schemas which satisfy the Open Service Broker spec", func() {
+			config := &Config{Catalog: Catalog{Services: []Service{{
+				Service: brokerapi.Service{ID: "service"},
+				Plans: []Plan{{
+					ServicePlan:        brokerapi.ServicePlan{ID: "plan"},
+					PlanSpecificConfig: PlanSpecificConfig{ServiceType: "elasticsearch"},
+				}},
+			}}}}
+
+			schemas, err := config.PlanSchemas("service", "plan")
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, schema := range []brokerapi.Schema{
+				schemas.Instance.Create,
+				schemas.Instance.Update,
+				schemas.Binding.Create,
+			} {
+				// The spec requires a draft-04 JSON schema describing an object.
+				Expect(schema.Parameters).To(HaveKeyWithValue("$schema", "http://json-schema.org/draft-04/schema#"))
+				Expect(schema.Parameters).To(HaveKeyWithValue("type", "object"))
+				expectValidJSONSchemaTypes(schema.Parameters)
+			}
+		})
+
+		It("only offers parameters to the service types they apply to, named as the catalog or Aiven does", func() {
+			config := &Config{Catalog: Catalog{Services: []Service{{
+				Service: brokerapi.Service{ID: "service"},
+				Plans: []Plan{
+					{
+						ServicePlan:        brokerapi.ServicePlan{ID: "postgres-plan"},
+						PlanSpecificConfig: PlanSpecificConfig{ServiceType: "postgres"},
+					},
+					{
+						ServicePlan:        brokerapi.ServicePlan{ID: "opensearch-plan"},
+						PlanSpecificConfig: PlanSpecificConfig{ServiceType: "opensearch"},
+					},
+				},
+			}}}}
+
+			postgresSchemas, err := config.PlanSchemas("service", "postgres-plan")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(postgresSchemas.Binding.Create.Parameters["properties"]).To(HaveKey("own_database"))
+			Expect(postgresSchemas.Instance.Create.Parameters["properties"]).NotTo(HaveKey("index_patterns"))
+
+			openSearchSchemas, err := config.PlanSchemas("service", "opensearch-plan")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(openSearchSchemas.Binding.Create.Parameters["properties"]).NotTo(HaveKey("own_database"))
+			Expect(openSearchSchemas.Instance.Create.Parameters["properties"]).To(HaveKey("index_patterns"))
+			Expect(openSearchSchemas.Instance.Update.Parameters["properties"]).To(HaveKey("index_patterns"))
+		})
+
+		It("only offers user_config for service types with an allow-list", func() {
+			config := &Config{Catalog: Catalog{Services: []Service{{
+				Service: brokerapi.Service{ID: "service"},
+				Plans: []Plan{{
+					ServicePlan:        brokerapi.ServicePlan{ID: "plan"},
+					PlanSpecificConfig: PlanSpecificConfig{ServiceType: "elasticsearch"},
+				}},
+			}}}}
+
+			schemas, err := config.PlanSchemas("service", "plan")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schemas.Instance.Create.Parameters["properties"]).NotTo(HaveKey("user_config"))
+			Expect(schemas.Instance.Update.Parameters["properties"]).NotTo(HaveKey("user_config"))
+
+			config.UserConfigAllowList = map[string][]string{"elasticsearch": {"elasticsearch.action_destructive_requires_name"}}
+			schemas, err = config.PlanSchemas("service", "plan")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schemas.Instance.Create.Parameters["properties"]).To(HaveKey("user_config"))
+			Expect(schemas.Instance.Update.Parameters["properties"]).To(HaveKey("user_config"))
+		})
+
+		It("only offers version to plans with allowed versions, which it lists", func() {
+			config := &Config{Catalog: Catalog{Services: []Service{{
+				Service: brokerapi.Service{ID: "service"},
+				Plans: []Plan{{
+					ServicePlan: brokerapi.ServicePlan{ID: "plan"},
+					PlanSpecificConfig: PlanSpecificConfig{
+						ServiceType:                     "elasticsearch",
+						AivenServiceElasticsearchConfig: AivenServiceElasticsearchConfig{ElasticsearchVersion: "7"},
+					},
+				}},
+			}}}}
+
+			schemas, err := config.PlanSchemas("service", "plan")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schemas.Instance.Update.Parameters["properties"]).NotTo(HaveKey("version"))
+
+			config.Catalog.Services[0].Plans[0].AllowedVersions = []string{"7.10"}
+			schemas, err = config.PlanSchemas("service", "plan")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schemas.Instance.Create.Parameters["properties"]).NotTo(HaveKey("version"))
+			Expect(schemas.Instance.Update.Parameters["properties"]).To(HaveKeyWithValue(
+				"version", HaveKeyWithValue("enum", []interface{}{"7", "7.10"}),
+			))
+		})
+
+		It("returns an error for an unknown plan", func() {
+			config := &Config{}
+
+			_, err := config.PlanSchemas("service", "plan")
+			Expect(err).To(MatchError("could not find service with id service for plan plan"))
+		})
+	})
 })
+
+func expectValidJSONSchemaTypes(schema map[string]interface{}) {
+	ExpectWithOffset(1, []interface{}{
+		"array", "boolean", "integer", "number", "object", "string",
+	}).To(ContainElement(schema["type"]))
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, property := range properties {
+			expectValidJSONSchemaTypes(property.(map[string]interface{}))
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		expectValidJSONSchemaTypes(items)
+	}
+}
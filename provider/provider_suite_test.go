@@ -1,6 +1,8 @@
 package provider_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -11,3 +13,9 @@ func TestProvider(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Provider Suite")
 }
+
+// aivenInput drops the context from the arguments a fake Aiven client was
+// called with, leaving the input.
+func aivenInput[T any](_ context.Context, input T) T {
+	return input
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// publicAccessComponents are the components of each service type which
+// public_access gives a public endpoint to.
+var publicAccessComponents = map[string][]string{
+	"elasticsearch": {"elasticsearch", "kibana"},
+	"influxdb":      {"influxdb"},
+	"kafka":         {"kafka"},
+	"opensearch":    {"opensearch", "opensearch_dashboards"},
+	"postgres":      {"pg", "pgbouncer"},
+	"redis":         {"redis"},
+}
+
+// publicAccess validates the public_access parameter, and returns the user
+// config which turns public endpoints on or off. Services outside a project
+// VPC are always public, so only plans in one support it. It returns nil
+// when the parameter is omitted, which leaves the endpoints as they are.
+func (c *Config) publicAccess(plan *Plan, parameter *bool) (map[string]bool, error) {
+	if parameter == nil {
+		return nil, nil
+	}
+	if c.ProjectVPCIDForPlan(plan) == "" {
+		return nil, invalidParameters(errors.New("public_access is only supported by plans in a project VPC, whose instances are otherwise private"))
+	}
+	publicAccess := map[string]bool{}
+	for _, component := range publicAccessComponents[plan.ServiceType] {
+		publicAccess[component] = *parameter
+	}
+	return publicAccess, nil
+}
+
+// publicEndpoint finds the public endpoint of a service in a project VPC
+// with public access, if it has one.
+func publicEndpoint(service *aiven.Service) (host, port string) {
+	if !service.UserConfig.PublicAccess[service.ServiceType] {
+		return "", ""
+	}
+	for _, component := range service.Components {
+		if component.Component != service.ServiceType || component.Route != "public" {
+			continue
+		}
+		if service.ServiceType == "kafka" && component.KafkaAuthenticationMethod != "sasl" {
+			continue
+		}
+		if service.ServiceType != "kafka" && component.Usage != "primary" {
+			continue
+		}
+		return component.Host, strconv.Itoa(component.Port)
+	}
+	return "", ""
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-cf/brokerapi"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// restartTag marks a service which an update has powered off to restart it.
+// Operation data cannot change between polls, so the tag is how polling the
+// update tells a service which has not been powered off yet from one which
+// has been powered back on. It is removed when the service is powered on.
+const restartTag = "cf_restart_requested_at"
+
+// restart powers the instance's service off. Polling the update's last
+// operation powers it back on once it is off. Restarts only restart, so
+// cannot be combined with other changes.
+func (ap *AivenProvider) restart(ctx context.Context, config *Config, plan *Plan, updateData UpdateData) (string, error) {
+	if config.DisableRestarts {
+		return "", invalidParameters(errors.New("restart is disabled by the platform"))
+	}
+	if err := checkOnlyChange(updateData, "restart"); err != nil {
+		return "", err
+	}
+
+	project := config.ProjectForPlan(plan)
+	serviceName := buildServiceName(config.ServiceNamePrefix, updateData.InstanceID)
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return "", err
+	}
+	if service.State != aiven.Running {
+		return "", brokerapi.NewFailureResponse(
+			fmt.Errorf("Cannot restart the instance while it is %s", service.State),
+			http.StatusUnprocessableEntity,
+			"restart-not-running",
+		)
+	}
+
+	operationData := ap.newOperationData(OperationUpdate, plan)
+	operationData.Restart = true
+	tags := map[string]string{}
+	for key, value := range service.Tags {
+		tags[key] = value
+	}
+	tags[restartTag] = operationData.StartedAt.Format(time.RFC3339)
+	err = ap.Client.UpdateServiceTags(ctx, &aiven.UpdateServiceTagsInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Tags:        tags,
+	})
+	if err != nil {
+		return "", err
+	}
+	err = ap.Client.PowerService(ctx, &aiven.PowerServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+		Powered:     false,
+	})
+	if err != nil {
+		return "", err
+	}
+	ap.Logger.Info("restart", lager.Data{
+		"project": project,
+		"service": serviceName,
+	})
+	return operationData.encode(), nil
+}
+
+// restartState follows a restart through the service being powered off and
+// powered back on. Being powered off is expected, so is not a failure.
+func (ap *AivenProvider) restartState(
+	ctx context.Context,
+	project, serviceName string,
+	service *aiven.Service,
+) (brokerapi.LastOperationState, string, error) {
+	_, requested := service.Tags[restartTag]
+	switch {
+	case service.State == aiven.PowerOff:
+		// The tag is removed first, so that a service left powered off by
+		// a failure to power it on is tried again, rather than reported as
+		// restarted.
+		tags := map[string]string{}
+		for key, value := range service.Tags {
+			if key != restartTag {
+				tags[key] = value
+			}
+		}
+		if requested {
+			err := ap.Client.UpdateServiceTags(ctx, &aiven.UpdateServiceTagsInput{
+				Project:     project,
+				ServiceName: serviceName,
+				Tags:        tags,
+			})
+			if err != nil {
+				return "", "", err
+			}
+		}
+		defer ap.invalidateCachedService(serviceName)
+		err := ap.Client.PowerService(ctx, &aiven.PowerServiceInput{
+			Project:     project,
+			ServiceName: serviceName,
+			Powered:     true,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		return brokerapi.InProgress, "Restarting the service: powering it back on", nil
+	case service.State == aiven.Running && requested:
+		return brokerapi.InProgress, "Restarting the service: powering it off", nil
+	}
+
+	state, description := providerStatesMapping(service.State)
+	if state == brokerapi.InProgress {
+		description = fmt.Sprintf("Restarting the service: %s", description)
+	}
+	return state, description, nil
+}
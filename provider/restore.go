@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pivotal-cf/brokerapi"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// latestBackup is the backup_name which restores the most recent backup,
+// which Aiven does when no backup is named.
+const latestBackup = "latest"
+
+// backupNameServiceTypes are the service types which Aiven can restore a
+// named backup of, rather than only the latest.
+var backupNameServiceTypes = []string{"pg", "opensearch"}
+
+// forkFrom validates the restore_from_instance and backup_name parameters,
+// and returns the user config which makes Aiven create the service from the
+// backup. The instance restored from must be in the project the new
+// instance is created in, as Aiven can only fork services within a project.
+func (ap *AivenProvider) forkFrom(ctx context.Context, config *Config, plan *Plan, instanceID, backupName string) (aiven.CommonUserConfig, error) {
+	if instanceID == "" {
+		return aiven.CommonUserConfig{}, invalidParameters(fmt.Errorf("backup_name can only be given with restore_from_instance"))
+	}
+	if backupName != "" && backupName != latestBackup && !contains(backupNameServiceTypes, aivenServiceType(plan.ServiceType)) {
+		return aiven.CommonUserConfig{}, invalidParameters(fmt.Errorf(
+			"backup_name is not supported by %s services, only the latest backup can be restored", plan.ServiceType,
+		))
+	}
+
+	source, err := ap.forkSource(ctx, config, plan, instanceID)
+	if err != nil {
+		return aiven.CommonUserConfig{}, err
+	}
+	return ap.forkUserConfig(ctx, config.ProjectForPlan(plan), source, instanceID, backupName)
+}
+
+// cloneFrom validates the clone_from parameter, and returns the user config
+// which makes Aiven create the service from the latest backup of the
+// instance. Unlike restore_from_instance, the instance must belong to the
+// organization, or with the same_space policy the space, the new instance
+// is being created in.
+func (ap *AivenProvider) cloneFrom(ctx context.Context, config *Config, plan *Plan, instanceID, organizationGUID, spaceGUID string) (aiven.CommonUserConfig, error) {
+	source, err := ap.forkSource(ctx, config, plan, instanceID)
+	if err != nil {
+		return aiven.CommonUserConfig{}, err
+	}
+	if err := config.checkClonePolicy(source, instanceID, organizationGUID, spaceGUID); err != nil {
+		return aiven.CommonUserConfig{}, err
+	}
+	return ap.forkUserConfig(ctx, config.ProjectForPlan(plan), source, instanceID, latestBackup)
+}
+
+// checkClonePolicy stops tenants cloning the data of instances which belong
+// to other organizations or spaces. Instances are identified by the tags
+// they were created with, so those without them cannot be cloned.
+func (c *Config) checkClonePolicy(source *aiven.Service, instanceID, organizationGUID, spaceGUID string) error {
+	allowed := organizationGUID != "" && source.Tags[organizationGUIDTag] == organizationGUID
+	if c.CloneSourcePolicy == CloneSourcePolicySameSpace {
+		allowed = allowed && spaceGUID != "" && source.Tags[spaceGUIDTag] == spaceGUID
+	}
+	if allowed {
+		return nil
+	}
+	return brokerapi.NewFailureResponse(
+		fmt.Errorf("Cannot clone instance %s: it is not in the same %s as the new instance", instanceID, c.clonePolicyScope()),
+		http.StatusForbidden,
+		"clone-not-allowed",
+	)
+}
+
+func (c *Config) clonePolicyScope() string {
+	if c.CloneSourcePolicy == CloneSourcePolicySameSpace {
+		return "space"
+	}
+	return "organization"
+}
+
+// forkSource finds the service of the instance a new instance is being
+// created from a backup of.
+func (ap *AivenProvider) forkSource(ctx context.Context, config *Config, plan *Plan, instanceID string) (*aiven.Service, error) {
+	sourceServiceName := buildServiceName(config.ServiceNamePrefix, instanceID)
+	services, err := ap.Client.ListServices(ctx, &aiven.ListServicesInput{Project: config.ProjectForPlan(plan)})
+	if err != nil {
+		return nil, err
+	}
+	var source *aiven.Service
+	for i := range services {
+		if services[i].ServiceName == sourceServiceName {
+			source = &services[i]
+			break
+		}
+	}
+	if source == nil {
+		return nil, invalidParameters(fmt.Errorf(
+			"Cannot restore from instance %s: it does not exist, or is in a different Aiven project to the plan's", instanceID,
+		))
+	}
+	if source.ServiceType != aivenServiceType(plan.ServiceType) {
+		return nil, invalidParameters(fmt.Errorf(
+			"Cannot restore from instance %s: it is a %s service, not %s", instanceID, source.ServiceType, aivenServiceType(plan.ServiceType),
+		))
+	}
+	return source, nil
+}
+
+// forkUserConfig checks the source has the backup, and returns the user
+// config which restores it.
+func (ap *AivenProvider) forkUserConfig(ctx context.Context, project string, source *aiven.Service, instanceID, backupName string) (aiven.CommonUserConfig, error) {
+	sourceServiceName := source.ServiceName
+	backups, err := ap.Client.ListServiceBackups(ctx, &aiven.ListServiceBackupsInput{
+		Project:     project,
+		ServiceName: sourceServiceName,
+	})
+	if err != nil {
+		return aiven.CommonUserConfig{}, err
+	}
+	if len(backups) == 0 {
+		return aiven.CommonUserConfig{}, invalidParameters(fmt.Errorf(
+			"Cannot restore from instance %s: it does not have any backups yet", instanceID,
+		))
+	}
+
+	userConfig := aiven.CommonUserConfig{ServiceToForkFrom: sourceServiceName}
+	if backupName == "" || backupName == latestBackup {
+		return userConfig, nil
+	}
+	for _, backup := range backups {
+		if backup.BackupName == backupName {
+			userConfig.RecoveryBasebackupName = backupName
+			return userConfig, nil
+		}
+	}
+	return aiven.CommonUserConfig{}, invalidParameters(fmt.Errorf(
+		"Cannot restore from instance %s: it does not have a backup named %s", instanceID, backupName,
+	))
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pivotal-cf/brokerapi"
+)
+
+const jsonSchemaDraft04 = "http://json-schema.org/draft-04/schema#"
+
+// ProvisionParameters are the parameters accepted when creating an instance.
+//
+// The catalog schemas are generated from the parameter structs. Fields are
+// described with `description` and `enum` tags, and a `service_types` tag
+// restricts a field to plans of the listed service types.
+type ProvisionParameters struct {
+	IPFilter              []string `json:"ip_filter,omitempty" description:"IP addresses and CIDR blocks which can connect to the instance, in addition to the platform's"`
+	IPFilterGroups        []string `json:"ip_filter_groups,omitempty" description:"Names of IP whitelist groups defined by the platform which can connect to the instance"`
+	Cloud                 string   `json:"cloud,omitempty" description:"Cloud and region to create the instance in, from those the platform allows. Defaults to the plan's"`
+	MaintenanceDOW        string   `json:"maintenance_dow,omitempty" enum:"monday,tuesday,wednesday,thursday,friday,saturday,sunday" description:"Day of the week on which Aiven can apply maintenance updates. Defaults to one Aiven picks"`
+	MaintenanceTime       string   `json:"maintenance_time,omitempty" description:"Time of day, in UTC and formatted as HH:MM:SS, from which Aiven can apply maintenance updates. Defaults to one Aiven picks"`
+	TerminationProtection *bool    `json:"termination_protection,omitempty" description:"Stop the instance being deleted until termination protection is turned off again with an update. Defaults to false"`
+	// RestoreFromInstance must be an instance of the same service type in
+	// the same Aiven project as the new instance.
+	RestoreFromInstance   string                  `json:"restore_from_instance,omitempty" description:"GUID of an instance, of the same type, to create the instance from a backup of"`
+	BackupName            string                  `json:"backup_name,omitempty" service_types:"pg,opensearch" description:"Name of the backup of restore_from_instance to restore. Defaults to latest, the most recent backup"`
+	CloneFrom             string                  `json:"clone_from,omitempty" description:"GUID of an instance, of the same type and in the same organization, to create the instance from the latest backup of"`
+	PublicAccess          *bool                   `json:"public_access,omitempty" description:"Give the instance public endpoints as well as private ones. Only plans in a project VPC support it, as other instances are always public"`
+	StaticIPs             bool                    `json:"static_ips,omitempty" description:"Give the instance static IP addresses, which are included in its bindings' credentials. Only some plans support them"`
+	UserConfig            map[string]interface{}  `json:"user_config,omitempty" description:"Aiven user config settings for the instance, from those the platform allows, which are merged over the plan's"`
+	AdditionalDiskSpaceGB *int                    `json:"additional_disk_space_gb,omitempty" description:"Disk space, in GB, to give the instance beyond its plan's, up to a maximum the plan sets. Only some plans support it"`
+	IndexPatterns         []IndexPatternParameter `json:"index_patterns,omitempty" service_types:"elasticsearch,opensearch" description:"Index retention rules, each keeping at most max_index_count indexes matching pattern and deleting the oldest"`
+	NotificationEmails    []string                `json:"notification_emails,omitempty" description:"Email addresses, at most 10, which Aiven sends notifications about the instance to, such as its disk filling up or maintenance being scheduled"`
+}
+
+// UpdateParameters are the parameters accepted when updating an instance.
+type UpdateParameters struct {
+	IPFilter              []string                `json:"ip_filter,omitempty" description:"IP addresses and CIDR blocks which can connect to the instance, in addition to the platform's. Replaces the current list when given"`
+	IPFilterGroups        []string                `json:"ip_filter_groups,omitempty" description:"Names of IP whitelist groups defined by the platform which can connect to the instance. Replaces the current list when given"`
+	Cloud                 string                  `json:"cloud,omitempty" description:"Cloud and region to migrate the instance to, from those the platform allows. Defaults to the cloud it was last given, or else the plan's"`
+	MaintenanceDOW        string                  `json:"maintenance_dow,omitempty" enum:"monday,tuesday,wednesday,thursday,friday,saturday,sunday" description:"Day of the week on which Aiven can apply maintenance updates. The current window is kept when it is omitted"`
+	MaintenanceTime       string                  `json:"maintenance_time,omitempty" description:"Time of day, in UTC and formatted as HH:MM:SS, from which Aiven can apply maintenance updates. The current window is kept when it is omitted"`
+	TerminationProtection *bool                   `json:"termination_protection,omitempty" description:"Stop the instance being deleted, or set to false to allow it again. The current setting is kept when it is omitted"`
+	UserConfig            map[string]interface{}  `json:"user_config,omitempty" description:"Aiven user config settings for the instance, from those the platform allows, which are merged over the plan's. Settings which are omitted are left as they are, unless the plan sets them"`
+	AdditionalDiskSpaceGB *int                    `json:"additional_disk_space_gb,omitempty" description:"Disk space, in GB, to give the instance beyond its plan's, up to a maximum the plan sets. Disks can grow but not shrink. The current disk is kept when it is omitted"`
+	IndexPatterns         []IndexPatternParameter `json:"index_patterns,omitempty" service_types:"elasticsearch,opensearch" description:"Index retention rules, each keeping at most max_index_count indexes matching pattern and deleting the oldest. Replaces the current rules when given, and clears them when omitted"`
+	NotificationEmails    *[]string               `json:"notification_emails,omitempty" description:"Email addresses, at most 10, which Aiven sends notifications about the instance to. Replaces the current list when given, and an empty list removes them"`
+	Version               string                  `json:"version,omitempty" service_types:"elasticsearch,kafka,opensearch,pg" description:"Engine version to upgrade the instance to, from those the plan allows. Aiven cannot downgrade instances. The current version is kept when it is omitted"`
+	ApplyMaintenance      bool                    `json:"apply_maintenance,omitempty" description:"Set to true to apply the instance's pending maintenance updates, such as security patches, now rather than in its maintenance window. Cannot be combined with other changes"`
+	Restart               bool                    `json:"restart,omitempty" description:"Set to true to restart the instance, by powering it off and back on. Cannot be combined with other changes"`
+	PublicAccess          *bool                   `json:"public_access,omitempty" description:"Set to true to give the instance public endpoints as well as private ones, or false to remove them. Only plans in a project VPC support it. The current setting is kept when it is omitted"`
+}
+
+// IndexPatternParameter is an index retention rule of the index_patterns
+// parameter.
+type IndexPatternParameter struct {
+	Pattern       string `json:"pattern" description:"Index name pattern, such as logs-*"`
+	MaxIndexCount int    `json:"max_index_count" description:"Most indexes matching the pattern to keep"`
+}
+
+// BindParameters are the parameters accepted when creating a binding.
+type BindParameters struct {
+	Permission  string `json:"permission,omitempty" enum:"read,readwrite,admin" service_types:"elasticsearch,opensearch" description:"Access the binding has to the instance's indexes. Defaults to admin, which is full access"`
+	IndexPrefix string `json:"index_prefix,omitempty" service_types:"elasticsearch,opensearch" description:"Prefix, or wildcard pattern, of the only indexes the binding can access"`
+	Access      string `json:"access,omitempty" enum:"api,dashboards" service_types:"elasticsearch,opensearch" description:"Set to dashboards for credentials which only log in to Kibana or OpenSearch Dashboards, with read access to the indexes. Defaults to api"`
+	Username    string `json:"username,omitempty" description:"Name of the binding's user, which is suffixed with part of a hash of the binding ID. Defaults to the binding ID"`
+	TTL         string `json:"ttl,omitempty" description:"How long the binding's credentials work for, such as 4h, after which its user is deleted"`
+	OwnDatabase bool   `json:"own_database,omitempty" service_types:"pg" description:"Create a database for the binding which only its user can access, rather than sharing defaultdb"`
+	Pooled      bool   `json:"pooled,omitempty" service_types:"pg" description:"Connect through a PgBouncer connection pool for the binding"`
+	PoolSize    *int   `json:"pool_size,omitempty" service_types:"pg" description:"Number of connections in the pool of a pooled binding. Defaults to 10"`
+	// CredentialType prometheus cannot be combined with the other parameters.
+	CredentialType string `json:"credential_type,omitempty" enum:"prometheus" description:"Set to prometheus for credentials to scrape the instance's metrics instead of a user"`
+}
+
+// PlanSchemas describes the parameters accepted by instances and bindings of
+// the plan.
+func (c *Config) PlanSchemas(serviceID, planID string) (*brokerapi.ServiceSchemas, error) {
+	plan, err := c.FindPlan(serviceID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	createSchema := parametersSchema(ProvisionParameters{}, plan.ServiceType)
+	updateSchema := parametersSchema(UpdateParameters{}, plan.ServiceType)
+	if len(c.UserConfigAllowList[plan.ServiceType]) == 0 {
+		delete(createSchema["properties"].(map[string]interface{}), "user_config")
+		delete(updateSchema["properties"].(map[string]interface{}), "user_config")
+	}
+	if c.DisableRestarts {
+		delete(updateSchema["properties"].(map[string]interface{}), "restart")
+	}
+	if version, ok := updateSchema["properties"].(map[string]interface{})["version"].(map[string]interface{}); ok {
+		if len(plan.AllowedVersions) == 0 {
+			delete(updateSchema["properties"].(map[string]interface{}), "version")
+		} else {
+			values := []interface{}{}
+			for _, allowed := range plan.allowedVersions() {
+				values = append(values, allowed)
+			}
+			version["enum"] = values
+		}
+	}
+
+	return &brokerapi.ServiceSchemas{
+		Instance: brokerapi.ServiceInstanceSchema{
+			Create: brokerapi.Schema{Parameters: createSchema},
+			Update: brokerapi.Schema{Parameters: updateSchema},
+		},
+		Binding: brokerapi.ServiceBindingSchema{
+			Create: brokerapi.Schema{Parameters: parametersSchema(BindParameters{}, plan.ServiceType)},
+		},
+	}, nil
+}
+
+func parametersSchema(parameters interface{}, serviceType string) map[string]interface{} {
+	schema := objectSchema(reflect.TypeOf(parameters), serviceType)
+	schema["$schema"] = jsonSchemaDraft04
+	return schema
+}
+
+func objectSchema(t reflect.Type, serviceType string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		// Parameters name service types as either the catalog or Aiven does.
+		if serviceTypes, ok := field.Tag.Lookup("service_types"); ok &&
+			!contains(strings.Split(serviceTypes, ","), serviceType) &&
+			!contains(strings.Split(serviceTypes, ","), aivenServiceType(serviceType)) {
+			continue
+		}
+
+		property := typeSchema(field.Type, serviceType)
+		if description, ok := field.Tag.Lookup("description"); ok {
+			property["description"] = description
+		}
+		if enum, ok := field.Tag.Lookup("enum"); ok {
+			values := []interface{}{}
+			for _, value := range strings.Split(enum, ",") {
+				values = append(values, value)
+			}
+			property["enum"] = values
+		}
+		properties[name] = property
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func typeSchema(t reflect.Type, serviceType string) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem(), serviceType),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem(), serviceType),
+		}
+	case reflect.Struct:
+		return objectSchema(t, serviceType)
+	case reflect.Interface:
+		return map[string]interface{}{}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
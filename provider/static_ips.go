@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// allocateStaticIPs creates the static IP addresses for a new instance of
+// the plan, in the cloud it is created in. Addresses are billed from when
+// they are created, so any already created are released if one fails.
+func (ap *AivenProvider) allocateStaticIPs(ctx context.Context, plan *Plan, project, cloud string) ([]string, error) {
+	if plan.StaticIPCount == 0 {
+		return nil, invalidParameters(fmt.Errorf("static_ips is not supported by the %s plan", plan.Name))
+	}
+	staticIPIDs := []string{}
+	for i := 0; i < plan.StaticIPCount; i++ {
+		staticIP, err := ap.Client.CreateStaticIP(ctx, &aiven.CreateStaticIPInput{
+			Project:   project,
+			CloudName: cloud,
+		})
+		if err != nil {
+			ap.releaseStaticIPs(ctx, project, staticIPIDs)
+			return nil, err
+		}
+		staticIPIDs = append(staticIPIDs, staticIP.StaticIPAddressID)
+	}
+	return staticIPIDs, nil
+}
+
+// releaseStaticIPs deletes static IP addresses which are no longer needed.
+// Failures are logged rather than returned, as the request which no longer
+// needs them has otherwise succeeded or failed already.
+func (ap *AivenProvider) releaseStaticIPs(ctx context.Context, project string, staticIPIDs []string) {
+	for _, staticIPID := range staticIPIDs {
+		err := ap.Client.DeleteStaticIP(ctx, &aiven.DeleteStaticIPInput{
+			Project:           project,
+			StaticIPAddressID: staticIPID,
+		})
+		if err != nil {
+			ap.Logger.Error("release-static-ips", err, lager.Data{
+				"project":      project,
+				"static-ip-id": staticIPID,
+			})
+		}
+	}
+}
+
+// serviceStaticIPs returns the static IP addresses associated with a
+// service.
+func (ap *AivenProvider) serviceStaticIPs(ctx context.Context, project, serviceName string) ([]aiven.StaticIP, error) {
+	staticIPs, err := ap.Client.ListStaticIPs(ctx, &aiven.ListStaticIPsInput{Project: project})
+	if err != nil {
+		return nil, err
+	}
+	serviceStaticIPs := []aiven.StaticIP{}
+	for _, staticIP := range staticIPs {
+		if staticIP.ServiceName == serviceName {
+			serviceStaticIPs = append(serviceStaticIPs, staticIP)
+		}
+	}
+	return serviceStaticIPs, nil
+}
+
+func staticIPIDs(staticIPs []aiven.StaticIP) []string {
+	ids := []string{}
+	for _, staticIP := range staticIPs {
+		ids = append(ids, staticIP.StaticIPAddressID)
+	}
+	return ids
+}
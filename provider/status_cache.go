@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+type cachedService struct {
+	service   *aiven.Service
+	fetchedAt time.Time
+}
+
+// pollService gets the instance's service for LastOperation. Cloud Foundry
+// polls every instance with an operation in progress every few seconds, so
+// when the config sets service_status_cache_seconds the service is reused
+// for that long, rather than fetched from Aiven on every poll. Errors are
+// not cached. The service is shared between polls, so must not be changed.
+func (ap *AivenProvider) pollService(ctx context.Context, project, serviceName string) (*aiven.Service, error) {
+	ttl := time.Duration(ap.currentConfig().ServiceStatusCacheSeconds) * time.Second
+	var generation uint64
+	if ttl > 0 {
+		ap.serviceCacheLock.Lock()
+		cached, ok := ap.serviceCache[serviceName]
+		generation = ap.serviceCacheGeneration
+		ap.serviceCacheLock.Unlock()
+		if ok && ap.now().Before(cached.fetchedAt.Add(ttl)) {
+			return cached.service, nil
+		}
+	}
+
+	fetchedAt := ap.now()
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil || ttl == 0 {
+		return service, err
+	}
+
+	ap.serviceCacheLock.Lock()
+	defer ap.serviceCacheLock.Unlock()
+	if ap.serviceCache == nil {
+		ap.serviceCache = map[string]cachedService{}
+	}
+	// A service fetched while an operation was changing it may be out of
+	// date, so is not kept.
+	if generation == ap.serviceCacheGeneration {
+		ap.serviceCache[serviceName] = cachedService{service: service, fetchedAt: fetchedAt}
+	}
+	return service, nil
+}
+
+// invalidateCachedService forgets the service, so that the next poll sees
+// the changes an operation has made to it.
+func (ap *AivenProvider) invalidateCachedService(serviceName string) {
+	ap.serviceCacheLock.Lock()
+	defer ap.serviceCacheLock.Unlock()
+	delete(ap.serviceCache, serviceName)
+	ap.serviceCacheGeneration++
+}
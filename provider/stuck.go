@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-cf/brokerapi"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// stuckProvision fails a provision whose service has not started running
+// by its timeout, such as when Aiven is short of capacity in the cloud, and
+// deletes the service if the config asks for it.
+func (ap *AivenProvider) stuckProvision(
+	ctx context.Context,
+	lastOperationData LastOperationData,
+	serviceName string,
+	operationData OperationData,
+) (brokerapi.LastOperationState, string, error) {
+	description := fmt.Sprintf("Last operation failed: service did not start running within %s", operationData.timeout())
+	if !ap.currentConfig().DeleteStuckProvisions {
+		return brokerapi.Failed, description, nil
+	}
+
+	project := ap.projectForInstance(lastOperationData.ServiceID, lastOperationData.PlanID)
+	deleted, err := ap.deleteStuckService(ctx, project, serviceName)
+	if err != nil {
+		// The provision has failed either way. The service is left for the
+		// tenant to delete, or for the platform operators to clean up.
+		ap.Logger.Error("delete-stuck-provision", err, lager.Data{
+			"project": project,
+			"service": serviceName,
+		})
+		return brokerapi.Failed, description, nil
+	}
+	if !deleted {
+		state, description := providerStatesMapping(aiven.Running)
+		return state, description, nil
+	}
+	ap.Logger.Info("deleted-stuck-provision", lager.Data{
+		"project": project,
+		"service": serviceName,
+	})
+	return brokerapi.Failed, description + ", so it has been deleted", nil
+}
+
+// timedOutOperation fails an update or deprovision which has not finished by
+// its timeout, such as when the service is stuck rebuilding, so that Cloud
+// Foundry stops polling it. Unlike stuck provisions, the service is left as
+// it is, as it may hold data the platform operators need to look at.
+func (ap *AivenProvider) timedOutOperation(
+	serviceName string,
+	operationData OperationData,
+	description string,
+) (brokerapi.LastOperationState, string) {
+	ap.Logger.Info("operation-timed-out", lager.Data{
+		"service":     serviceName,
+		"operation":   operationData.Operation,
+		"started-at":  operationData.StartedAt,
+		"description": description,
+	})
+	return brokerapi.Failed, fmt.Sprintf(
+		"Last operation failed: %s did not finish within %s (%s). The service has been left for the platform operators to investigate",
+		operationData.Operation, operationData.timeout(), description,
+	)
+}
+
+// deleteStuckService deletes a service which has not started running, and
+// releases its static IPs. It reports false without deleting the service if
+// it has started running after all.
+func (ap *AivenProvider) deleteStuckService(ctx context.Context, project, serviceName string) (bool, error) {
+	staticIPs, err := ap.serviceStaticIPs(ctx, project, serviceName)
+	if err != nil {
+		return false, err
+	}
+
+	// The service may have started running since its state was last
+	// fetched, so it is checked again immediately before it is deleted.
+	service, err := ap.Client.GetService(ctx, &aiven.GetServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return false, err
+	}
+	if service.State == aiven.Running {
+		return false, nil
+	}
+
+	err = ap.Client.DeleteService(ctx, &aiven.DeleteServiceInput{
+		Project:     project,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return false, err
+	}
+	ap.releaseStaticIPs(ctx, project, staticIPIDs(staticIPs))
+	return true, nil
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// BindingReaperInterval is how often the users of expired bindings are
+// looked for.
+const BindingReaperInterval = 5 * time.Minute
+
+// The users of bindings with a ttl have their expiry, in Unix seconds, in
+// their name. Aiven does not keep anything else about users, and the reaper
+// only has the users to go on.
+var expiringUsernamePattern = regexp.MustCompile(fmt.Sprintf(`-exp([0-9]+)-[0-9a-f]{%d}$`, customUsernameHashLength))
+
+func (ap *AivenProvider) now() time.Time {
+	if ap.Clock != nil {
+		return ap.Clock()
+	}
+	return time.Now()
+}
+
+// parseTTL reads the ttl bind parameter, which is a duration such as 4h.
+// Bindings without one do not expire.
+func parseTTL(ttl string) (time.Duration, error) {
+	if ttl == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(ttl)
+	if err != nil || duration <= 0 {
+		return 0, invalidParameters(fmt.Errorf("Invalid ttl: %s, must be a positive duration such as 4h", ttl))
+	}
+	return duration, nil
+}
+
+// expiringServiceUsername names the user of a binding with a ttl. It is
+// named after the username the binding asked for, or otherwise its binding
+// ID, shortened if need be to leave room for the expiry.
+func expiringServiceUsername(username, bindingID string, expiry time.Time) string {
+	if username == "" {
+		username = serviceUsername(bindingID)
+	}
+	suffix := fmt.Sprintf("-exp%d%s", expiry.Unix(), customUsernameSuffix(bindingID))
+	if len(username)+len(suffix) > maxServiceUsernameLength {
+		username = strings.TrimRight(username[:maxServiceUsernameLength-len(suffix)], "._-")
+	}
+	return username + suffix
+}
+
+// usernameExpiry returns when the user of a binding with a ttl expires.
+func usernameExpiry(username string) (time.Time, bool) {
+	match := expiringUsernamePattern.FindStringSubmatch(username)
+	if match == nil {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// RunBindingReaper deletes the users of expired bindings every interval
+// until the context is done.
+func (ap *AivenProvider) RunBindingReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := ap.ReapExpiredBindings(ctx); err != nil {
+				ap.Logger.Error("reap-expired-bindings", err)
+			}
+		}
+	}
+}
+
+// ReapExpiredBindings deletes the users of bindings whose ttl has passed, in
+// every service of the broker, and returns how many it deleted. The bindings
+// themselves are left for the platform to unbind.
+func (ap *AivenProvider) ReapExpiredBindings(ctx context.Context) (int, error) {
+	config := ap.currentConfig()
+	now := ap.now()
+
+	reaped := 0
+	var firstErr error
+	for _, project := range config.projects() {
+		services, err := ap.Client.ListServices(ctx, &aiven.ListServicesInput{Project: project})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		for _, service := range services {
+			if ctx.Err() != nil {
+				return reaped, ctx.Err()
+			}
+			if !strings.HasPrefix(service.ServiceName, strings.ToLower(config.ServiceNamePrefix+"-")) {
+				continue
+			}
+
+			n, err := ap.reapServiceUsers(ctx, project, service, now)
+			reaped += n
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return reaped, firstErr
+}
+
+func (ap *AivenProvider) reapServiceUsers(ctx context.Context, project string, service aiven.Service, now time.Time) (int, error) {
+	users, err := ap.Client.ListServiceUsers(ctx, &aiven.ListServiceUsersInput{
+		Project:     project,
+		ServiceName: service.ServiceName,
+	})
+	if err == aiven.ErrServiceDoesNotExist {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	reaped := 0
+	for _, user := range users {
+		expiry, ok := usernameExpiry(user.Username)
+		if !ok || expiry.After(now) {
+			continue
+		}
+
+		if err := ap.deletePostgresBinding(ctx, project, service.ServiceName, &service, user.Username); err != nil {
+			return reaped, err
+		}
+		if supportsACLs(service.ServiceType) {
+			err := ap.revokeACL(ctx, project, service.ServiceName, service.ServiceType, user.Username)
+			if err != nil && err != aiven.ErrServiceDoesNotExist {
+				return reaped, err
+			}
+		}
+		_, err := ap.Client.DeleteServiceUser(ctx, &aiven.DeleteServiceUserInput{
+			Project:     project,
+			ServiceName: service.ServiceName,
+			Username:    user.Username,
+		})
+		if err == aiven.ErrServiceUserDoesNotExist || err == aiven.ErrInstanceDoesNotExist {
+			continue
+		}
+		if err != nil {
+			return reaped, err
+		}
+
+		ap.Logger.Info("reap-expired-binding", lager.Data{
+			"service":  service.ServiceName,
+			"username": user.Username,
+			"expiry":   expiry.UTC().Format(time.RFC3339),
+		})
+		reaped++
+	}
+	return reaped, nil
+}
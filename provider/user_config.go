@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alphagov/paas-aiven-broker/provider/aiven"
+)
+
+// managedUserConfigKeys are the user config keys the broker sets itself,
+// such as the IP filter and engine version, which neither the config nor
+// tenants can override.
+var managedUserConfigKeys = aiven.UserConfig{}.ExplicitKeys()
+
+// checkUserConfigAllowList rejects allow-lists which name unknown service
+// types or keys the broker manages.
+func checkUserConfigAllowList(allowList map[string][]string) error {
+	for serviceType, paths := range allowList {
+		if !knownServiceTypes[serviceType] {
+			return fmt.Errorf("Config error: user_config_allow_list has unknown service type %s", serviceType)
+		}
+		for _, path := range paths {
+			if contains(managedUserConfigKeys, strings.Split(path, ".")[0]) {
+				return fmt.Errorf(
+					"Config error: user_config_allow_list for %s cannot include %s, as the broker manages it", serviceType, path,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// tenantUserConfig validates the user_config parameter against the
+// allow-list for the service type. Every key in it must be, or be inside,
+// an allow-listed path.
+func (c *Config) tenantUserConfig(serviceType string, userConfig map[string]interface{}) (map[string]interface{}, error) {
+	if err := checkUserConfigPaths(c.UserConfigAllowList[serviceType], "", userConfig); err != nil {
+		return nil, err
+	}
+	return userConfig, nil
+}
+
+func checkUserConfigPaths(allowList []string, prefix string, userConfig map[string]interface{}) error {
+	keys := []string{}
+	for key := range userConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := prefix + key
+		if prefix == "" && contains(managedUserConfigKeys, key) {
+			return invalidParameters(fmt.Errorf("Invalid user_config: %s is managed by the broker, so cannot be set", path))
+		}
+		if userConfigPathAllowed(allowList, path) {
+			continue
+		}
+		if nested, ok := userConfig[key].(map[string]interface{}); ok {
+			if err := checkUserConfigPaths(allowList, path+".", nested); err != nil {
+				return err
+			}
+			continue
+		}
+		return invalidParameters(fmt.Errorf("Invalid user_config: %s is not one of the settings the platform allows", path))
+	}
+	return nil
+}
+
+// userConfigPathAllowed reports whether the path, such as
+// elasticsearch.action_destructive_requires_name, is allow-listed, either
+// itself or as part of an object which is.
+func userConfigPathAllowed(allowList []string, path string) bool {
+	for _, allowed := range allowList {
+		if path == allowed || strings.HasPrefix(path, allowed+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeUserConfig deep merges the overrides into a copy of the base user
+// config, so objects such as pg are merged key by key rather than replaced.
+func mergeUserConfig(base, overrides map[string]interface{}) map[string]interface{} {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := map[string]interface{}{}
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		baseObject, baseIsObject := merged[key].(map[string]interface{})
+		overrideObject, overrideIsObject := value.(map[string]interface{})
+		if baseIsObject && overrideIsObject {
+			merged[key] = mergeUserConfig(baseObject, overrideObject)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}